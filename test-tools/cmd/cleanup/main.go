@@ -0,0 +1,174 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pulsar-local-lab/perf-test/internal/config"
+	"github.com/pulsar-local-lab/perf-test/internal/pulsar"
+	"github.com/streamnative/pulsar-admin-go/pkg/utils"
+)
+
+const (
+	appName    = "Pulsar Performance Test Cleanup"
+	appVersion = "1.0.0"
+)
+
+// Command-line flags
+var (
+	configFile   = flag.String("config", "", "Path to configuration file (JSON, YAML or TOML)")
+	adminURL     = flag.String("admin-url", "", "Pulsar admin API URL (overrides config)")
+	topic        = flag.String("topic", "", "Base topic name without run suffix (overrides config)")
+	subscription = flag.String("subscription", "", "Base subscription name without run suffix (overrides config)")
+	runID        = flag.String("run-id", "", "Delete the topic and subscription of this run")
+	olderThan    = flag.Duration("older-than", 0, "Delete run topics started longer ago than this (e.g. 24h)")
+	namespace    = flag.String("namespace", "", "Namespace to sweep with --older-than (default: namespace of the topic)")
+	dryRun       = flag.Bool("dry-run", false, "List what would be deleted without deleting anything")
+	showHelp     = flag.Bool("help", false, "Show help message")
+	version      = flag.Bool("version", false, "Show version information")
+)
+
+func main() {
+	// Parse command-line flags
+	flag.Usage = printUsage
+	flag.Parse()
+
+	// Handle special flags
+	if *version {
+		fmt.Printf("%s v%s\n", appName, appVersion)
+		os.Exit(0)
+	}
+
+	if *showHelp || (*runID == "" && *olderThan <= 0) {
+		printUsage()
+		os.Exit(0)
+	}
+
+	cfg, err := loadConfiguration()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *runID != "" {
+		if err := cleanupRun(cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "Cleanup failed: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *olderThan > 0 {
+		if err := sweepRuns(cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "Sweep failed: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// loadConfiguration loads configuration from file or defaults and applies CLI overrides
+func loadConfiguration() (*config.Config, error) {
+	cfg, err := config.LoadConfig(*configFile, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if *adminURL != "" {
+		cfg.Pulsar.AdminURL = *adminURL
+	}
+	if *topic != "" {
+		cfg.Pulsar.Topic = *topic
+	}
+	if *subscription != "" {
+		cfg.Consumer.SubscriptionName = *subscription
+	}
+	return cfg, nil
+}
+
+// cleanupRun deletes the topics (with all subscriptions and backlog) of a single run
+func cleanupRun(cfg *config.Config) error {
+	cfg.Pulsar.RunID = *runID
+	if err := cfg.ApplyRunID(); err != nil {
+		return err
+	}
+
+	if *dryRun {
+		fmt.Printf("Would delete topic %s\n", strings.Join(cfg.Pulsar.TopicNames(), ", "))
+		return nil
+	}
+
+	if err := pulsar.CleanupRun(cfg, true); err != nil {
+		return err
+	}
+	fmt.Printf("Deleted run %s (topic %s)\n", cfg.Pulsar.RunID, strings.Join(cfg.Pulsar.TopicNames(), ", "))
+	return nil
+}
+
+// sweepRuns deletes every run topic in the namespace that is older than --older-than
+func sweepRuns(cfg *config.Config) error {
+	ns := *namespace
+	if ns == "" {
+		topicName, err := utils.GetTopicName(cfg.Pulsar.Topic)
+		if err != nil {
+			return fmt.Errorf("invalid topic name %s: %w", cfg.Pulsar.Topic, err)
+		}
+		ns = fmt.Sprintf("%s/%s", topicName.GetTenant(), topicName.GetNamespace())
+	}
+
+	admin, err := pulsar.NewAdminClient(cfg.Pulsar.AdminURL)
+	if err != nil {
+		return err
+	}
+
+	runs, err := admin.FindRunTopics(ns, *olderThan)
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		fmt.Printf("No run topics older than %v in %s\n", *olderThan, ns)
+		return nil
+	}
+
+	var failed []string
+	for _, run := range runs {
+		age := time.Since(run.Started).Truncate(time.Minute)
+		if *dryRun {
+			fmt.Printf("Would delete %s (started %v ago)\n", run.Topic, age)
+			continue
+		}
+
+		topicName, err := utils.GetTopicName(run.Topic)
+		if err == nil {
+			err = admin.DeleteTopic(topicName, run.Partitioned)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to delete %s: %v\n", run.Topic, err)
+			failed = append(failed, run.Topic)
+			continue
+		}
+		fmt.Printf("Deleted %s (started %v ago)\n", run.Topic, age)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to delete %d topic(s): %s", len(failed), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// printUsage prints usage information
+func printUsage() {
+	fmt.Fprintf(os.Stderr, "%s v%s\n\n", appName, appVersion)
+	fmt.Fprintf(os.Stderr, "USAGE:\n")
+	fmt.Fprintf(os.Stderr, "  %s [options]\n\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "OPTIONS:\n")
+	flag.PrintDefaults()
+	fmt.Fprintf(os.Stderr, "\nEXAMPLES:\n")
+	fmt.Fprintf(os.Stderr, "  # Delete the topic, subscriptions and backlog of one run\n")
+	fmt.Fprintf(os.Stderr, "  %s --run-id 20250101120000-3fa2\n\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  # Preview which run topics older than a day would be removed\n")
+	fmt.Fprintf(os.Stderr, "  %s --older-than 24h --dry-run\n\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  # Sweep stale run topics from another namespace\n")
+	fmt.Fprintf(os.Stderr, "  %s --older-than 1h --namespace perf/load\n", os.Args[0])
+}
@@ -2,17 +2,19 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"os/signal"
-	"path/filepath"
 	"syscall"
 	"time"
 
 	"github.com/pulsar-local-lab/perf-test/internal/config"
+	"github.com/pulsar-local-lab/perf-test/internal/metrics"
+	"github.com/pulsar-local-lab/perf-test/internal/pulsar"
 	"github.com/pulsar-local-lab/perf-test/internal/ui"
 	"github.com/pulsar-local-lab/perf-test/internal/worker"
 )
@@ -24,19 +26,35 @@ const (
 
 // Command-line flags
 var (
-	configFile       = flag.String("config", "", "Path to configuration file (JSON)")
-	profile          = flag.String("profile", "default", "Performance test profile (default, low-latency, high-throughput, burst, sustained)")
-	serviceURL       = flag.String("service-url", "", "Pulsar broker service URL (overrides config)")
-	topic            = flag.String("topic", "", "Pulsar topic name (overrides config)")
-	partitions       = flag.Int("partitions", -1, "Number of topic partitions (overrides config, -1=use config, 0=non-partitioned)")
-	subscription     = flag.String("subscription", "", "Subscription name (overrides config)")
-	subscriptionType = flag.String("subscription-type", "", "Subscription type: Exclusive, Shared, Failover, KeyShared (overrides config)")
-	numWorkers       = flag.Int("workers", 0, "Number of consumer workers (overrides config, 0=use config)")
-	showHelp         = flag.Bool("help", false, "Show help message")
-	listProfs        = flag.Bool("list-profiles", false, "List available performance profiles")
-	version          = flag.Bool("version", false, "Show version information")
+	configFile   = flag.String("config", "", "Path to configuration file (JSON, YAML or TOML)")
+	profile      = flag.String("profile", "default", "Performance test profile (built-in or custom, see --list-profiles)")
+	scenarioFile = flag.String("scenario", "", "Run a multi-phase scenario file (YAML or JSON) and quit when it completes")
+	printConfig  = flag.Bool("print-config", false, "Print the effective configuration with the source of each value and exit")
+	showHelp     = flag.Bool("help", false, "Show help message")
+	listProfs    = flag.Bool("list-profiles", false, "List available performance profiles")
+	version      = flag.Bool("version", false, "Show version information")
+
+	// overrides holds --<section>.<field> flags for every config setting and the shorthands below
+	overrides = config.RegisterFlags(flag.CommandLine)
 )
 
+func init() {
+	overrides.Alias("service-url", "pulsar.service_url", "Pulsar broker service URL (shorthand for --pulsar.service-url)")
+	overrides.Alias("topic", "pulsar.topic", "Pulsar topic name (shorthand for --pulsar.topic)")
+	overrides.Alias("partitions", "pulsar.topic_partitions", "Number of topic partitions, 0=non-partitioned (shorthand for --pulsar.topic-partitions)")
+	overrides.Alias("recreate-topic", "pulsar.recreate_topic", "Delete and recreate the topic on startup (drops subscriptions and backlog)")
+	overrides.Alias("run-id", "pulsar.run_id", "Isolate the run by suffixing topic and subscription with a run ID (\"auto\" generates one)")
+	overrides.Alias("cleanup", "pulsar.cleanup_on_exit", "Delete the run subscription on exit (requires --run-id)")
+	overrides.Alias("subscription", "consumer.subscription_name", "Subscription name (shorthand for --consumer.subscription-name)")
+	overrides.Alias("subscription-type", "consumer.subscription_type", "Subscription type: Exclusive, Shared, Failover, KeyShared")
+	overrides.Alias("workers", "consumer.num_consumers", "Number of consumer workers (shorthand for --consumer.num-consumers)")
+	overrides.Alias("mode", "consumer.mode", "Consumer mode: subscription, or reader to read without a subscription")
+	overrides.Alias("start", "consumer.reader_start", "Reader start: earliest, latest, <ledger>:<entry>[:<partition>], RFC 3339 time or duration ago")
+	overrides.Alias("backlog-messages", "consumer.backlog_messages", "Messages to pre-fill before consuming in backlog mode")
+	overrides.Alias("backlog-duration", "consumer.backlog_duration", "How long to pre-fill before consuming in backlog mode, e.g. 5m")
+	overrides.Alias("seek-to", "consumer.seek_to", "Backlog mode seek position: earliest, <ledger>:<entry>, RFC 3339 time or duration ago")
+}
+
 func main() {
 	// Parse command-line flags
 	flag.Usage = printUsage
@@ -48,6 +66,12 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Load custom profiles so they can be selected and listed
+	if err := config.LoadProfiles(config.ProfileDir()); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load profiles: %v\n", err)
+		os.Exit(1)
+	}
+
 	if *showHelp {
 		printUsage()
 		os.Exit(0)
@@ -96,22 +120,47 @@ func main() {
 		os.Stderr = origStderr
 	}()
 
-	// Load configuration
-	cfg, err := loadConfiguration()
+	// Resolve configuration: defaults, profile, config file, environment, flags
+	cfg, origins, err := loadConfiguration()
+
+	// Show the effective configuration and where each value came from, even when it is
+	// invalid, so the layer that set a bad value can be found
+	if *printConfig && cfg != nil {
+		config.PrintConfig(origStdout, cfg, origins)
+	}
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		fmt.Fprintf(origStderr, "Failed to load configuration: %v\n", err)
 		os.Exit(1)
 	}
+	if *printConfig {
+		os.Exit(0)
+	}
 
-	// Apply CLI overrides
-	applyOverrides(cfg)
+	// Isolate the run on its own topic and subscription if requested
+	if err := cfg.ApplyRunID(); err != nil {
+		fmt.Fprintf(origStderr, "Invalid configuration: %v\n", err)
+		os.Exit(1)
+	}
+	if cfg.Pulsar.RunID != "" {
+		log.Printf("Run ID: %s (topic: %s)", cfg.Pulsar.RunID, cfg.Pulsar.Topic)
+	}
 
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
-		fmt.Fprintf(os.Stderr, "Invalid configuration: %v\n", err)
+		fmt.Fprintf(origStderr, "Invalid configuration: %v\n", err)
 		os.Exit(1)
 	}
 
+	// Load the scenario before connecting so mistakes in the file fail fast
+	var scenario *config.Scenario
+	if *scenarioFile != "" {
+		scenario, err = config.LoadScenario(*scenarioFile)
+		if err != nil {
+			fmt.Fprintf(origStderr, "Invalid scenario: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// Create context with cancellation
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -150,107 +199,168 @@ func main() {
 		fmt.Fprintf(os.Stderr, "    → Or run: ./scripts/access-ui.sh\n\n")
 		os.Exit(1)
 	}
+
+	// Pre-fill a backlog and move the subscription back so consumers replay it
+	var prefill *worker.PrefillResult
+	if cfg.Consumer.BacklogMode() {
+		if prefill, err = prepareBacklog(ctx, pool, cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "\n❌ ERROR: %v\n", err)
+			os.Exit(1)
+		}
+	}
 	// Restore redirection for TUI
 	os.Stderr = stderrWriter
 
+	// Run the scenario in the background; the UI closes when it completes. Consumer
+	// scenarios only change the number of consumers.
+	uiCtx, stopUI := context.WithCancel(ctx)
+	defer stopUI()
+	var runner *worker.ScenarioRunner
+	scenarioDone := make(chan error, 1)
+	if scenario != nil {
+		runner, err = worker.NewScenarioRunner(scenario, nil, pool)
+		if err != nil {
+			fmt.Fprintf(origStderr, "Invalid scenario: %v\n", err)
+			os.Exit(1)
+		}
+		go func() {
+			_, err := runner.Run(uiCtx)
+			scenarioDone <- err
+			stopUI()
+		}()
+	}
+
 	// Start the interactive UI with log buffer (blocks until quit)
-	_ = ui.RunConsumerUI(ctx, pool, logBuffer)
+	_ = ui.RunConsumerUI(uiCtx, pool, logBuffer)
+
+	// Wait for the scenario to stop changing the pool before shutting it down
+	stopUI()
+	if runner != nil {
+		if err := <-scenarioDone; err != nil && !errors.Is(err, context.Canceled) {
+			fmt.Fprintf(origStderr, "Scenario failed: %v\n", err)
+		}
+	}
 
 	// Graceful shutdown (silent - TUI has been stopped)
 	_ = pool.Stop()
 
+	// Report where received messages were captured
+	if capture := pool.Capture(); capture != nil {
+		stats := capture.Stats()
+		fmt.Fprintf(origStdout, "Captured %d messages (%d skipped) to %s: %d file(s), %.2f MB\n",
+			stats.Captured, stats.Skipped, cfg.Consumer.CaptureFile, stats.Files, float64(stats.Bytes)/(1024*1024))
+	}
+
+	// Report how fast readers caught up on existing messages, or consumers drained the
+	// backlog of the last seek
+	if catchUp := pool.GetMetrics().GetSnapshot().CatchUp; catchUp.Readers > 0 {
+		workers := "consumers"
+		if cfg.Consumer.ReaderMode() {
+			workers = "readers"
+		}
+		if catchUp.Done() {
+			fmt.Fprintf(origStdout, "All %s caught up on %d messages in %v (%.0f msg/s, read wait p99 %.2f ms)\n",
+				workers, catchUp.Messages, catchUp.Duration.Round(time.Millisecond), catchUp.Rate, catchUp.ReadWait.P99)
+		} else {
+			fmt.Fprintf(origStdout, "%d of %d %s caught up: read %d messages in %v (%.0f msg/s)\n",
+				catchUp.CaughtUp, catchUp.Readers, workers, catchUp.Messages, catchUp.Duration.Round(time.Millisecond), catchUp.Rate)
+		}
+	}
+
+	// Report corrupt payloads, which are not counted as failures
+	if checksums := pool.GetMetrics().GetSnapshot().Checksums; checksums.Verified > 0 {
+		fmt.Fprintf(origStdout, "Verified %d payload checksums: %d corrupt\n", checksums.Verified, checksums.Corrupt)
+	}
+
 	// Export metrics if enabled
 	if cfg.Metrics.ExportEnabled {
-		_ = exportMetrics(pool, cfg)
+		_ = exportMetrics(pool, cfg, prefill)
+	}
+	// Report per-phase metrics of the scenario
+	if runner != nil {
+		results := runner.Results()
+		worker.WriteScenarioSummary(origStdout, results)
+		if cfg.Metrics.ExportEnabled {
+			if filename, err := worker.ExportScenarioReport(cfg.Metrics.ExportPath, scenario, results); err != nil {
+				fmt.Fprintf(origStderr, "Failed to export scenario report: %v\n", err)
+			} else {
+				fmt.Fprintf(origStdout, "Scenario report: %s\n", filename)
+			}
+		}
+	}
+	// Remove the run's subscription if requested (readers leave none behind)
+	if cfg.Pulsar.CleanupOnExit && !cfg.Consumer.ReaderMode() {
+		if err := pulsar.CleanupRun(cfg, false); err != nil {
+			fmt.Fprintf(origStderr, "Cleanup failed: %v\n", err)
+		}
 	}
 }
 
-// loadConfiguration loads configuration from file or uses profile
-func loadConfiguration() (*config.Config, error) {
+// loadConfiguration resolves the configuration from all layers
+func loadConfiguration() (*config.Config, config.Origins, error) {
 	if *configFile != "" {
 		log.Printf("Loading configuration from file: %s", *configFile)
-		return config.LoadConfig(*configFile, "")
 	}
-
 	log.Printf("Using profile: %s", *profile)
-	return config.LoadConfig("", *profile)
+	return config.Load(config.LoadOptions{
+		Profile: *profile,
+		File:    *configFile,
+		Flags:   overrides,
+	})
 }
 
-// applyOverrides applies command-line overrides to configuration
-func applyOverrides(cfg *config.Config) {
-	if *serviceURL != "" {
-		log.Printf("Overriding service URL: %s", *serviceURL)
-		cfg.Pulsar.ServiceURL = *serviceURL
-	}
-
-	if *topic != "" {
-		log.Printf("Overriding topic: %s", *topic)
-		cfg.Pulsar.Topic = *topic
+// prepareBacklog produces the configured backlog, if any, then seeks the subscription to
+// the configured position. It returns the pre-fill result, or nil when nothing was produced.
+func prepareBacklog(ctx context.Context, pool *worker.Pool, cfg *config.Config) (*worker.PrefillResult, error) {
+	var prefill *worker.PrefillResult
+	if cfg.Consumer.Prefill() {
+		result, err := worker.Prefill(ctx, cfg, func(sent uint64) {
+			fmt.Fprintf(os.Stderr, "\rPre-filling backlog: %d messages", sent)
+		})
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to pre-fill backlog: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Pre-filled %d messages (%.2f MB) in %v\n",
+			result.Messages, float64(result.Bytes)/(1024*1024), result.Duration.Round(time.Millisecond))
+		prefill = &result
 	}
 
-	if *partitions >= 0 {
-		log.Printf("Overriding topic partitions: %d", *partitions)
-		cfg.Pulsar.TopicPartitions = *partitions
-	}
-
-	if *subscription != "" {
-		log.Printf("Overriding subscription: %s", *subscription)
-		cfg.Consumer.SubscriptionName = *subscription
-	}
-
-	if *subscriptionType != "" {
-		log.Printf("Overriding subscription type: %s", *subscriptionType)
-		cfg.Consumer.SubscriptionType = *subscriptionType
+	start, err := config.ParseSeekPosition(cfg.Consumer.SeekTo, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("invalid seek position: %w", err)
 	}
-
-	if *numWorkers > 0 {
-		log.Printf("Overriding worker count: %d", *numWorkers)
-		cfg.Consumer.NumConsumers = *numWorkers
+	if err := pool.Seek(start); err != nil {
+		return nil, fmt.Errorf("failed to seek subscription to %s: %w", cfg.Consumer.SeekTo, err)
 	}
+	log.Printf("Subscription moved to %s", cfg.Consumer.SeekTo)
+	return prefill, nil
 }
 
 // exportMetrics exports final metrics to file
-func exportMetrics(pool *worker.Pool, cfg *config.Config) error {
-	// Create export directory if it doesn't exist
-	if err := os.MkdirAll(cfg.Metrics.ExportPath, 0755); err != nil {
-		return fmt.Errorf("failed to create export directory: %w", err)
+func exportMetrics(pool *worker.Pool, cfg *config.Config, prefill *worker.PrefillResult) error {
+	opts := metrics.ReportOptions{
+		Tool:            "consumer",
+		Receive:         true,
+		RunID:           cfg.Pulsar.RunID,
+		Topic:           cfg.Pulsar.Topic,
+		GroupByProperty: cfg.Consumer.GroupByProperty,
 	}
-
-	// Generate filename with timestamp
-	timestamp := time.Now().Format("20060102-150405")
-	filename := filepath.Join(cfg.Metrics.ExportPath, fmt.Sprintf("consumer-metrics-%s.json", timestamp))
-
-	// Export metrics (this would call a method on the collector)
-	snapshot := pool.GetMetrics().GetSnapshot()
-
-	// Write snapshot to file (simplified - real implementation would serialize to JSON)
-	file, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("failed to create metrics file: %w", err)
+	if cfg.Consumer.ReaderMode() {
+		opts.Mode = cfg.Consumer.Mode
+		opts.ReaderStart = cfg.Consumer.ReaderStart
+	}
+	if cfg.Consumer.BacklogMode() {
+		opts.Mode = cfg.Consumer.Mode
+		opts.SeekTo = cfg.Consumer.SeekTo
+	}
+	if prefill != nil {
+		opts.Prefill = prefill.Report()
 	}
-	defer file.Close()
-
-	// Calculate throughput in Mbps
-	throughputMbps := float64(snapshot.BytesReceived) / snapshot.Elapsed.Seconds() / 1024 / 1024 * 8
 
-	// Write summary
-	fmt.Fprintf(file, "{\n")
-	fmt.Fprintf(file, "  \"timestamp\": \"%s\",\n", time.Now().Format(time.RFC3339))
-	fmt.Fprintf(file, "  \"duration\": \"%v\",\n", snapshot.Elapsed)
-	fmt.Fprintf(file, "  \"total_messages\": %d,\n", snapshot.MessagesReceived)
-	fmt.Fprintf(file, "  \"total_bytes\": %d,\n", snapshot.BytesReceived)
-	fmt.Fprintf(file, "  \"receive_rate\": %.2f,\n", snapshot.Throughput.ReceiveRate)
-	fmt.Fprintf(file, "  \"throughput_mbps\": %.2f,\n", throughputMbps)
-	if snapshot.LatencyStats.Count > 0 {
-		fmt.Fprintf(file, "  \"latency_p50\": %.2f,\n", snapshot.LatencyStats.P50)
-		fmt.Fprintf(file, "  \"latency_p95\": %.2f,\n", snapshot.LatencyStats.P95)
-		fmt.Fprintf(file, "  \"latency_p99\": %.2f,\n", snapshot.LatencyStats.P99)
-		fmt.Fprintf(file, "  \"latency_max\": %.2f,\n", snapshot.LatencyStats.Max)
-	}
-	fmt.Fprintf(file, "  \"errors\": %d\n", snapshot.MessagesFailed)
-	fmt.Fprintf(file, "}\n")
-
-	return nil
+	report := metrics.NewReport(pool.GetMetrics().GetSnapshot(), opts)
+	_, err := metrics.WriteReport(cfg.Metrics.ExportPath, report)
+	return err
 }
 
 // printFinalStats prints final statistics to log
@@ -266,9 +376,16 @@ func printFinalStats(pool *worker.Pool) {
 	log.Printf("  Bytes Received: %d (%.2f MB)", snapshot.BytesReceived, float64(snapshot.BytesReceived)/(1024*1024))
 	log.Printf("  Average Receive Rate: %.2f msg/s", float64(snapshot.MessagesReceived)/snapshot.Elapsed.Seconds())
 	log.Printf("  Average Throughput: %.2f Mbps", throughputMbps)
-	if snapshot.LatencyStats.Count > 0 {
+	if snapshot.E2ELatency.Count > 0 {
 		log.Printf("  Latency (ms) - P50: %.2f, P95: %.2f, P99: %.2f, Max: %.2f",
-			snapshot.LatencyStats.P50, snapshot.LatencyStats.P95, snapshot.LatencyStats.P99, snapshot.LatencyStats.Max)
+			snapshot.E2ELatency.P50, snapshot.E2ELatency.P95, snapshot.E2ELatency.P99, snapshot.E2ELatency.Max)
+	}
+	if snapshot.Lag.HasBacklog {
+		log.Printf("  Backlog: %d (max %d at %s)", snapshot.Lag.Backlog, snapshot.Lag.MaxBacklog,
+			snapshot.Lag.MaxBacklogAt.Format(time.TimeOnly))
+	}
+	if snapshot.Lag.MaxLag > 0 {
+		log.Printf("  Max Lag: %v", snapshot.Lag.MaxLag)
 	}
 	if snapshot.MessagesFailed > 0 {
 		log.Printf("  Errors: %d (%.2f%%)", snapshot.MessagesFailed,
@@ -299,6 +416,12 @@ func printUsage() {
 	fmt.Fprintf(os.Stderr, "  %s --subscription my-consumer-group\n\n", os.Args[0])
 	fmt.Fprintf(os.Stderr, "  # Consume from 4-partition topic\n")
 	fmt.Fprintf(os.Stderr, "  %s --partitions 4 --workers 4\n\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  # Measure catch-up reads of the existing backlog without a subscription\n")
+	fmt.Fprintf(os.Stderr, "  %s --mode reader --start earliest\n\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  # Pre-fill 1M messages, then replay them from the earliest position\n")
+	fmt.Fprintf(os.Stderr, "  %s --mode backlog --backlog-messages 1000000 --seek-to earliest\n\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  # Scale consumers through the phases of a scenario unattended\n")
+	fmt.Fprintf(os.Stderr, "  %s --scenario consumers.yaml\n\n", os.Args[0])
 	fmt.Fprintf(os.Stderr, "PROFILES:\n")
 	for _, p := range config.GetAvailableProfiles() {
 		fmt.Fprintf(os.Stderr, "  %-18s %s\n", p, config.GetProfileDescription(p))
@@ -313,7 +436,7 @@ func printUsage() {
 	fmt.Fprintf(os.Stderr, "  P           - Pause/Resume workers\n")
 	fmt.Fprintf(os.Stderr, "  R           - Reset metrics\n")
 	fmt.Fprintf(os.Stderr, "  +/-         - Increase/Decrease workers\n")
-	fmt.Fprintf(os.Stderr, "  S           - Seek to earliest/latest\n")
+	fmt.Fprintf(os.Stderr, "  S           - Seek back to the position of the Seek Back control\n")
 	fmt.Fprintf(os.Stderr, "  H / ?       - Show help\n")
 }
 
@@ -324,4 +447,5 @@ func listProfiles() {
 		fmt.Printf("  %-18s %s\n", p, config.GetProfileDescription(p))
 	}
 	fmt.Printf("\nUse --profile <name> to select a profile\n")
+	fmt.Printf("Custom profiles are loaded from %s (override with $%s)\n", config.ProfileDir(), config.ProfileDirEnv)
 }
\ No newline at end of file
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/pulsar-local-lab/perf-test/internal/config"
+	"github.com/pulsar-local-lab/perf-test/internal/metrics"
+	"github.com/pulsar-local-lab/perf-test/internal/pulsar"
+	"github.com/pulsar-local-lab/perf-test/internal/ui"
+	"github.com/pulsar-local-lab/perf-test/internal/worker"
+)
+
+const (
+	appName    = "Pulsar End-to-End Performance Test"
+	appVersion = "1.0.0"
+)
+
+// Command-line flags
+var (
+	configFile   = flag.String("config", "", "Path to configuration file (JSON, YAML or TOML)")
+	profile      = flag.String("profile", "default", "Performance test profile (built-in or custom, see --list-profiles)")
+	scenarioFile = flag.String("scenario", "", "Run a multi-phase scenario file (YAML or JSON) and quit when it completes")
+	printConfig  = flag.Bool("print-config", false, "Print the effective configuration with the source of each value and exit")
+	showHelp     = flag.Bool("help", false, "Show help message")
+	listProfs    = flag.Bool("list-profiles", false, "List available performance profiles")
+	version      = flag.Bool("version", false, "Show version information")
+
+	// overrides holds --<section>.<field> flags for every config setting and the shorthands below
+	overrides = config.RegisterFlags(flag.CommandLine)
+)
+
+func init() {
+	overrides.Alias("service-url", "pulsar.service_url", "Pulsar broker service URL (shorthand for --pulsar.service-url)")
+	overrides.Alias("topic", "pulsar.topic", "Pulsar topic name (shorthand for --pulsar.topic)")
+	overrides.Alias("partitions", "pulsar.topic_partitions", "Number of topic partitions, 0=non-partitioned (shorthand for --pulsar.topic-partitions)")
+	overrides.Alias("recreate-topic", "pulsar.recreate_topic", "Delete and recreate the topic on startup (drops subscriptions and backlog)")
+	overrides.Alias("run-id", "pulsar.run_id", "Isolate the run by suffixing topic and subscription with a run ID (\"auto\" generates one)")
+	overrides.Alias("cleanup", "pulsar.cleanup_on_exit", "Delete the run topic on exit (requires --run-id)")
+	overrides.Alias("producers", "producer.num_producers", "Number of producer workers (shorthand for --producer.num-producers)")
+	overrides.Alias("consumers", "consumer.num_consumers", "Number of consumer workers (shorthand for --consumer.num-consumers)")
+	overrides.Alias("subscription", "consumer.subscription_name", "Subscription name (shorthand for --consumer.subscription-name)")
+	overrides.Alias("subscription-type", "consumer.subscription_type", "Subscription type: Exclusive, Shared, Failover, KeyShared")
+}
+
+func main() {
+	// Parse command-line flags
+	flag.Usage = printUsage
+	flag.Parse()
+
+	// Handle special flags
+	if *version {
+		fmt.Printf("%s v%s\n", appName, appVersion)
+		os.Exit(0)
+	}
+
+	// Load custom profiles so they can be selected and listed
+	if err := config.LoadProfiles(config.ProfileDir()); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load profiles: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *showHelp {
+		printUsage()
+		os.Exit(0)
+	}
+
+	if *listProfs {
+		listProfiles()
+		os.Exit(0)
+	}
+
+	// Create log buffer to capture all output
+	logBuffer := ui.NewLogBuffer(500)
+
+	// Redirect stdout and stderr to log buffer (captures ALL output including Pulsar client)
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create stdout pipe: %v\n", err)
+		os.Exit(1)
+	}
+	stderrReader, stderrWriter, err := os.Pipe()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create stderr pipe: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Save original stdout/stderr for emergency error messages
+	origStdout := os.Stdout
+	origStderr := os.Stderr
+
+	// Redirect OS-level stdout/stderr
+	os.Stdout = stdoutWriter
+	os.Stderr = stderrWriter
+	log.SetOutput(logBuffer)
+
+	// Start goroutines to copy pipe output to log buffer
+	go func() {
+		_, _ = io.Copy(logBuffer, stdoutReader)
+	}()
+	go func() {
+		_, _ = io.Copy(logBuffer, stderrReader)
+	}()
+
+	// Restore stderr for configuration errors (before UI starts)
+	defer func() {
+		os.Stdout = origStdout
+		os.Stderr = origStderr
+	}()
+
+	// Resolve configuration: defaults, profile, config file, environment, flags
+	cfg, origins, err := loadConfiguration()
+
+	// Show the effective configuration and where each value came from, even when it is
+	// invalid, so the layer that set a bad value can be found
+	if *printConfig && cfg != nil {
+		config.PrintConfig(origStdout, cfg, origins)
+	}
+	if err != nil {
+		fmt.Fprintf(origStderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+	if *printConfig {
+		os.Exit(0)
+	}
+
+	// Producers attach their send time so consumers measure exact end-to-end latency
+	cfg.Producer.SendTimestamps = true
+
+	// Isolate the run on its own topic and subscription if requested
+	if err := cfg.ApplyRunID(); err != nil {
+		fmt.Fprintf(origStderr, "Invalid configuration: %v\n", err)
+		os.Exit(1)
+	}
+	if cfg.Pulsar.RunID != "" {
+		log.Printf("Run ID: %s (topic: %s)", cfg.Pulsar.RunID, cfg.Pulsar.Topic)
+	}
+
+	// Validate configuration
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(origStderr, "Invalid configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Load the scenario before connecting so mistakes in the file fail fast
+	var scenario *config.Scenario
+	if *scenarioFile != "" {
+		scenario, err = config.LoadScenario(*scenarioFile)
+		if err != nil {
+			fmt.Fprintf(origStderr, "Invalid scenario: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Create context with cancellation
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Setup signal handling for graceful shutdown
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		cancel()
+	}()
+
+	// Initialize producer and consumer worker pools
+	// Temporarily restore stderr so errors are visible to user
+	os.Stderr = origStderr
+	producers, consumers, err := worker.NewE2EPools(ctx, cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\n❌ ERROR: Failed to initialize worker pools\n")
+		fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+		fmt.Fprintf(os.Stderr, "Configuration:\n")
+		fmt.Fprintf(os.Stderr, "  Service URL:  %s\n", cfg.Pulsar.ServiceURL)
+		fmt.Fprintf(os.Stderr, "  Admin URL:    %s\n", cfg.Pulsar.AdminURL)
+		fmt.Fprintf(os.Stderr, "  Topic:        %s\n", cfg.Pulsar.Topic)
+		fmt.Fprintf(os.Stderr, "  Subscription: %s (%s)\n\n", cfg.Consumer.SubscriptionName, cfg.Consumer.SubscriptionType)
+		fmt.Fprintf(os.Stderr, "Common issues:\n")
+		fmt.Fprintf(os.Stderr, "  • Pulsar broker not accessible\n")
+		fmt.Fprintf(os.Stderr, "    → Check port forwarding: kubectl port-forward -n pulsar svc/pulsar-broker 6650:6650 8080:8080\n")
+		fmt.Fprintf(os.Stderr, "    → Or run: ./scripts/access-ui.sh\n\n")
+		os.Exit(1)
+	}
+	// Restore redirection for TUI
+	os.Stderr = stderrWriter
+
+	// Run the scenario in the background; the UI closes when it completes
+	uiCtx, stopUI := context.WithCancel(ctx)
+	defer stopUI()
+	var runner *worker.ScenarioRunner
+	scenarioDone := make(chan error, 1)
+	if scenario != nil {
+		runner, err = worker.NewScenarioRunner(scenario, producers, consumers)
+		if err != nil {
+			fmt.Fprintf(origStderr, "Invalid scenario: %v\n", err)
+			os.Exit(1)
+		}
+		go func() {
+			_, err := runner.Run(uiCtx)
+			scenarioDone <- err
+			stopUI()
+		}()
+	}
+
+	// Start the interactive UI with log buffer (blocks until quit)
+	_ = ui.RunE2EUI(uiCtx, producers, consumers, logBuffer)
+
+	// Wait for the scenario to stop changing the pools before shutting them down
+	stopUI()
+	if runner != nil {
+		if err := <-scenarioDone; err != nil && !errors.Is(err, context.Canceled) {
+			fmt.Fprintf(origStderr, "Scenario failed: %v\n", err)
+		}
+	}
+
+	// Graceful shutdown (silent - TUI has been stopped)
+	_ = producers.Stop()
+	_ = consumers.Stop()
+
+	// Report where received messages were captured
+	if capture := consumers.Capture(); capture != nil {
+		stats := capture.Stats()
+		fmt.Fprintf(origStdout, "Captured %d messages (%d skipped) to %s: %d file(s), %.2f MB\n",
+			stats.Captured, stats.Skipped, cfg.Consumer.CaptureFile, stats.Files, float64(stats.Bytes)/(1024*1024))
+	}
+
+	// Report corrupt payloads, which are not counted as failures
+	if checksums := consumers.GetMetrics().GetSnapshot().Checksums; checksums.Verified > 0 {
+		fmt.Fprintf(origStdout, "Verified %d payload checksums: %d corrupt\n", checksums.Verified, checksums.Corrupt)
+	}
+
+	// Export metrics if enabled
+	if cfg.Metrics.ExportEnabled {
+		_ = exportMetrics(producers, cfg)
+	}
+	// Report per-phase metrics of the scenario
+	if runner != nil {
+		results := runner.Results()
+		worker.WriteScenarioSummary(origStdout, results)
+		if cfg.Metrics.ExportEnabled {
+			if filename, err := worker.ExportScenarioReport(cfg.Metrics.ExportPath, scenario, results); err != nil {
+				fmt.Fprintf(origStderr, "Failed to export scenario report: %v\n", err)
+			} else {
+				fmt.Fprintf(origStdout, "Scenario report: %s\n", filename)
+			}
+		}
+	}
+	// Remove the run's topic if requested
+	if cfg.Pulsar.CleanupOnExit {
+		if err := pulsar.CleanupRun(cfg, true); err != nil {
+			fmt.Fprintf(origStderr, "Cleanup failed: %v\n", err)
+		}
+	}
+
+	if cfg.Pulsar.RunID != "" {
+		fmt.Fprintf(origStdout, "Run ID: %s\n", cfg.Pulsar.RunID)
+	}
+}
+
+// loadConfiguration resolves the configuration from all layers
+func loadConfiguration() (*config.Config, config.Origins, error) {
+	if *configFile != "" {
+		log.Printf("Loading configuration from file: %s", *configFile)
+	}
+	log.Printf("Using profile: %s", *profile)
+	return config.Load(config.LoadOptions{
+		Profile: *profile,
+		File:    *configFile,
+		Flags:   overrides,
+	})
+}
+
+// exportMetrics exports final metrics of both pools to file
+func exportMetrics(pool *worker.Pool, cfg *config.Config) error {
+	// Producers and consumers share one collector
+	report := metrics.NewReport(pool.GetMetrics().GetSnapshot(), metrics.ReportOptions{
+		Tool:                    "e2e",
+		Send:                    true,
+		Receive:                 true,
+		RunID:                   cfg.Pulsar.RunID,
+		Topic:                   cfg.Pulsar.Topic,
+		CompressionType:         cfg.Producer.CompressionType,
+		PayloadCompressionRatio: cfg.Producer.PayloadCompressionRatio,
+	})
+	_, err := metrics.WriteReport(cfg.Metrics.ExportPath, report)
+	return err
+}
+
+// printUsage prints usage information
+func printUsage() {
+	fmt.Fprintf(os.Stderr, "%s v%s\n\n", appName, appVersion)
+	fmt.Fprintf(os.Stderr, "Runs producers and consumers on the same topic and measures\n")
+	fmt.Fprintf(os.Stderr, "end-to-end latency from send to receive.\n\n")
+	fmt.Fprintf(os.Stderr, "USAGE:\n")
+	fmt.Fprintf(os.Stderr, "  %s [options]\n\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "OPTIONS:\n")
+	flag.PrintDefaults()
+	fmt.Fprintf(os.Stderr, "\nEXAMPLES:\n")
+	fmt.Fprintf(os.Stderr, "  # Start with default profile\n")
+	fmt.Fprintf(os.Stderr, "  %s\n\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  # 4 producers and 2 Shared consumers on a fresh isolated topic\n")
+	fmt.Fprintf(os.Stderr, "  %s --producers 4 --consumers 2 --subscription-type Shared --run-id auto --cleanup\n\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  # Run a warm up, steady, spike and recover plan unattended\n")
+	fmt.Fprintf(os.Stderr, "  %s --scenario scenario.example.yaml\n\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "PROFILES:\n")
+	for _, p := range config.GetAvailableProfiles() {
+		fmt.Fprintf(os.Stderr, "  %-18s %s\n", p, config.GetProfileDescription(p))
+	}
+	fmt.Fprintf(os.Stderr, "\nKEYBOARD SHORTCUTS:\n")
+	fmt.Fprintf(os.Stderr, "  Q / Ctrl+C  - Quit application\n")
+	fmt.Fprintf(os.Stderr, "  P           - Pause/Resume producers\n")
+	fmt.Fprintf(os.Stderr, "  R           - Reset metrics\n")
+	fmt.Fprintf(os.Stderr, "  L           - Show/hide logs\n")
+	fmt.Fprintf(os.Stderr, "  H / ?       - Show help\n")
+}
+
+// listProfiles lists available performance profiles
+func listProfiles() {
+	fmt.Printf("Available performance profiles:\n\n")
+	for _, p := range config.GetAvailableProfiles() {
+		fmt.Printf("  %-18s %s\n", p, config.GetProfileDescription(p))
+	}
+	fmt.Printf("\nUse --profile <name> to select a profile\n")
+	fmt.Printf("Custom profiles are loaded from %s (override with $%s)\n", config.ProfileDir(), config.ProfileDirEnv)
+}
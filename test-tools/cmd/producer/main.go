@@ -2,17 +2,18 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"os/signal"
-	"path/filepath"
 	"syscall"
-	"time"
 
 	"github.com/pulsar-local-lab/perf-test/internal/config"
+	"github.com/pulsar-local-lab/perf-test/internal/metrics"
+	"github.com/pulsar-local-lab/perf-test/internal/pulsar"
 	"github.com/pulsar-local-lab/perf-test/internal/ui"
 	"github.com/pulsar-local-lab/perf-test/internal/worker"
 )
@@ -24,17 +25,28 @@ const (
 
 // Command-line flags
 var (
-	configFile = flag.String("config", "", "Path to configuration file (JSON)")
-	profile    = flag.String("profile", "default", "Performance test profile (default, low-latency, high-throughput, burst, sustained)")
-	serviceURL = flag.String("service-url", "", "Pulsar broker service URL (overrides config)")
-	topic      = flag.String("topic", "", "Pulsar topic name (overrides config)")
-	partitions = flag.Int("partitions", -1, "Number of topic partitions (overrides config, -1=use config, 0=non-partitioned)")
-	numWorkers = flag.Int("workers", 0, "Number of producer workers (overrides config, 0=use config)")
-	showHelp   = flag.Bool("help", false, "Show help message")
-	listProfs  = flag.Bool("list-profiles", false, "List available performance profiles")
-	version    = flag.Bool("version", false, "Show version information")
+	configFile   = flag.String("config", "", "Path to configuration file (JSON, YAML or TOML)")
+	profile      = flag.String("profile", "default", "Performance test profile (built-in or custom, see --list-profiles)")
+	scenarioFile = flag.String("scenario", "", "Run a multi-phase scenario file (YAML or JSON) and quit when it completes")
+	printConfig  = flag.Bool("print-config", false, "Print the effective configuration with the source of each value and exit")
+	showHelp     = flag.Bool("help", false, "Show help message")
+	listProfs    = flag.Bool("list-profiles", false, "List available performance profiles")
+	version      = flag.Bool("version", false, "Show version information")
+
+	// overrides holds --<section>.<field> flags for every config setting and the shorthands below
+	overrides = config.RegisterFlags(flag.CommandLine)
 )
 
+func init() {
+	overrides.Alias("service-url", "pulsar.service_url", "Pulsar broker service URL (shorthand for --pulsar.service-url)")
+	overrides.Alias("topic", "pulsar.topic", "Pulsar topic name (shorthand for --pulsar.topic)")
+	overrides.Alias("partitions", "pulsar.topic_partitions", "Number of topic partitions, 0=non-partitioned (shorthand for --pulsar.topic-partitions)")
+	overrides.Alias("recreate-topic", "pulsar.recreate_topic", "Delete and recreate the topic on startup (drops subscriptions and backlog)")
+	overrides.Alias("run-id", "pulsar.run_id", "Isolate the run by suffixing topic and subscription with a run ID (\"auto\" generates one)")
+	overrides.Alias("cleanup", "pulsar.cleanup_on_exit", "Delete the run topic on exit (requires --run-id)")
+	overrides.Alias("workers", "producer.num_producers", "Number of producer workers (shorthand for --producer.num-producers)")
+}
+
 func main() {
 	// Parse command-line flags
 	flag.Usage = printUsage
@@ -46,6 +58,12 @@ func main() {
 		os.Exit(0)
 	}
 
+	// Load custom profiles so they can be selected and listed
+	if err := config.LoadProfiles(config.ProfileDir()); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load profiles: %v\n", err)
+		os.Exit(1)
+	}
+
 	if *showHelp {
 		printUsage()
 		os.Exit(0)
@@ -94,22 +112,47 @@ func main() {
 		os.Stderr = origStderr
 	}()
 
-	// Load configuration
-	cfg, err := loadConfiguration()
+	// Resolve configuration: defaults, profile, config file, environment, flags
+	cfg, origins, err := loadConfiguration()
+
+	// Show the effective configuration and where each value came from, even when it is
+	// invalid, so the layer that set a bad value can be found
+	if *printConfig && cfg != nil {
+		config.PrintConfig(origStdout, cfg, origins)
+	}
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		fmt.Fprintf(origStderr, "Failed to load configuration: %v\n", err)
 		os.Exit(1)
 	}
+	if *printConfig {
+		os.Exit(0)
+	}
 
-	// Apply CLI overrides
-	applyOverrides(cfg)
+	// Isolate the run on its own topic and subscription if requested
+	if err := cfg.ApplyRunID(); err != nil {
+		fmt.Fprintf(origStderr, "Invalid configuration: %v\n", err)
+		os.Exit(1)
+	}
+	if cfg.Pulsar.RunID != "" {
+		log.Printf("Run ID: %s (topic: %s)", cfg.Pulsar.RunID, cfg.Pulsar.Topic)
+	}
 
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
-		fmt.Fprintf(os.Stderr, "Invalid configuration: %v\n", err)
+		fmt.Fprintf(origStderr, "Invalid configuration: %v\n", err)
 		os.Exit(1)
 	}
 
+	// Load the scenario before connecting so mistakes in the file fail fast
+	var scenario *config.Scenario
+	if *scenarioFile != "" {
+		scenario, err = config.LoadScenario(*scenarioFile)
+		if err != nil {
+			fmt.Fprintf(origStderr, "Invalid scenario: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// Create context with cancellation
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -163,8 +206,34 @@ func main() {
 	// Restore redirection for TUI
 	os.Stderr = stderrWriter
 
+	// Run the scenario in the background; the UI closes when it completes
+	uiCtx, stopUI := context.WithCancel(ctx)
+	defer stopUI()
+	var runner *worker.ScenarioRunner
+	scenarioDone := make(chan error, 1)
+	if scenario != nil {
+		runner, err = worker.NewScenarioRunner(scenario, pool, nil)
+		if err != nil {
+			fmt.Fprintf(origStderr, "Invalid scenario: %v\n", err)
+			os.Exit(1)
+		}
+		go func() {
+			_, err := runner.Run(uiCtx)
+			scenarioDone <- err
+			stopUI()
+		}()
+	}
+
 	// Start the interactive UI with log buffer (blocks until quit)
-	_ = ui.RunProducerUI(ctx, pool, logBuffer)
+	_ = ui.RunProducerUI(uiCtx, pool, logBuffer)
+
+	// Wait for the scenario to stop changing the pools before shutting them down
+	stopUI()
+	if runner != nil {
+		if err := <-scenarioDone; err != nil && !errors.Is(err, context.Canceled) {
+			fmt.Fprintf(origStderr, "Scenario failed: %v\n", err)
+		}
+	}
 
 	// Graceful shutdown (silent - TUI has been stopped)
 	_ = pool.Stop()
@@ -173,78 +242,56 @@ func main() {
 	if cfg.Metrics.ExportEnabled {
 		_ = exportMetrics(pool, cfg)
 	}
-}
-
-// loadConfiguration loads configuration from file or uses profile
-func loadConfiguration() (*config.Config, error) {
-	if *configFile != "" {
-		log.Printf("Loading configuration from file: %s", *configFile)
-		return config.LoadConfig(*configFile, "")
-	}
-
-	log.Printf("Using profile: %s", *profile)
-	return config.LoadConfig("", *profile)
-}
-
-// applyOverrides applies command-line overrides to configuration
-func applyOverrides(cfg *config.Config) {
-	if *serviceURL != "" {
-		log.Printf("Overriding service URL: %s", *serviceURL)
-		cfg.Pulsar.ServiceURL = *serviceURL
+	// Report per-phase metrics of the scenario
+	if runner != nil {
+		results := runner.Results()
+		worker.WriteScenarioSummary(origStdout, results)
+		if cfg.Metrics.ExportEnabled {
+			if filename, err := worker.ExportScenarioReport(cfg.Metrics.ExportPath, scenario, results); err != nil {
+				fmt.Fprintf(origStderr, "Failed to export scenario report: %v\n", err)
+			} else {
+				fmt.Fprintf(origStdout, "Scenario report: %s\n", filename)
+			}
+		}
 	}
-
-	if *topic != "" {
-		log.Printf("Overriding topic: %s", *topic)
-		cfg.Pulsar.Topic = *topic
+	// Remove the run's topic if requested
+	if cfg.Pulsar.CleanupOnExit {
+		if err := pulsar.CleanupRun(cfg, true); err != nil {
+			fmt.Fprintf(origStderr, "Cleanup failed: %v\n", err)
+		}
 	}
 
-	if *partitions >= 0 {
-		log.Printf("Overriding topic partitions: %d", *partitions)
-		cfg.Pulsar.TopicPartitions = *partitions
+	// Print the run ID so the next tool can join the same run
+	if cfg.Pulsar.RunID != "" {
+		fmt.Fprintf(origStdout, "Run ID: %s\n", cfg.Pulsar.RunID)
 	}
+}
 
-	if *numWorkers > 0 {
-		log.Printf("Overriding worker count: %d", *numWorkers)
-		cfg.Producer.NumProducers = *numWorkers
+// loadConfiguration resolves the configuration from all layers
+func loadConfiguration() (*config.Config, config.Origins, error) {
+	if *configFile != "" {
+		log.Printf("Loading configuration from file: %s", *configFile)
 	}
+	log.Printf("Using profile: %s", *profile)
+	return config.Load(config.LoadOptions{
+		Profile: *profile,
+		File:    *configFile,
+		Flags:   overrides,
+	})
 }
 
 // exportMetrics exports final metrics to file
 func exportMetrics(pool *worker.Pool, cfg *config.Config) error {
-	// Create export directory if it doesn't exist
-	if err := os.MkdirAll(cfg.Metrics.ExportPath, 0755); err != nil {
-		return fmt.Errorf("failed to create export directory: %w", err)
-	}
-
-	// Generate filename with timestamp
-	timestamp := time.Now().Format("20060102-150405")
-	filename := filepath.Join(cfg.Metrics.ExportPath, fmt.Sprintf("producer-metrics-%s.json", timestamp))
-
-	// Export metrics (this would call a method on the collector)
-	snapshot := pool.GetMetrics().GetSnapshot()
-
-	// Write snapshot to file (simplified - real implementation would serialize to JSON)
-	file, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("failed to create metrics file: %w", err)
-	}
-	defer file.Close()
-
-	// Calculate throughput in Mbps
-	throughputMbps := float64(snapshot.BytesSent) / snapshot.Elapsed.Seconds() / 1024 / 1024 * 8
-
-	// Write summary
-	fmt.Fprintf(file, "{\n")
-	fmt.Fprintf(file, "  \"timestamp\": \"%s\",\n", time.Now().Format(time.RFC3339))
-	fmt.Fprintf(file, "  \"duration\": \"%v\",\n", snapshot.Elapsed)
-	fmt.Fprintf(file, "  \"total_messages\": %d,\n", snapshot.MessagesSent)
-	fmt.Fprintf(file, "  \"total_bytes\": %d,\n", snapshot.BytesSent)
-	fmt.Fprintf(file, "  \"send_rate\": %.2f,\n", snapshot.Throughput.SendRate)
-	fmt.Fprintf(file, "  \"throughput_mbps\": %.2f,\n", throughputMbps)
-	fmt.Fprintf(file, "  \"errors\": %d\n", snapshot.MessagesFailed)
-	fmt.Fprintf(file, "}\n")
-
-	return nil
+	report := metrics.NewReport(pool.GetMetrics().GetSnapshot(), metrics.ReportOptions{
+		Tool:                    "producer",
+		Send:                    true,
+		RunID:                   cfg.Pulsar.RunID,
+		Topic:                   cfg.Pulsar.Topic,
+		CompressionType:         cfg.Producer.CompressionType,
+		PayloadCompressionRatio: cfg.Producer.PayloadCompressionRatio,
+	})
+	_, err := metrics.WriteReport(cfg.Metrics.ExportPath, report)
+	return err
 }
 
 // printFinalStats prints final statistics to log
@@ -260,6 +307,18 @@ func printFinalStats(pool *worker.Pool) {
 	log.Printf("  Bytes Sent: %d (%.2f MB)", snapshot.BytesSent, float64(snapshot.BytesSent)/(1024*1024))
 	log.Printf("  Average Send Rate: %.2f msg/s", float64(snapshot.MessagesSent)/snapshot.Elapsed.Seconds())
 	log.Printf("  Average Throughput: %.2f Mbps", throughputMbps)
+	if ratio := snapshot.WireCompressionRatio(); ratio > 0 {
+		cfg := pool.GetConfig()
+		if target := cfg.Producer.PayloadCompressionRatio; target > 0 {
+			log.Printf("  Compression (%s): wire ratio %.3f (payload target %.2f)", cfg.Producer.CompressionType, ratio, target)
+		} else {
+			log.Printf("  Compression (%s): wire ratio %.3f", cfg.Producer.CompressionType, ratio)
+		}
+	}
+	if sizes := snapshot.MessageSizes; sizes.Varies() {
+		log.Printf("  Message Size: min %.0f / p50 %.0f / p99 %.0f / max %.0f bytes (mean %.0f)",
+			sizes.Min, sizes.P50, sizes.P99, sizes.Max, sizes.Mean)
+	}
 	if snapshot.MessagesFailed > 0 {
 		log.Printf("  Errors: %d (%.2f%%)", snapshot.MessagesFailed,
 			float64(snapshot.MessagesFailed)/float64(snapshot.MessagesSent+snapshot.MessagesFailed)*100)
@@ -287,6 +346,10 @@ func printUsage() {
 	fmt.Fprintf(os.Stderr, "  %s --workers 10 --topic perf-test-topic\n\n", os.Args[0])
 	fmt.Fprintf(os.Stderr, "  # Test with 4 partitions\n")
 	fmt.Fprintf(os.Stderr, "  %s --partitions 4 --workers 4\n\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  # Start from a fresh topic with 8 partitions\n")
+	fmt.Fprintf(os.Stderr, "  %s --partitions 8 --recreate-topic\n\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  # Run a warm up, steady, spike and recover plan unattended\n")
+	fmt.Fprintf(os.Stderr, "  %s --scenario scenario.example.yaml\n\n", os.Args[0])
 	fmt.Fprintf(os.Stderr, "PROFILES:\n")
 	for _, p := range config.GetAvailableProfiles() {
 		fmt.Fprintf(os.Stderr, "  %-18s %s\n", p, config.GetProfileDescription(p))
@@ -306,4 +369,5 @@ func listProfiles() {
 		fmt.Printf("  %-18s %s\n", p, config.GetProfileDescription(p))
 	}
 	fmt.Printf("\nUse --profile <name> to select a profile\n")
+	fmt.Printf("Custom profiles are loaded from %s (override with $%s)\n", config.ProfileDir(), config.ProfileDirEnv)
 }
\ No newline at end of file
@@ -3,9 +3,13 @@ module github.com/pulsar-local-lab/perf-test
 go 1.25.1
 
 require (
+	github.com/BurntSushi/toml v1.6.0
 	github.com/apache/pulsar-client-go v0.12.1
+	github.com/cespare/xxhash/v2 v2.1.1
 	github.com/gdamore/tcell/v2 v2.7.0
 	github.com/rivo/tview v0.0.0-20240101144852-b3bd1aa5e9f2
+	github.com/streamnative/pulsar-admin-go v0.1.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -16,7 +20,6 @@ require (
 	github.com/ardielle/ardielle-go v1.5.2 // indirect
 	github.com/beorn7/perks v1.0.1 // indirect
 	github.com/bits-and-blooms/bitset v1.4.0 // indirect
-	github.com/cespare/xxhash/v2 v2.1.1 // indirect
 	github.com/danieljoos/wincred v1.1.2 // indirect
 	github.com/dvsekhvalnov/jose2go v1.6.0 // indirect
 	github.com/gdamore/encoding v1.0.0 // indirect
@@ -43,7 +46,6 @@ require (
 	github.com/rivo/uniseg v0.4.3 // indirect
 	github.com/sirupsen/logrus v1.6.0 // indirect
 	github.com/spaolacci/murmur3 v1.1.0 // indirect
-	github.com/streamnative/pulsar-admin-go v0.1.1 // indirect
 	go.uber.org/atomic v1.7.0 // indirect
 	golang.org/x/mod v0.8.0 // indirect
 	golang.org/x/net v0.17.0 // indirect
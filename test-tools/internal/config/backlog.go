@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// BacklogMode reports whether consumers drain a pre-filled backlog after seeking the
+// subscription back
+func (c *ConsumerConfig) BacklogMode() bool {
+	return c.Mode == ConsumerModeBacklog
+}
+
+// Prefill reports whether a backlog is produced before consumers start. Without limits
+// backlog mode replays the messages already on the topic.
+func (c *ConsumerConfig) Prefill() bool {
+	return c.BacklogMode() && (c.BacklogMessages > 0 || c.BacklogDuration > 0)
+}
+
+// ParseSeekPosition parses a position a subscription can seek to. Positions are given like
+// reader start positions, except that a subscription cannot seek to the latest message.
+func ParseSeekPosition(s string, now time.Time) (ReaderStart, error) {
+	start, err := ParseReaderStart(s, now)
+	if err != nil {
+		return ReaderStart{}, err
+	}
+	if start.Position == ReaderStartLatest {
+		return ReaderStart{}, fmt.Errorf("a subscription cannot seek to the latest message")
+	}
+	return start, nil
+}
+
+// validateBacklog checks the pre-fill limits and, in backlog mode, the seek position and topics
+func (c *Config) validateBacklog() error {
+	if c.Consumer.BacklogMessages < 0 {
+		return fmt.Errorf("backlog messages must be non-negative, got %d", c.Consumer.BacklogMessages)
+	}
+	if c.Consumer.BacklogDuration < 0 {
+		return fmt.Errorf("backlog duration must be non-negative, got %v", c.Consumer.BacklogDuration)
+	}
+	if !c.Consumer.BacklogMode() {
+		return nil
+	}
+	start, err := ParseSeekPosition(c.Consumer.SeekTo, time.Now())
+	if err != nil {
+		return fmt.Errorf("invalid seek position: %w", err)
+	}
+	if start.Position == ReaderStartMessage && c.Pulsar.TopicPartitions > 0 {
+		return fmt.Errorf("seeking to a message ID needs a non-partitioned topic")
+	}
+	// Consumers of several topics cannot seek
+	if c.MultiTopic() {
+		return fmt.Errorf("backlog mode needs a single topic")
+	}
+	if c.Metrics.BrokerStatsInterval <= 0 {
+		return fmt.Errorf("backlog mode needs broker stats polling to detect the end of the drain")
+	}
+	if c.Consumer.Prefill() && c.Producer.NumProducers <= 0 {
+		return fmt.Errorf("backlog pre-fill needs at least one producer")
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSeekPosition(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		input    string
+		position string
+		wantErr  bool
+	}{
+		{"earliest", ReaderStartEarliest, false},
+		{"", ReaderStartEarliest, false},
+		{"15m", ReaderStartTime, false},
+		{"2024-05-01T10:00:00Z", ReaderStartTime, false},
+		{"12:34", ReaderStartMessage, false},
+		{"latest", "", true},
+		{"yesterday", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseSeekPosition(tt.input, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Position != tt.position {
+				t.Errorf("expected position %s, got %s", tt.position, got.Position)
+			}
+		})
+	}
+}
+
+func TestValidateBacklog(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr string
+	}{
+		{"defaults", func(c *Config) {}, ""},
+		{"backlog", func(c *Config) {
+			c.Consumer.Mode = ConsumerModeBacklog
+			c.Consumer.BacklogMessages = 100000
+			c.Consumer.SeekTo = "10m"
+		}, ""},
+		{"negative messages", func(c *Config) { c.Consumer.BacklogMessages = -1 }, "backlog messages must be non-negative"},
+		{"negative duration", func(c *Config) { c.Consumer.BacklogDuration = Duration(-time.Second) }, "backlog duration must be non-negative"},
+		{"seek to latest", func(c *Config) { c.Consumer.Mode = ConsumerModeBacklog; c.Consumer.SeekTo = "latest" }, "invalid seek position"},
+		{"seek ignored for subscriptions", func(c *Config) { c.Consumer.SeekTo = "latest" }, ""},
+		{"message ID on partitioned topic", func(c *Config) {
+			c.Consumer.Mode = ConsumerModeBacklog
+			c.Consumer.SeekTo = "12:34"
+			c.Pulsar.TopicPartitions = 4
+		}, "non-partitioned topic"},
+		{"several topics", func(c *Config) {
+			c.Consumer.Mode = ConsumerModeBacklog
+			c.Pulsar.TopicCount = 3
+		}, "single topic"},
+		{"no broker stats", func(c *Config) {
+			c.Consumer.Mode = ConsumerModeBacklog
+			c.Metrics.BrokerStatsInterval = 0
+		}, "broker stats polling"},
+		{"no producers", func(c *Config) {
+			c.Consumer.Mode = ConsumerModeBacklog
+			c.Consumer.BacklogDuration = Duration(time.Minute)
+			c.Producer.NumProducers = 0
+		}, "at least one producer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig("")
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
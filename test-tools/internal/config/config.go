@@ -1,12 +1,11 @@
 package config
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
-	"strconv"
-	"strings"
 	"time"
+
+	"github.com/pulsar-local-lab/perf-test/internal/generator"
 )
 
 // Compression type constants
@@ -26,6 +25,45 @@ const (
 	SubscriptionKeyShared = "KeyShared"
 )
 
+// Capture payload modes
+const (
+	CapturePayloadFull = "full"
+	CapturePayloadHash = "hash"
+)
+
+// Payload size distribution constants
+const (
+	SizeFixed     = "fixed"
+	SizeUniform   = "uniform"
+	SizeNormal    = "normal"
+	SizeLogNormal = "lognormal"
+	SizeWeighted  = "weighted"
+	SizeHistogram = "histogram"
+)
+
+// Message routing mode constants
+const (
+	RoutingRoundRobin      = "RoundRobin"
+	RoutingSinglePartition = "SinglePartition"
+	RoutingCustom          = "Custom"
+)
+
+// Hashing scheme constants
+const (
+	HashingJavaString = "JavaStringHash"
+	HashingMurmur3    = "Murmur3_32Hash"
+)
+
+// Batcher builder type constants
+const (
+	BatcherDefault  = "Default"
+	BatcherKeyBased = "KeyBased"
+)
+
+// DefaultBrokerMaxMessageSize is the broker's default maxMessageSize (5 MB).
+// Payloads larger than this are rejected by the broker unless chunking is enabled.
+const DefaultBrokerMaxMessageSize = 5 * 1024 * 1024
+
 // Config represents the main configuration for performance testing.
 //
 // Example JSON configuration:
@@ -62,227 +100,362 @@ const (
 //	    "collection_interval": "1s",
 //	    "histogram_buckets": [1, 5, 10, 25, 50, 100, 250, 500, 1000],
 //	    "export_enabled": true,
-//	    "export_path": "./metrics"
+//	    "export_path": "./metrics",
+//	    "broker_stats_interval": "5s"
 //	  }
 //	}
 type Config struct {
 	// Pulsar connection settings
-	Pulsar PulsarConfig `json:"pulsar"`
+	Pulsar PulsarConfig `json:"pulsar" yaml:"pulsar" toml:"pulsar"`
 
 	// Producer settings
-	Producer ProducerConfig `json:"producer"`
+	Producer ProducerConfig `json:"producer" yaml:"producer" toml:"producer"`
 
 	// Consumer settings
-	Consumer ConsumerConfig `json:"consumer"`
+	Consumer ConsumerConfig `json:"consumer" yaml:"consumer" toml:"consumer"`
 
 	// Performance settings
-	Performance PerformanceConfig `json:"performance"`
+	Performance PerformanceConfig `json:"performance" yaml:"performance" toml:"performance"`
 
 	// Metrics settings
-	Metrics MetricsConfig `json:"metrics"`
+	Metrics MetricsConfig `json:"metrics" yaml:"metrics" toml:"metrics"`
 }
 
 // PulsarConfig contains Pulsar connection parameters.
 type PulsarConfig struct {
 	// ServiceURL is the Pulsar broker service URL (e.g., pulsar://localhost:6650)
-	ServiceURL string `json:"service_url"`
+	ServiceURL string `json:"service_url" yaml:"service_url" toml:"service_url"`
 
 	// AdminURL is the Pulsar admin API URL (e.g., http://localhost:8080)
-	AdminURL string `json:"admin_url"`
+	AdminURL string `json:"admin_url" yaml:"admin_url" toml:"admin_url"`
 
 	// Topic is the Pulsar topic name (e.g., persistent://public/default/perf-test)
-	Topic string `json:"topic"`
+	Topic string `json:"topic" yaml:"topic" toml:"topic"`
 
 	// TopicPartitions is the number of partitions for the topic (0 = non-partitioned)
-	TopicPartitions int `json:"topic_partitions"`
+	TopicPartitions int `json:"topic_partitions" yaml:"topic_partitions" toml:"topic_partitions"`
+
+	// TopicCount spreads the workload over this many topics named <topic>-0 to <topic>-<N-1>
+	// (0 = Topic only). Every topic gets TopicPartitions partitions.
+	TopicCount int `json:"topic_count" yaml:"topic_count" toml:"topic_count"`
+
+	// Topics is a comma-separated topic list used instead of Topic and TopicCount
+	Topics string `json:"topics" yaml:"topics" toml:"topics"`
+
+	// TopicWeights are comma-separated relative weights producers pick topics by, one per
+	// topic (e.g., "5,1,1"); empty spreads messages evenly
+	TopicWeights string `json:"topic_weights" yaml:"topic_weights" toml:"topic_weights"`
+
+	// RecreateTopic deletes an existing topic (with its subscriptions and backlog) and creates it again on startup
+	RecreateTopic bool `json:"recreate_topic" yaml:"recreate_topic" toml:"recreate_topic"`
+
+	// RunID isolates a run by suffixing the topic and subscription names with "-run-<id>".
+	// Use "auto" to generate one; share the generated ID with the consumer to read the same topic.
+	RunID string `json:"run_id" yaml:"run_id" toml:"run_id"`
+
+	// CleanupOnExit deletes the run's topic (producer) or subscription (consumer) on exit.
+	// Requires RunID so shared topics are never deleted.
+	CleanupOnExit bool `json:"cleanup_on_exit" yaml:"cleanup_on_exit" toml:"cleanup_on_exit"`
 }
 
 // ProducerConfig contains producer-specific settings.
 type ProducerConfig struct {
 	// NumProducers is the number of concurrent producer workers
-	NumProducers int `json:"num_producers"`
+	NumProducers int `json:"num_producers" yaml:"num_producers" toml:"num_producers"`
 
-	// MessageSize is the size of each message in bytes
-	MessageSize int `json:"message_size"`
+	// MessageSize is the size of each message in bytes, or the mean size of the normal
+	// and log-normal size distributions
+	MessageSize int `json:"message_size" yaml:"message_size" toml:"message_size"`
+
+	// SizeDistribution selects how payload sizes vary (fixed, uniform, normal, lognormal,
+	// weighted, histogram). Empty means fixed at MessageSize.
+	SizeDistribution string `json:"size_distribution" yaml:"size_distribution" toml:"size_distribution"`
+
+	// SizeMin is the smallest payload size in bytes: the lower bound of the uniform
+	// distribution and the clamp for normal and log-normal sizes (0 = 1 byte)
+	SizeMin int `json:"size_min" yaml:"size_min" toml:"size_min"`
+
+	// SizeMax is the largest payload size in bytes: the upper bound of the uniform
+	// distribution and the clamp for normal and log-normal sizes (required for all three)
+	SizeMax int `json:"size_max" yaml:"size_max" toml:"size_max"`
+
+	// SizeStdDev is the standard deviation in bytes of the normal and log-normal distributions
+	SizeStdDev int `json:"size_stddev" yaml:"size_stddev" toml:"size_stddev"`
+
+	// SizeWeights is the weighted size list of the weighted distribution as size:weight
+	// pairs (e.g., "512:70,4096:25,65536:5"); a size may be a min-max range
+	SizeWeights string `json:"size_weights" yaml:"size_weights" toml:"size_weights"`
+
+	// SizeHistogramFile is an empirical size histogram sampled by the histogram distribution,
+	// with one "size count" or "min-max count" line per bucket
+	SizeHistogramFile string `json:"size_histogram_file" yaml:"size_histogram_file" toml:"size_histogram_file"`
 
 	// BatchingEnabled enables message batching for better throughput
-	BatchingEnabled bool `json:"batching_enabled"`
+	BatchingEnabled bool `json:"batching_enabled" yaml:"batching_enabled" toml:"batching_enabled"`
 
 	// BatchingMaxSize is the maximum number of messages in a batch
-	BatchingMaxSize int `json:"batching_max_size"`
+	BatchingMaxSize int `json:"batching_max_size" yaml:"batching_max_size" toml:"batching_max_size"`
+
+	// BatchingMaxBytes is the maximum size of a batch in bytes (0 = client default of 128 KB)
+	BatchingMaxBytes int `json:"batching_max_bytes" yaml:"batching_max_bytes" toml:"batching_max_bytes"`
+
+	// BatchingMaxPublishDelay is how long messages are held to form a batch (0 = client default of 10ms)
+	BatchingMaxPublishDelay Duration `json:"batching_max_publish_delay" yaml:"batching_max_publish_delay" toml:"batching_max_publish_delay"`
+
+	// BatcherType selects the batch container (Default, KeyBased).
+	// KeyBased groups messages with the same key into the same batch.
+	BatcherType string `json:"batcher_type" yaml:"batcher_type" toml:"batcher_type"`
 
 	// CompressionType specifies the compression algorithm (NONE, LZ4, ZLIB, ZSTD, SNAPPY)
-	CompressionType string `json:"compression_type"`
+	CompressionType string `json:"compression_type" yaml:"compression_type" toml:"compression_type"`
+
+	// PayloadFill selects how payload bytes are generated (crypto, chacha8, pcg, rotating).
+	// crypto uses crypto/rand; the others are per-worker seeded generators that leave
+	// more CPU to the Pulsar client at high rates, rotating being the cheapest.
+	PayloadFill string `json:"payload_fill" yaml:"payload_fill" toml:"payload_fill"`
+
+	// PayloadSeed seeds the payload fill and size distribution of every worker, offset by
+	// the worker ID, to make payloads reproducible (0 = seed from the clock)
+	PayloadSeed int `json:"payload_seed" yaml:"payload_seed" toml:"payload_seed"`
+
+	// PayloadTemplate is an inline payload template, such as a JSON event with placeholders
+	// like {{uuid}}, {{seq}}, {{now}} and {{int 1 100}} (see generator.Template).
+	// Templated payloads replace the random payloads and their size settings.
+	PayloadTemplate string `json:"payload_template" yaml:"payload_template" toml:"payload_template"`
+
+	// PayloadTemplateFile is a file holding the payload template, as an alternative to
+	// PayloadTemplate
+	PayloadTemplateFile string `json:"payload_template_file" yaml:"payload_template_file" toml:"payload_template_file"`
+
+	// PayloadCompressionRatio makes generated payloads compress to roughly this fraction of
+	// their size (e.g., 0.3), so compression types can be compared on realistic data.
+	// 0 generates random payloads, which do not compress.
+	PayloadCompressionRatio float64 `json:"payload_compression_ratio" yaml:"payload_compression_ratio" toml:"payload_compression_ratio"`
+
+	// ReplayFile is a traffic file (NDJSON or length-prefixed binary, see generator.Record)
+	// whose messages are sent instead of generated payloads, with their keys and properties.
+	// Workers take records from the shared file in file order but send them concurrently, so
+	// the send order across workers is not preserved. Workers stop at the end of the file
+	// unless ReplayLoop is set.
+	ReplayFile string `json:"replay_file" yaml:"replay_file" toml:"replay_file"`
+
+	// ReplayFormat is the format of the replay file (ndjson, binary); empty selects it
+	// from the file extension
+	ReplayFormat string `json:"replay_format" yaml:"replay_format" toml:"replay_format"`
+
+	// ReplaySpeed scales the recorded timing of the replay file: 1 replays at the original
+	// pace, 10 ten times faster. 0 sends as fast as possible.
+	ReplaySpeed float64 `json:"replay_speed" yaml:"replay_speed" toml:"replay_speed"`
+
+	// ReplayLoop restarts the replay file at its end instead of ending the run
+	ReplayLoop bool `json:"replay_loop" yaml:"replay_loop" toml:"replay_loop"`
+
+	// RoutingMode selects how messages are spread over partitions (RoundRobin, SinglePartition, Custom).
+	// Custom pins every message to CustomPartition, which is useful for creating a hot partition.
+	RoutingMode string `json:"routing_mode" yaml:"routing_mode" toml:"routing_mode"`
+
+	// CustomPartition is the partition used by the Custom routing mode
+	CustomPartition int `json:"custom_partition" yaml:"custom_partition" toml:"custom_partition"`
+
+	// HashingScheme is the hash used to map message keys to partitions (JavaStringHash, Murmur3_32Hash)
+	HashingScheme string `json:"hashing_scheme" yaml:"hashing_scheme" toml:"hashing_scheme"`
+
+	// KeyCount is the number of distinct message keys producers rotate through (0 = no keys)
+	KeyCount int `json:"key_count" yaml:"key_count" toml:"key_count"`
+
+	// SendTimestamps attaches the send time as a message property so consumers sharing the
+	// producer's clock (e.g. the e2e tool) measure exact end-to-end latency
+	SendTimestamps bool `json:"send_timestamps" yaml:"send_timestamps" toml:"send_timestamps"`
+
+	// Properties are message properties attached to every message, as comma-separated
+	// name=value pairs. Values are static or use template placeholders (see
+	// generator.PropertyTemplate), e.g. "region=us-east,seq={{seq}},worker={{worker}}".
+	Properties string `json:"properties" yaml:"properties" toml:"properties"`
+
+	// PropertyPadding adds this many extra static properties (pad-0, pad-1, ...) to measure
+	// the overhead of large property maps (0 = none)
+	PropertyPadding int `json:"property_padding" yaml:"property_padding" toml:"property_padding"`
+
+	// PropertyPaddingSize is the value size in bytes of each padding property
+	PropertyPaddingSize int `json:"property_padding_size" yaml:"property_padding_size" toml:"property_padding_size"`
+
+	// PayloadChecksum attaches a checksum of each payload as a message property so
+	// consumers can detect corrupted payloads (none, crc32c, xxhash)
+	PayloadChecksum string `json:"payload_checksum" yaml:"payload_checksum" toml:"payload_checksum"`
 
 	// SendTimeout is the timeout for send operations
-	SendTimeout time.Duration `json:"send_timeout"`
+	SendTimeout Duration `json:"send_timeout" yaml:"send_timeout" toml:"send_timeout"`
 
 	// MaxPendingMsg is the maximum number of pending messages
-	MaxPendingMsg int `json:"max_pending_messages"`
+	MaxPendingMsg int `json:"max_pending_messages" yaml:"max_pending_messages" toml:"max_pending_messages"`
+
+	// EnableChunking splits payloads larger than the broker's max message size into chunks.
+	// Chunking cannot be combined with batching.
+	EnableChunking bool `json:"enable_chunking" yaml:"enable_chunking" toml:"enable_chunking"`
+
+	// ChunkMaxMessageSize is the maximum size of a single chunk in bytes (0 = broker max message size)
+	ChunkMaxMessageSize int `json:"chunk_max_message_size" yaml:"chunk_max_message_size" toml:"chunk_max_message_size"`
 }
 
 // ConsumerConfig contains consumer-specific settings.
 type ConsumerConfig struct {
 	// NumConsumers is the number of concurrent consumer workers
-	NumConsumers int `json:"num_consumers"`
+	NumConsumers int `json:"num_consumers" yaml:"num_consumers" toml:"num_consumers"`
 
 	// SubscriptionName is the name of the subscription
-	SubscriptionName string `json:"subscription_name"`
+	SubscriptionName string `json:"subscription_name" yaml:"subscription_name" toml:"subscription_name"`
 
 	// SubscriptionType specifies the subscription type (Exclusive, Shared, Failover, KeyShared)
-	SubscriptionType string `json:"subscription_type"`
+	SubscriptionType string `json:"subscription_type" yaml:"subscription_type" toml:"subscription_type"`
 
 	// ReceiverQueueSize is the size of the consumer receive queue
-	ReceiverQueueSize int `json:"receiver_queue_size"`
+	ReceiverQueueSize int `json:"receiver_queue_size" yaml:"receiver_queue_size" toml:"receiver_queue_size"`
 
 	// AckTimeout is the timeout for acknowledgment operations
-	AckTimeout time.Duration `json:"ack_timeout"`
+	AckTimeout Duration `json:"ack_timeout" yaml:"ack_timeout" toml:"ack_timeout"`
+
+	// MaxPendingChunkedMessages is the maximum number of chunked messages buffered
+	// for reassembly at once (0 = client default)
+	MaxPendingChunkedMessages int `json:"max_pending_chunked_messages" yaml:"max_pending_chunked_messages" toml:"max_pending_chunked_messages"`
+
+	// ChunkExpiry is how long an incomplete chunked message is kept before it is
+	// discarded (0 = client default)
+	ChunkExpiry Duration `json:"chunk_expiry" yaml:"chunk_expiry" toml:"chunk_expiry"`
+
+	// CaptureFile is a file received messages are written to, in a format producers can
+	// replay (NDJSON or binary by extension, see CaptureFormat). Empty disables capture.
+	CaptureFile string `json:"capture_file" yaml:"capture_file" toml:"capture_file"`
+
+	// CaptureFormat is the format of the capture file (ndjson, binary); empty selects it
+	// from the file extension. Only NDJSON records message IDs and timestamps.
+	CaptureFormat string `json:"capture_format" yaml:"capture_format" toml:"capture_format"`
+
+	// CapturePayload selects what is recorded of each payload (full, hash).
+	// hash records a SHA-256 of the payload instead of its bytes and requires NDJSON.
+	CapturePayload string `json:"capture_payload" yaml:"capture_payload" toml:"capture_payload"`
+
+	// CaptureSampleRate is the fraction of received messages that are captured (0 < rate <= 1)
+	CaptureSampleRate float64 `json:"capture_sample_rate" yaml:"capture_sample_rate" toml:"capture_sample_rate"`
+
+	// CaptureMaxPayloadSize truncates captured payloads to this many bytes (0 = no limit)
+	CaptureMaxPayloadSize int `json:"capture_max_payload_size" yaml:"capture_max_payload_size" toml:"capture_max_payload_size"`
+
+	// CaptureRotateSize starts a new capture file once the current one reaches this many
+	// bytes (0 = never rotate)
+	CaptureRotateSize int `json:"capture_rotate_size" yaml:"capture_rotate_size" toml:"capture_rotate_size"`
+
+	// CaptureMaxSize stops capturing once this many bytes have been written across all
+	// capture files (0 = no limit)
+	CaptureMaxSize int `json:"capture_max_size" yaml:"capture_max_size" toml:"capture_max_size"`
+
+	// GroupByProperty groups receive metrics by the value of this message property, e.g.
+	// a tenant or region set by producer properties (empty = no grouping)
+	GroupByProperty string `json:"group_by_property" yaml:"group_by_property" toml:"group_by_property"`
+
+	// VerifyChecksums verifies the payload checksum of messages that carry one (see
+	// ProducerConfig.PayloadChecksum) and counts mismatches as corrupt messages
+	VerifyChecksums bool `json:"verify_checksums" yaml:"verify_checksums" toml:"verify_checksums"`
+
+	// TopicsPattern subscribes consumers to every topic matching this regular expression
+	// instead of the topic list, e.g. "persistent://public/default/perf-test-.*"
+	TopicsPattern string `json:"topics_pattern" yaml:"topics_pattern" toml:"topics_pattern"`
+
+	// PatternDiscoveryInterval is how often topics created after subscribing are matched
+	// against TopicsPattern (0 = client default of 1 minute)
+	PatternDiscoveryInterval Duration `json:"pattern_discovery_interval" yaml:"pattern_discovery_interval" toml:"pattern_discovery_interval"`
+
+	// Mode selects how consumer workers read the topic (subscription, reader, backlog).
+	// Readers read without a subscription from ReaderStart and measure how fast they catch
+	// up. Backlog mode pre-fills a backlog, seeks the subscription to SeekTo and measures
+	// how fast consumers drain it.
+	Mode string `json:"mode" yaml:"mode" toml:"mode"`
+
+	// ReaderStart is where readers start: earliest, latest, a message ID
+	// (<ledger>:<entry>[:<partition>]), an RFC 3339 timestamp or a duration before now
+	ReaderStart string `json:"reader_start" yaml:"reader_start" toml:"reader_start"`
+
+	// BacklogMessages is the number of messages produced before consumers start in backlog
+	// mode (0 = no message limit)
+	BacklogMessages int `json:"backlog_messages" yaml:"backlog_messages" toml:"backlog_messages"`
+
+	// BacklogDuration is how long messages are produced before consumers start in backlog
+	// mode (0 = no time limit). With both limits set, pre-filling stops at the first one.
+	BacklogDuration Duration `json:"backlog_duration" yaml:"backlog_duration" toml:"backlog_duration"`
+
+	// SeekTo is where the subscription is moved before consumers start in backlog mode:
+	// earliest, a message ID (non-partitioned topics only), an RFC 3339 timestamp or a
+	// duration before now
+	SeekTo string `json:"seek_to" yaml:"seek_to" toml:"seek_to"`
 }
 
 // PerformanceConfig contains performance tuning parameters.
 type PerformanceConfig struct {
 	// TargetThroughput is the target messages per second (0 = unlimited)
-	TargetThroughput int `json:"target_throughput"`
+	TargetThroughput int `json:"target_throughput" yaml:"target_throughput" toml:"target_throughput"`
 
 	// Duration is the test duration (0 = unlimited)
-	Duration time.Duration `json:"duration"`
+	Duration Duration `json:"duration" yaml:"duration" toml:"duration"`
 
 	// Warmup is the warmup period before measurements begin
-	Warmup time.Duration `json:"warmup"`
+	Warmup Duration `json:"warmup" yaml:"warmup" toml:"warmup"`
 
 	// RateLimitEnabled enables rate limiting to achieve target throughput
-	RateLimitEnabled bool `json:"rate_limit_enabled"`
+	RateLimitEnabled bool `json:"rate_limit_enabled" yaml:"rate_limit_enabled" toml:"rate_limit_enabled"`
 }
 
 // MetricsConfig contains metrics collection settings.
 type MetricsConfig struct {
 	// CollectionInterval is the interval for collecting metrics snapshots
-	CollectionInterval time.Duration `json:"collection_interval"`
+	CollectionInterval Duration `json:"collection_interval" yaml:"collection_interval" toml:"collection_interval"`
 
 	// HistogramBuckets defines the latency histogram bucket boundaries in milliseconds
-	HistogramBuckets []float64 `json:"histogram_buckets"`
+	HistogramBuckets []float64 `json:"histogram_buckets" yaml:"histogram_buckets" toml:"histogram_buckets"`
 
 	// ExportEnabled enables exporting metrics to files
-	ExportEnabled bool `json:"export_enabled"`
+	ExportEnabled bool `json:"export_enabled" yaml:"export_enabled" toml:"export_enabled"`
 
 	// ExportPath is the directory path for exported metrics
-	ExportPath string `json:"export_path"`
+	ExportPath string `json:"export_path" yaml:"export_path" toml:"export_path"`
+
+	// BrokerStatsInterval is how often topic and subscription stats are polled from the
+	// admin API (0 disables broker stats polling)
+	BrokerStatsInterval Duration `json:"broker_stats_interval" yaml:"broker_stats_interval" toml:"broker_stats_interval"`
 }
 
-// LoadConfig loads configuration from a file or returns defaults.
+// LoadConfig loads configuration from the defaults, the profile and an optional file.
 // If path is empty, returns the default configuration with the specified profile applied.
-// If a file path is provided, loads the configuration from the file and validates it.
+// Fields missing from the file keep the profile's values. Environment variables and
+// flags are not applied; use Load for the full layered resolution.
 func LoadConfig(path string, profile string) (*Config, error) {
-	if path == "" {
-		return DefaultConfig(profile), nil
-	}
-
-	data, err := os.ReadFile(path)
+	noEnv := func(string) (string, bool) { return "", false }
+	cfg, _, err := Load(LoadOptions{Profile: profile, File: path, LookupEnv: noEnv})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file: %w", err)
+		return nil, err
 	}
-
-	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
-	}
-
-	// Validate the loaded configuration
-	if err := cfg.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid configuration: %w", err)
-	}
-
-	return &cfg, nil
+	return cfg, nil
 }
 
 // LoadConfigFromEnv loads configuration from environment variables.
-// Environment variables take precedence over default values.
-// Supported environment variables:
+// Environment variables take precedence over default values. Every setting is reachable
+// through its section and field key in upper case (see Settings), for example:
 //   - PULSAR_SERVICE_URL: Pulsar broker service URL
-//   - PULSAR_ADMIN_URL: Pulsar admin API URL
-//   - PULSAR_TOPIC: Pulsar topic name
-//   - PRODUCER_NUM_WORKERS: Number of producer workers
+//   - PULSAR_TOPIC_PARTITIONS: Number of topic partitions
 //   - PRODUCER_MESSAGE_SIZE: Message size in bytes
-//   - PRODUCER_TARGET_RATE: Target message rate per second
-//   - PRODUCER_BATCH_SIZE: Batch size for producers
-//   - PRODUCER_COMPRESSION: Compression type (NONE, LZ4, ZLIB, ZSTD, SNAPPY)
-//   - CONSUMER_NUM_WORKERS: Number of consumer workers
-//   - CONSUMER_SUBSCRIPTION: Consumer subscription name
+//   - PRODUCER_COMPRESSION_TYPE: Compression type (NONE, LZ4, ZLIB, ZSTD, SNAPPY)
 //   - CONSUMER_SUBSCRIPTION_TYPE: Subscription type (Exclusive, Shared, Failover, KeyShared)
-//   - METRICS_UPDATE_INTERVAL: Metrics collection interval (e.g., "1s", "100ms")
-//   - METRICS_ENABLE_EXPORT: Enable metrics export (true/false)
-//   - METRICS_EXPORT_PATH: Path for exported metrics
+//   - PERFORMANCE_TARGET_THROUGHPUT: Target message rate per second
+//   - METRICS_BROKER_STATS_INTERVAL: Broker stats polling interval (e.g., "5s", "0" to disable)
+//
+// The older names PRODUCER_NUM_WORKERS, PRODUCER_TARGET_RATE, PRODUCER_BATCH_SIZE,
+// PRODUCER_COMPRESSION, CONSUMER_NUM_WORKERS, CONSUMER_SUBSCRIPTION,
+// METRICS_UPDATE_INTERVAL and METRICS_ENABLE_EXPORT are still accepted.
 func LoadConfigFromEnv() (*Config, error) {
 	cfg := DefaultConfig("")
 
-	// Pulsar configuration
-	if v := os.Getenv("PULSAR_SERVICE_URL"); v != "" {
-		cfg.Pulsar.ServiceURL = v
-	}
-	if v := os.Getenv("PULSAR_ADMIN_URL"); v != "" {
-		cfg.Pulsar.AdminURL = v
-	}
-	if v := os.Getenv("PULSAR_TOPIC"); v != "" {
-		cfg.Pulsar.Topic = v
-	}
-	if v := os.Getenv("PULSAR_TOPIC_PARTITIONS"); v != "" {
-		if val, err := strconv.Atoi(v); err == nil {
-			cfg.Pulsar.TopicPartitions = val
-		}
-	}
-
-	// Producer configuration
-	if v := os.Getenv("PRODUCER_NUM_WORKERS"); v != "" {
-		if val, err := strconv.Atoi(v); err == nil {
-			cfg.Producer.NumProducers = val
-		}
-	}
-	if v := os.Getenv("PRODUCER_MESSAGE_SIZE"); v != "" {
-		if val, err := strconv.Atoi(v); err == nil {
-			cfg.Producer.MessageSize = val
-		}
-	}
-	if v := os.Getenv("PRODUCER_TARGET_RATE"); v != "" {
-		if val, err := strconv.Atoi(v); err == nil {
-			cfg.Performance.TargetThroughput = val
-		}
-	}
-	if v := os.Getenv("PRODUCER_BATCH_SIZE"); v != "" {
-		if val, err := strconv.Atoi(v); err == nil {
-			cfg.Producer.BatchingMaxSize = val
-		}
-	}
-	if v := os.Getenv("PRODUCER_COMPRESSION"); v != "" {
-		cfg.Producer.CompressionType = strings.ToUpper(v)
-	}
-
-	// Consumer configuration
-	if v := os.Getenv("CONSUMER_NUM_WORKERS"); v != "" {
-		if val, err := strconv.Atoi(v); err == nil {
-			cfg.Consumer.NumConsumers = val
-		}
-	}
-	if v := os.Getenv("CONSUMER_SUBSCRIPTION"); v != "" {
-		cfg.Consumer.SubscriptionName = v
-	}
-	if v := os.Getenv("CONSUMER_SUBSCRIPTION_TYPE"); v != "" {
-		cfg.Consumer.SubscriptionType = v
-	}
-
-	// Metrics configuration
-	if v := os.Getenv("METRICS_UPDATE_INTERVAL"); v != "" {
-		if val, err := time.ParseDuration(v); err == nil {
-			cfg.Metrics.CollectionInterval = val
-		}
-	}
-	if v := os.Getenv("METRICS_ENABLE_EXPORT"); v != "" {
-		if val, err := strconv.ParseBool(v); err == nil {
-			cfg.Metrics.ExportEnabled = val
-		}
-	}
-	if v := os.Getenv("METRICS_EXPORT_PATH"); v != "" {
-		cfg.Metrics.ExportPath = v
+	if err := applyEnv(cfg, os.LookupEnv, nil); err != nil {
+		return nil, err
 	}
+	cfg.normalize()
 
 	// Validate the configuration
 	if err := cfg.Validate(); err != nil {
@@ -294,6 +467,7 @@ func LoadConfigFromEnv() (*Config, error) {
 
 // DefaultConfig returns a default configuration with the specified profile applied.
 // If profile is empty or "default", returns base defaults without profile modifications.
+// Unknown profiles leave the defaults unchanged; use GetProfile or LoadConfig to report them.
 func DefaultConfig(profile string) *Config {
 	// Base defaults
 	cfg := &Config{
@@ -304,38 +478,53 @@ func DefaultConfig(profile string) *Config {
 			TopicPartitions: 0, // non-partitioned by default
 		},
 		Producer: ProducerConfig{
-			NumProducers:    1,
-			MessageSize:     1024,
-			BatchingEnabled: true,
-			BatchingMaxSize: 1000,
-			CompressionType: CompressionLZ4,
-			SendTimeout:     30 * time.Second,
-			MaxPendingMsg:   1000,
+			NumProducers:        1,
+			MessageSize:         1024,
+			SizeDistribution:    SizeFixed,
+			PayloadFill:         generator.FillCrypto,
+			ReplaySpeed:         1,
+			PropertyPaddingSize: 32,
+			PayloadChecksum:     generator.ChecksumNone,
+			BatchingEnabled:     true,
+			BatchingMaxSize:     1000,
+			CompressionType:     CompressionLZ4,
+			SendTimeout:         Duration(30 * time.Second),
+			MaxPendingMsg:       1000,
+			RoutingMode:         RoutingRoundRobin,
+			HashingScheme:       HashingJavaString,
+			BatcherType:         BatcherDefault,
 		},
 		Consumer: ConsumerConfig{
 			NumConsumers:      1,
 			SubscriptionName:  "perf-test-sub",
 			SubscriptionType:  SubscriptionShared,
 			ReceiverQueueSize: 1000,
-			AckTimeout:        30 * time.Second,
+			AckTimeout:        Duration(30 * time.Second),
+			CapturePayload:    CapturePayloadFull,
+			CaptureSampleRate: 1,
+			VerifyChecksums:   true,
+			Mode:              ConsumerModeSubscription,
+			ReaderStart:       ReaderStartEarliest,
+			SeekTo:            ReaderStartEarliest,
 		},
 		Performance: PerformanceConfig{
 			TargetThroughput: 0, // unlimited
 			Duration:         0, // unlimited
-			Warmup:           5 * time.Second,
+			Warmup:           Duration(5 * time.Second),
 			RateLimitEnabled: false,
 		},
 		Metrics: MetricsConfig{
-			CollectionInterval: 1 * time.Second,
-			HistogramBuckets:   []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000},
-			ExportEnabled:      false,
-			ExportPath:         "./metrics",
+			CollectionInterval:  Duration(1 * time.Second),
+			HistogramBuckets:    []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000},
+			ExportEnabled:       false,
+			ExportPath:          "./metrics",
+			BrokerStatsInterval: Duration(5 * time.Second),
 		},
 	}
 
 	// Apply profile-specific settings
 	if profile != "" && profile != "default" {
-		ApplyProfile(cfg, profile)
+		_ = ApplyProfile(cfg, profile)
 	}
 
 	return cfg
@@ -354,6 +543,12 @@ func (c *Config) Validate() error {
 	if c.Pulsar.TopicPartitions < 0 {
 		return fmt.Errorf("topic partitions must be non-negative, got %d", c.Pulsar.TopicPartitions)
 	}
+	if err := c.validateTopics(); err != nil {
+		return err
+	}
+	if c.Pulsar.CleanupOnExit && c.Pulsar.RunID == "" {
+		return fmt.Errorf("cleanup on exit requires a run ID")
+	}
 
 	// Validate producer configuration
 	if c.Producer.NumProducers < 0 {
@@ -371,6 +566,19 @@ func (c *Config) Validate() error {
 	if c.Producer.SendTimeout < 0 {
 		return fmt.Errorf("send timeout must be non-negative, got %v", c.Producer.SendTimeout)
 	}
+	if c.Producer.ChunkMaxMessageSize < 0 {
+		return fmt.Errorf("chunk max message size must be non-negative, got %d", c.Producer.ChunkMaxMessageSize)
+	}
+	if c.Producer.EnableChunking && c.Producer.BatchingEnabled {
+		return fmt.Errorf("chunking cannot be enabled when batching is enabled")
+	}
+	if err := c.Producer.validateSizeDistribution(); err != nil {
+		return err
+	}
+	if maxSize := c.Producer.MaxMessageSize(); !c.Producer.EnableChunking && maxSize > DefaultBrokerMaxMessageSize {
+		return fmt.Errorf("message size %d exceeds the broker max message size %d (enable chunking to send larger payloads)",
+			maxSize, DefaultBrokerMaxMessageSize)
+	}
 
 	// Validate compression type
 	validCompressionTypes := map[string]bool{
@@ -383,6 +591,66 @@ func (c *Config) Validate() error {
 	if !validCompressionTypes[c.Producer.CompressionType] {
 		return fmt.Errorf("invalid compression type: %s (must be one of: NONE, LZ4, ZLIB, ZSTD, SNAPPY)", c.Producer.CompressionType)
 	}
+	validFills := map[string]bool{
+		generator.FillCrypto:   true,
+		generator.FillChaCha8:  true,
+		generator.FillPCG:      true,
+		generator.FillRotating: true,
+	}
+	if !validFills[c.Producer.PayloadFill] {
+		return fmt.Errorf("invalid payload fill: %s (must be one of: crypto, chacha8, pcg, rotating)", c.Producer.PayloadFill)
+	}
+	if c.Producer.PayloadCompressionRatio != 0 {
+		if err := generator.ValidateCompressionRatio(c.Producer.PayloadCompressionRatio); err != nil {
+			return fmt.Errorf("invalid payload compression ratio: %w", err)
+		}
+	}
+	if err := c.Producer.validatePayloadTemplate(); err != nil {
+		return err
+	}
+	if err := c.Producer.validateReplay(); err != nil {
+		return err
+	}
+	if _, err := generator.NewChecksummer(c.Producer.PayloadChecksum); err != nil {
+		return fmt.Errorf("invalid payload checksum: %w", err)
+	}
+
+	// Validate routing and batching policy
+	if c.Producer.BatchingMaxBytes < 0 {
+		return fmt.Errorf("batching max bytes must be non-negative, got %d", c.Producer.BatchingMaxBytes)
+	}
+	if c.Producer.BatchingMaxPublishDelay < 0 {
+		return fmt.Errorf("batching max publish delay must be non-negative, got %v", c.Producer.BatchingMaxPublishDelay)
+	}
+	if c.Producer.KeyCount < 0 {
+		return fmt.Errorf("key count must be non-negative, got %d", c.Producer.KeyCount)
+	}
+	if err := c.Producer.validateProperties(); err != nil {
+		return err
+	}
+	validRoutingModes := map[string]bool{
+		RoutingRoundRobin:      true,
+		RoutingSinglePartition: true,
+		RoutingCustom:          true,
+	}
+	if c.Producer.RoutingMode != "" && !validRoutingModes[c.Producer.RoutingMode] {
+		return fmt.Errorf("invalid routing mode: %s (must be one of: RoundRobin, SinglePartition, Custom)", c.Producer.RoutingMode)
+	}
+	if c.Producer.RoutingMode == RoutingCustom {
+		if c.Producer.CustomPartition < 0 {
+			return fmt.Errorf("custom partition must be non-negative, got %d", c.Producer.CustomPartition)
+		}
+		if c.Pulsar.TopicPartitions > 0 && c.Producer.CustomPartition >= c.Pulsar.TopicPartitions {
+			return fmt.Errorf("custom partition %d out of range for topic with %d partitions",
+				c.Producer.CustomPartition, c.Pulsar.TopicPartitions)
+		}
+	}
+	if c.Producer.HashingScheme != "" && c.Producer.HashingScheme != HashingJavaString && c.Producer.HashingScheme != HashingMurmur3 {
+		return fmt.Errorf("invalid hashing scheme: %s (must be one of: JavaStringHash, Murmur3_32Hash)", c.Producer.HashingScheme)
+	}
+	if c.Producer.BatcherType != "" && c.Producer.BatcherType != BatcherDefault && c.Producer.BatcherType != BatcherKeyBased {
+		return fmt.Errorf("invalid batcher type: %s (must be one of: Default, KeyBased)", c.Producer.BatcherType)
+	}
 
 	// Validate consumer configuration
 	if c.Consumer.NumConsumers < 0 {
@@ -397,6 +665,12 @@ func (c *Config) Validate() error {
 	if c.Consumer.AckTimeout < 0 {
 		return fmt.Errorf("ack timeout must be non-negative, got %v", c.Consumer.AckTimeout)
 	}
+	if c.Consumer.MaxPendingChunkedMessages < 0 {
+		return fmt.Errorf("max pending chunked messages must be non-negative, got %d", c.Consumer.MaxPendingChunkedMessages)
+	}
+	if c.Consumer.ChunkExpiry < 0 {
+		return fmt.Errorf("chunk expiry must be non-negative, got %v", c.Consumer.ChunkExpiry)
+	}
 
 	// Validate subscription type
 	validSubscriptionTypes := map[string]bool{
@@ -408,6 +682,15 @@ func (c *Config) Validate() error {
 	if c.Consumer.SubscriptionType != "" && !validSubscriptionTypes[c.Consumer.SubscriptionType] {
 		return fmt.Errorf("invalid subscription type: %s (must be one of: Exclusive, Shared, Failover, KeyShared)", c.Consumer.SubscriptionType)
 	}
+	if err := c.Consumer.validateCapture(); err != nil {
+		return err
+	}
+	if err := c.validateReader(); err != nil {
+		return err
+	}
+	if err := c.validateBacklog(); err != nil {
+		return err
+	}
 
 	// Validate performance configuration
 	if c.Performance.TargetThroughput < 0 {
@@ -427,11 +710,305 @@ func (c *Config) Validate() error {
 	if c.Metrics.ExportEnabled && c.Metrics.ExportPath == "" {
 		return fmt.Errorf("metrics export path is required when export is enabled")
 	}
+	if c.Metrics.BrokerStatsInterval < 0 {
+		return fmt.Errorf("broker stats interval must be non-negative, got %v", c.Metrics.BrokerStatsInterval)
+	}
+
+	return nil
+}
+
+// ChunkThreshold returns the payload size above which the producer splits a message
+// into chunks, or 0 when chunking is disabled.
+func (p *ProducerConfig) ChunkThreshold() int {
+	if !p.EnableChunking {
+		return 0
+	}
+	if p.ChunkMaxMessageSize > 0 && p.ChunkMaxMessageSize < DefaultBrokerMaxMessageSize {
+		return p.ChunkMaxMessageSize
+	}
+	return DefaultBrokerMaxMessageSize
+}
+
+// validateSizeDistribution checks the parameters of the payload size distribution
+func (p *ProducerConfig) validateSizeDistribution() error {
+	if p.SizeMin < 0 || p.SizeMax < 0 || p.SizeStdDev < 0 {
+		return fmt.Errorf("size min, max and stddev must be non-negative")
+	}
+	if p.SizeMax > 0 && p.SizeMax < p.SizeMin {
+		return fmt.Errorf("size max %d must not be less than size min %d", p.SizeMax, p.SizeMin)
+	}
+
+	switch p.SizeDistribution {
+	case "", SizeFixed:
+	case SizeUniform:
+		if p.SizeMin <= 0 || p.SizeMax <= 0 {
+			return fmt.Errorf("uniform size distribution requires positive size min and max")
+		}
+	case SizeNormal, SizeLogNormal:
+		if p.SizeStdDev <= 0 {
+			return fmt.Errorf("%s size distribution requires a positive size stddev", p.SizeDistribution)
+		}
+		// Without a clamp the tail is unbounded and cannot be checked against the broker limit
+		if p.SizeMax <= 0 {
+			return fmt.Errorf("%s size distribution requires a positive size max", p.SizeDistribution)
+		}
+	case SizeWeighted:
+		buckets, err := generator.ParseSizeWeights(p.SizeWeights)
+		if err == nil {
+			_, err = generator.NewWeightedSize(buckets)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid size weights: %w", err)
+		}
+	case SizeHistogram:
+		if p.SizeHistogramFile == "" {
+			return fmt.Errorf("histogram size distribution requires a size histogram file")
+		}
+		if _, err := p.loadSizeHistogram(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid size distribution: %s (must be one of: fixed, uniform, normal, lognormal, weighted, histogram)", p.SizeDistribution)
+	}
+	return nil
+}
+
+// MaxMessageSize returns the largest payload size the configured distribution can produce,
+// or 0 when the distribution is invalid
+func (p *ProducerConfig) MaxMessageSize() int {
+	switch p.SizeDistribution {
+	case SizeUniform, SizeNormal, SizeLogNormal:
+		return p.SizeMax
+	case SizeWeighted:
+		buckets, err := generator.ParseSizeWeights(p.SizeWeights)
+		if err != nil {
+			return 0
+		}
+		weighted, err := generator.NewWeightedSize(buckets)
+		if err != nil {
+			return 0
+		}
+		return weighted.MaxSize()
+	case SizeHistogram:
+		weighted, err := p.loadSizeHistogram()
+		if err != nil {
+			return 0
+		}
+		return weighted.MaxSize()
+	default:
+		return p.MessageSize
+	}
+}
+
+// NewSizeDistribution builds the payload size distribution described by the producer config
+func (p *ProducerConfig) NewSizeDistribution() (generator.SizeDistribution, error) {
+	minSize := max(p.SizeMin, 1)
+	switch p.SizeDistribution {
+	case "", SizeFixed:
+		return generator.FixedSize(p.MessageSize), nil
+	case SizeUniform:
+		return generator.UniformSize{Min: p.SizeMin, Max: p.SizeMax}, nil
+	case SizeNormal:
+		return generator.NormalSize{Mean: float64(p.MessageSize), StdDev: float64(p.SizeStdDev), Min: minSize, Max: p.SizeMax}, nil
+	case SizeLogNormal:
+		return generator.NewLogNormalSize(float64(p.MessageSize), float64(p.SizeStdDev), minSize, p.SizeMax), nil
+	case SizeWeighted:
+		buckets, err := generator.ParseSizeWeights(p.SizeWeights)
+		if err != nil {
+			return nil, fmt.Errorf("invalid size weights: %w", err)
+		}
+		return generator.NewWeightedSize(buckets)
+	case SizeHistogram:
+		weighted, err := p.loadSizeHistogram()
+		if err != nil {
+			return nil, err
+		}
+		if !p.EnableChunking && weighted.MaxSize() > DefaultBrokerMaxMessageSize {
+			return nil, fmt.Errorf("size histogram %s contains sizes up to %d, above the broker max message size %d (enable chunking to send larger payloads)",
+				p.SizeHistogramFile, weighted.MaxSize(), DefaultBrokerMaxMessageSize)
+		}
+		return weighted, nil
+	default:
+		return nil, fmt.Errorf("invalid size distribution: %s", p.SizeDistribution)
+	}
+}
+
+// loadSizeHistogram loads the histogram file of the histogram size distribution
+func (p *ProducerConfig) loadSizeHistogram() (*generator.WeightedSize, error) {
+	buckets, err := generator.LoadSizeHistogram(p.SizeHistogramFile)
+	if err != nil {
+		return nil, err
+	}
+	return generator.NewWeightedSize(buckets)
+}
+
+// HasPayloadTemplate reports whether payloads are rendered from a template
+func (p *ProducerConfig) HasPayloadTemplate() bool {
+	return p.PayloadTemplate != "" || p.PayloadTemplateFile != ""
+}
+
+// validatePayloadTemplate checks the payload template settings; template files are
+// only read when the template is loaded
+func (p *ProducerConfig) validatePayloadTemplate() error {
+	if !p.HasPayloadTemplate() {
+		return nil
+	}
+	if p.PayloadTemplate != "" && p.PayloadTemplateFile != "" {
+		return fmt.Errorf("payload template and payload template file are mutually exclusive")
+	}
+	if p.SizeDistribution != "" && p.SizeDistribution != SizeFixed {
+		return fmt.Errorf("payload template cannot be combined with the %s size distribution", p.SizeDistribution)
+	}
+	if p.PayloadCompressionRatio != 0 {
+		return fmt.Errorf("payload template cannot be combined with a payload compression ratio")
+	}
+	if p.PayloadTemplate != "" {
+		if _, err := generator.ParseTemplate(p.PayloadTemplate); err != nil {
+			return fmt.Errorf("invalid payload template: %w", err)
+		}
+	}
+	return nil
+}
+
+// NewPayloadTemplate compiles the configured payload template, or returns nil when
+// payloads are not templated
+func (p *ProducerConfig) NewPayloadTemplate() (*generator.Template, error) {
+	switch {
+	case p.PayloadTemplateFile != "":
+		return generator.LoadTemplate(p.PayloadTemplateFile)
+	case p.PayloadTemplate != "":
+		tmpl, err := generator.ParseTemplate(p.PayloadTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid payload template: %w", err)
+		}
+		return tmpl, nil
+	default:
+		return nil, nil
+	}
+}
+
+// validateProperties checks the message property settings
+func (p *ProducerConfig) validateProperties() error {
+	if _, err := generator.ParseProperties(p.Properties); err != nil {
+		return fmt.Errorf("invalid properties: %w", err)
+	}
+	if p.PropertyPadding < 0 {
+		return fmt.Errorf("property padding must be non-negative, got %d", p.PropertyPadding)
+	}
+	if p.PropertyPadding > 0 && p.PropertyPaddingSize <= 0 {
+		return fmt.Errorf("property padding size must be positive, got %d", p.PropertyPaddingSize)
+	}
+	return nil
+}
+
+// NewPropertyRenderer builds the message property renderer of the given worker, or
+// returns nil when no properties are configured
+func (p *ProducerConfig) NewPropertyRenderer(worker int) (*generator.PropertyRenderer, error) {
+	if p.Properties == "" && p.PropertyPadding == 0 {
+		return nil, nil
+	}
+	props, err := generator.ParseProperties(p.Properties)
+	if err != nil {
+		return nil, fmt.Errorf("invalid properties: %w", err)
+	}
+	// Padding values are the same for every worker so runs are comparable
+	props.AddPadding(p.PropertyPadding, p.PropertyPaddingSize, uint64(p.PayloadSeed))
+	return generator.NewPropertyRenderer(props, worker, p.PayloadSeedFor(worker)), nil
+}
+
+// NewChecksummer builds the payload checksummer, or returns nil when checksums are disabled
+func (p *ProducerConfig) NewChecksummer() (*generator.Checksummer, error) {
+	return generator.NewChecksummer(p.PayloadChecksum)
+}
+
+// validateReplay checks the replay settings, which are ignored without a replay file;
+// the file itself is only read when the replayer is created
+func (p *ProducerConfig) validateReplay() error {
+	if p.ReplayFile == "" {
+		return nil
+	}
+	if p.ReplayFormat != "" && p.ReplayFormat != generator.RecordFormatNDJSON && p.ReplayFormat != generator.RecordFormatBinary {
+		return fmt.Errorf("invalid replay format: %s (must be one of: ndjson, binary)", p.ReplayFormat)
+	}
+	if p.ReplaySpeed < 0 {
+		return fmt.Errorf("replay speed must be non-negative, got %g", p.ReplaySpeed)
+	}
+	if p.HasPayloadTemplate() {
+		return fmt.Errorf("replay file cannot be combined with a payload template")
+	}
+	if p.SizeDistribution != "" && p.SizeDistribution != SizeFixed {
+		return fmt.Errorf("replay file cannot be combined with the %s size distribution", p.SizeDistribution)
+	}
+	if p.PayloadCompressionRatio != 0 {
+		return fmt.Errorf("replay file cannot be combined with a payload compression ratio")
+	}
+	return nil
+}
 
+// NewReplayer opens the configured replay file, or returns nil when no replay file is set
+func (p *ProducerConfig) NewReplayer() (*generator.Replayer, error) {
+	if p.ReplayFile == "" {
+		return nil, nil
+	}
+	return generator.NewReplayer(p.ReplayFile, generator.ReplayOptions{
+		Format: p.ReplayFormat,
+		Speed:  p.ReplaySpeed,
+		Loop:   p.ReplayLoop,
+	})
+}
+
+// validateCapture checks the capture settings, which are ignored without a capture file
+func (c *ConsumerConfig) validateCapture() error {
+	if c.CaptureFile == "" {
+		return nil
+	}
+	if c.CaptureFormat != "" && c.CaptureFormat != generator.RecordFormatNDJSON && c.CaptureFormat != generator.RecordFormatBinary {
+		return fmt.Errorf("invalid capture format: %s (must be one of: ndjson, binary)", c.CaptureFormat)
+	}
+	if c.CapturePayload != CapturePayloadFull && c.CapturePayload != CapturePayloadHash {
+		return fmt.Errorf("invalid capture payload: %s (must be one of: full, hash)", c.CapturePayload)
+	}
+	if c.CapturePayload == CapturePayloadHash && c.CaptureFormat == generator.RecordFormatBinary {
+		return fmt.Errorf("payload hashes can only be captured in the ndjson format")
+	}
+	if c.CaptureSampleRate <= 0 || c.CaptureSampleRate > 1 {
+		return fmt.Errorf("capture sample rate must be greater than 0 and at most 1, got %g", c.CaptureSampleRate)
+	}
+	if c.CaptureMaxPayloadSize < 0 {
+		return fmt.Errorf("capture max payload size must be non-negative, got %d", c.CaptureMaxPayloadSize)
+	}
+	if c.CaptureRotateSize < 0 {
+		return fmt.Errorf("capture rotate size must be non-negative, got %d", c.CaptureRotateSize)
+	}
+	if c.CaptureMaxSize < 0 {
+		return fmt.Errorf("capture max size must be non-negative, got %d", c.CaptureMaxSize)
+	}
 	return nil
 }
 
-// Save saves the configuration to a JSON file at the specified path.
+// PayloadSeedFor returns the seed of the given worker's payload fill and size distribution
+func (p *ProducerConfig) PayloadSeedFor(worker int) uint64 {
+	if p.PayloadSeed == 0 {
+		return uint64(time.Now().UnixNano()) + uint64(worker)
+	}
+	return uint64(p.PayloadSeed) + uint64(worker)
+}
+
+// NewPayloadFiller builds the payload filler of the given worker, mixing in repeated
+// filler text when a payload compression ratio is configured
+func (p *ProducerConfig) NewPayloadFiller(worker int) (generator.Filler, error) {
+	filler, err := generator.NewFiller(p.PayloadFill, p.PayloadSeedFor(worker))
+	if err != nil {
+		return nil, err
+	}
+	if p.PayloadCompressionRatio > 0 {
+		return generator.CompressibleFiller{Random: filler, Ratio: p.PayloadCompressionRatio}, nil
+	}
+	return filler, nil
+}
+
+// Save saves the configuration to a file at the specified path, in JSON, YAML or TOML
+// as selected by the file extension.
 // The file is created with 0644 permissions and formatted with indentation for readability.
 func (c *Config) Save(path string) error {
 	// Validate before saving
@@ -439,7 +1016,12 @@ func (c *Config) Save(path string) error {
 		return fmt.Errorf("cannot save invalid configuration: %w", err)
 	}
 
-	data, err := json.MarshalIndent(c, "", "  ")
+	format, err := FormatForPath(path)
+	if err != nil {
+		return err
+	}
+
+	data, err := encodeConfig(c, format)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
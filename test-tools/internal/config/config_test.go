@@ -1,10 +1,14 @@
 package config
 
 import (
+	"bytes"
+	"math/rand/v2"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/pulsar-local-lab/perf-test/internal/generator"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -95,6 +99,53 @@ func TestConfigValidation(t *testing.T) {
 			wantError: true,
 			errorMsg:  "invalid subscription type",
 		},
+		{
+			name: "valid capture",
+			modify: func(c *Config) {
+				c.Consumer.CaptureFile = "capture.ndjson"
+				c.Consumer.CapturePayload = CapturePayloadHash
+				c.Consumer.CaptureSampleRate = 0.1
+				c.Consumer.CaptureRotateSize = 64 << 20
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid capture payload",
+			modify: func(c *Config) {
+				c.Consumer.CaptureFile = "capture.ndjson"
+				c.Consumer.CapturePayload = "none"
+			},
+			wantError: true,
+			errorMsg:  "invalid capture payload",
+		},
+		{
+			name: "capture hashes in binary format",
+			modify: func(c *Config) {
+				c.Consumer.CaptureFile = "capture.bin"
+				c.Consumer.CaptureFormat = "binary"
+				c.Consumer.CapturePayload = CapturePayloadHash
+			},
+			wantError: true,
+			errorMsg:  "only be captured in the ndjson format",
+		},
+		{
+			name: "invalid capture sample rate",
+			modify: func(c *Config) {
+				c.Consumer.CaptureFile = "capture.ndjson"
+				c.Consumer.CaptureSampleRate = 1.5
+			},
+			wantError: true,
+			errorMsg:  "capture sample rate",
+		},
+		{
+			name: "negative capture rotate size",
+			modify: func(c *Config) {
+				c.Consumer.CaptureFile = "capture.ndjson"
+				c.Consumer.CaptureRotateSize = -1
+			},
+			wantError: true,
+			errorMsg:  "capture rotate size must be non-negative",
+		},
 		{
 			name: "negative target throughput",
 			modify: func(c *Config) {
@@ -120,6 +171,369 @@ func TestConfigValidation(t *testing.T) {
 			wantError: true,
 			errorMsg:  "metrics export path is required when export is enabled",
 		},
+		{
+			name: "negative broker stats interval",
+			modify: func(c *Config) {
+				c.Metrics.BrokerStatsInterval = Duration(-time.Second)
+			},
+			wantError: true,
+			errorMsg:  "broker stats interval must be non-negative",
+		},
+		{
+			name: "chunking with batching enabled",
+			modify: func(c *Config) {
+				c.Producer.EnableChunking = true
+				c.Producer.BatchingEnabled = true
+			},
+			wantError: true,
+			errorMsg:  "chunking cannot be enabled when batching is enabled",
+		},
+		{
+			name: "message size above broker limit without chunking",
+			modify: func(c *Config) {
+				c.Producer.MessageSize = DefaultBrokerMaxMessageSize + 1
+			},
+			wantError: true,
+			errorMsg:  "exceeds the broker max message size",
+		},
+		{
+			name: "message size above broker limit with chunking",
+			modify: func(c *Config) {
+				c.Producer.MessageSize = 16 * 1024 * 1024
+				c.Producer.BatchingEnabled = false
+				c.Producer.EnableChunking = true
+			},
+			wantError: false,
+		},
+		{
+			name: "negative chunk max message size",
+			modify: func(c *Config) {
+				c.Producer.ChunkMaxMessageSize = -1
+			},
+			wantError: true,
+			errorMsg:  "chunk max message size must be non-negative",
+		},
+		{
+			name: "negative max pending chunked messages",
+			modify: func(c *Config) {
+				c.Consumer.MaxPendingChunkedMessages = -1
+			},
+			wantError: true,
+			errorMsg:  "max pending chunked messages must be non-negative",
+		},
+		{
+			name: "negative chunk expiry",
+			modify: func(c *Config) {
+				c.Consumer.ChunkExpiry = Duration(-time.Second)
+			},
+			wantError: true,
+			errorMsg:  "chunk expiry must be non-negative",
+		},
+		{
+			name: "cleanup on exit without run ID",
+			modify: func(c *Config) {
+				c.Pulsar.CleanupOnExit = true
+			},
+			wantError: true,
+			errorMsg:  "cleanup on exit requires a run ID",
+		},
+		{
+			name: "invalid routing mode",
+			modify: func(c *Config) {
+				c.Producer.RoutingMode = "Broadcast"
+			},
+			wantError: true,
+			errorMsg:  "invalid routing mode",
+		},
+		{
+			name: "custom partition out of range",
+			modify: func(c *Config) {
+				c.Pulsar.TopicPartitions = 4
+				c.Producer.RoutingMode = RoutingCustom
+				c.Producer.CustomPartition = 4
+			},
+			wantError: true,
+			errorMsg:  "custom partition 4 out of range",
+		},
+		{
+			name: "custom partition in range",
+			modify: func(c *Config) {
+				c.Pulsar.TopicPartitions = 4
+				c.Producer.RoutingMode = RoutingCustom
+				c.Producer.CustomPartition = 3
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid hashing scheme",
+			modify: func(c *Config) {
+				c.Producer.HashingScheme = "MD5"
+			},
+			wantError: true,
+			errorMsg:  "invalid hashing scheme",
+		},
+		{
+			name: "invalid batcher type",
+			modify: func(c *Config) {
+				c.Producer.BatcherType = "Sticky"
+			},
+			wantError: true,
+			errorMsg:  "invalid batcher type",
+		},
+		{
+			name: "negative batching max bytes",
+			modify: func(c *Config) {
+				c.Producer.BatchingMaxBytes = -1
+			},
+			wantError: true,
+			errorMsg:  "batching max bytes must be non-negative",
+		},
+		{
+			name: "negative batching max publish delay",
+			modify: func(c *Config) {
+				c.Producer.BatchingMaxPublishDelay = Duration(-time.Millisecond)
+			},
+			wantError: true,
+			errorMsg:  "batching max publish delay must be non-negative",
+		},
+		{
+			name: "negative key count",
+			modify: func(c *Config) {
+				c.Producer.KeyCount = -1
+			},
+			wantError: true,
+			errorMsg:  "key count must be non-negative",
+		},
+		{
+			name: "valid uniform size distribution",
+			modify: func(c *Config) {
+				c.Producer.SizeDistribution = SizeUniform
+				c.Producer.SizeMin = 100
+				c.Producer.SizeMax = 10000
+			},
+			wantError: false,
+		},
+		{
+			name: "uniform size distribution without bounds",
+			modify: func(c *Config) {
+				c.Producer.SizeDistribution = SizeUniform
+			},
+			wantError: true,
+			errorMsg:  "uniform size distribution requires positive size min and max",
+		},
+		{
+			name: "size max below size min",
+			modify: func(c *Config) {
+				c.Producer.SizeDistribution = SizeUniform
+				c.Producer.SizeMin = 1000
+				c.Producer.SizeMax = 100
+			},
+			wantError: true,
+			errorMsg:  "size max 100 must not be less than size min 1000",
+		},
+		{
+			name: "lognormal size distribution without stddev",
+			modify: func(c *Config) {
+				c.Producer.SizeDistribution = SizeLogNormal
+			},
+			wantError: true,
+			errorMsg:  "lognormal size distribution requires a positive size stddev",
+		},
+		{
+			name: "normal size distribution without size max",
+			modify: func(c *Config) {
+				c.Producer.SizeDistribution = SizeNormal
+				c.Producer.SizeStdDev = 500
+			},
+			wantError: true,
+			errorMsg:  "normal size distribution requires a positive size max",
+		},
+		{
+			name: "lognormal sizes above broker limit",
+			modify: func(c *Config) {
+				c.Producer.SizeDistribution = SizeLogNormal
+				c.Producer.SizeStdDev = 4096
+				c.Producer.SizeMax = 10 * 1024 * 1024
+			},
+			wantError: true,
+			errorMsg:  "exceeds the broker max message size",
+		},
+		{
+			name: "invalid size weights",
+			modify: func(c *Config) {
+				c.Producer.SizeDistribution = SizeWeighted
+				c.Producer.SizeWeights = "512:70,big:30"
+			},
+			wantError: true,
+			errorMsg:  "invalid size weights",
+		},
+		{
+			name: "weighted sizes above broker limit",
+			modify: func(c *Config) {
+				c.Producer.SizeDistribution = SizeWeighted
+				c.Producer.SizeWeights = "512:99,10485760:1"
+			},
+			wantError: true,
+			errorMsg:  "exceeds the broker max message size",
+		},
+		{
+			name: "histogram without file",
+			modify: func(c *Config) {
+				c.Producer.SizeDistribution = SizeHistogram
+			},
+			wantError: true,
+			errorMsg:  "histogram size distribution requires a size histogram file",
+		},
+		{
+			name: "invalid payload fill",
+			modify: func(c *Config) {
+				c.Producer.PayloadFill = "xorshift"
+			},
+			wantError: true,
+			errorMsg:  "invalid payload fill",
+		},
+		{
+			name: "valid payload compression ratio",
+			modify: func(c *Config) {
+				c.Producer.PayloadCompressionRatio = 0.3
+			},
+			wantError: false,
+		},
+		{
+			name: "payload compression ratio above 1",
+			modify: func(c *Config) {
+				c.Producer.PayloadCompressionRatio = 1.5
+			},
+			wantError: true,
+			errorMsg:  "invalid payload compression ratio",
+		},
+		{
+			name: "valid payload template",
+			modify: func(c *Config) {
+				c.Producer.PayloadTemplate = `{"id": "{{uuid}}", "seq": {{seq}}}`
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid payload template",
+			modify: func(c *Config) {
+				c.Producer.PayloadTemplate = `{"id": "{{uid}}"}`
+			},
+			wantError: true,
+			errorMsg:  "invalid payload template",
+		},
+		{
+			name: "payload template and template file",
+			modify: func(c *Config) {
+				c.Producer.PayloadTemplate = `{{seq}}`
+				c.Producer.PayloadTemplateFile = "event.json"
+			},
+			wantError: true,
+			errorMsg:  "mutually exclusive",
+		},
+		{
+			name: "payload template with size distribution",
+			modify: func(c *Config) {
+				c.Producer.PayloadTemplate = `{{seq}}`
+				c.Producer.SizeDistribution = SizeUniform
+				c.Producer.SizeMin = 10
+				c.Producer.SizeMax = 100
+			},
+			wantError: true,
+			errorMsg:  "cannot be combined with the uniform size distribution",
+		},
+		{
+			name: "valid properties",
+			modify: func(c *Config) {
+				c.Producer.Properties = `region=us-east,seq={{seq}},tier={{choice "gold" "free"}}`
+				c.Producer.PropertyPadding = 50
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid properties",
+			modify: func(c *Config) {
+				c.Producer.Properties = "=us-east"
+			},
+			wantError: true,
+			errorMsg:  "invalid properties",
+		},
+		{
+			name: "negative property padding",
+			modify: func(c *Config) {
+				c.Producer.PropertyPadding = -1
+			},
+			wantError: true,
+			errorMsg:  "property padding must be non-negative",
+		},
+		{
+			name: "zero property padding size",
+			modify: func(c *Config) {
+				c.Producer.PropertyPadding = 10
+				c.Producer.PropertyPaddingSize = 0
+			},
+			wantError: true,
+			errorMsg:  "property padding size must be positive",
+		},
+		{
+			name: "valid payload checksum",
+			modify: func(c *Config) {
+				c.Producer.PayloadChecksum = "xxhash"
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid payload checksum",
+			modify: func(c *Config) {
+				c.Producer.PayloadChecksum = "md5"
+			},
+			wantError: true,
+			errorMsg:  "invalid payload checksum",
+		},
+		{
+			name: "valid replay",
+			modify: func(c *Config) {
+				c.Producer.ReplayFile = "capture.bin"
+				c.Producer.ReplayFormat = "binary"
+				c.Producer.ReplaySpeed = 0
+			},
+			wantError: false,
+		},
+		{
+			name: "unknown replay format",
+			modify: func(c *Config) {
+				c.Producer.ReplayFile = "capture.dat"
+				c.Producer.ReplayFormat = "avro"
+			},
+			wantError: true,
+			errorMsg:  "invalid replay format",
+		},
+		{
+			name: "negative replay speed",
+			modify: func(c *Config) {
+				c.Producer.ReplayFile = "capture.ndjson"
+				c.Producer.ReplaySpeed = -1
+			},
+			wantError: true,
+			errorMsg:  "replay speed must be non-negative",
+		},
+		{
+			name: "replay with payload template",
+			modify: func(c *Config) {
+				c.Producer.ReplayFile = "capture.ndjson"
+				c.Producer.PayloadTemplate = `{{seq}}`
+			},
+			wantError: true,
+			errorMsg:  "replay file cannot be combined with a payload template",
+		},
+		{
+			name: "unknown size distribution",
+			modify: func(c *Config) {
+				c.Producer.SizeDistribution = "pareto"
+			},
+			wantError: true,
+			errorMsg:  "invalid size distribution",
+		},
 	}
 
 	for _, tt := range tests {
@@ -143,6 +557,262 @@ func TestConfigValidation(t *testing.T) {
 	}
 }
 
+func TestNewSizeDistribution(t *testing.T) {
+	histogramPath := filepath.Join(t.TempDir(), "sizes.txt")
+	if err := os.WriteFile(histogramPath, []byte("100 5\n200-300 5\n"), 0644); err != nil {
+		t.Fatalf("failed to write histogram: %v", err)
+	}
+	largeHistogramPath := filepath.Join(t.TempDir(), "large.txt")
+	if err := os.WriteFile(largeHistogramPath, []byte("100 5\n10485760 1\n"), 0644); err != nil {
+		t.Fatalf("failed to write histogram: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		producer  ProducerConfig
+		min       int
+		max       int
+		wantError bool
+	}{
+		{"fixed", ProducerConfig{MessageSize: 1024}, 1024, 1024, false},
+		{"uniform", ProducerConfig{SizeDistribution: SizeUniform, SizeMin: 10, SizeMax: 20}, 10, 20, false},
+		{"normal", ProducerConfig{SizeDistribution: SizeNormal, MessageSize: 1000, SizeStdDev: 500, SizeMax: 1500}, 1, 1500, false},
+		{"lognormal", ProducerConfig{SizeDistribution: SizeLogNormal, MessageSize: 1000, SizeStdDev: 500, SizeMin: 100, SizeMax: 5000}, 100, 5000, false},
+		{"weighted", ProducerConfig{SizeDistribution: SizeWeighted, SizeWeights: "512:1,4096:1"}, 512, 4096, false},
+		{"histogram", ProducerConfig{SizeDistribution: SizeHistogram, SizeHistogramFile: histogramPath}, 100, 300, false},
+		{"missing histogram", ProducerConfig{SizeDistribution: SizeHistogram, SizeHistogramFile: histogramPath + ".missing"}, 0, 0, true},
+		{"histogram above broker limit", ProducerConfig{SizeDistribution: SizeHistogram, SizeHistogramFile: largeHistogramPath}, 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dist, err := tt.producer.NewSizeDistribution()
+			if tt.wantError {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			rng := rand.New(rand.NewPCG(1, 2))
+			for i := 0; i < 1000; i++ {
+				if size := dist.Next(rng); size < tt.min || size > tt.max {
+					t.Fatalf("size %d outside [%d, %d]", size, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestMaxMessageSize(t *testing.T) {
+	histogramPath := filepath.Join(t.TempDir(), "sizes.txt")
+	if err := os.WriteFile(histogramPath, []byte("100 5\n200-300 5\n"), 0644); err != nil {
+		t.Fatalf("failed to write histogram: %v", err)
+	}
+	largeHistogramPath := filepath.Join(t.TempDir(), "large.txt")
+	if err := os.WriteFile(largeHistogramPath, []byte("100 5\n10485760 1\n"), 0644); err != nil {
+		t.Fatalf("failed to write histogram: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		modify    func(*ProducerConfig)
+		want      int
+		wantError bool
+	}{
+		{"fixed", func(p *ProducerConfig) { p.MessageSize = 2048 }, 2048, false},
+		{"lognormal", func(p *ProducerConfig) {
+			p.SizeDistribution = SizeLogNormal
+			p.SizeStdDev = 500
+			p.SizeMax = 5000
+		}, 5000, false},
+		{"histogram", func(p *ProducerConfig) {
+			p.SizeDistribution = SizeHistogram
+			p.SizeHistogramFile = histogramPath
+		}, 300, false},
+		{"histogram above broker limit", func(p *ProducerConfig) {
+			p.SizeDistribution = SizeHistogram
+			p.SizeHistogramFile = largeHistogramPath
+		}, 10485760, true},
+		{"histogram above broker limit with chunking", func(p *ProducerConfig) {
+			p.SizeDistribution = SizeHistogram
+			p.SizeHistogramFile = largeHistogramPath
+			p.EnableChunking = true
+			p.BatchingEnabled = false
+		}, 10485760, false},
+		{"missing histogram", func(p *ProducerConfig) {
+			p.SizeDistribution = SizeHistogram
+			p.SizeHistogramFile = histogramPath + ".missing"
+		}, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig("")
+			tt.modify(&cfg.Producer)
+
+			if got := cfg.Producer.MaxMessageSize(); got != tt.want {
+				t.Errorf("MaxMessageSize() = %d, want %d", got, tt.want)
+			}
+			err := cfg.Validate()
+			if tt.wantError && err == nil {
+				t.Error("expected validation error, got nil")
+			}
+			if !tt.wantError && err != nil {
+				t.Errorf("unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
+func TestNewPayloadFiller(t *testing.T) {
+	fill := func(p ProducerConfig, worker int) []byte {
+		t.Helper()
+		filler, err := p.NewPayloadFiller(worker)
+		if err != nil {
+			t.Fatalf("failed to create filler: %v", err)
+		}
+		buf := make([]byte, 512)
+		filler.Fill(buf)
+		return buf
+	}
+
+	seeded := ProducerConfig{PayloadFill: generator.FillPCG, PayloadSeed: 42}
+	if !bytes.Equal(fill(seeded, 1), fill(seeded, 1)) {
+		t.Error("seeded workers should produce reproducible payloads")
+	}
+	if bytes.Equal(fill(seeded, 1), fill(seeded, 2)) {
+		t.Error("workers should produce different payloads")
+	}
+
+	filler, err := (&ProducerConfig{PayloadFill: generator.FillCrypto, PayloadCompressionRatio: 0.5}).NewPayloadFiller(0)
+	if err != nil {
+		t.Fatalf("failed to create filler: %v", err)
+	}
+	if _, ok := filler.(generator.CompressibleFiller); !ok {
+		t.Errorf("expected a compressible filler, got %T", filler)
+	}
+
+	if _, err := (&ProducerConfig{PayloadFill: "xorshift"}).NewPayloadFiller(0); err == nil {
+		t.Error("expected error for unknown payload fill")
+	}
+}
+
+func TestNewPayloadTemplate(t *testing.T) {
+	templatePath := filepath.Join(t.TempDir(), "event.json")
+	if err := os.WriteFile(templatePath, []byte(`{"seq": {{seq}}}`), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		producer  ProducerConfig
+		want      string
+		wantError bool
+	}{
+		{"no template", ProducerConfig{}, "", false},
+		{"inline", ProducerConfig{PayloadTemplate: `seq={{seq}}`}, "seq=0", false},
+		{"file", ProducerConfig{PayloadTemplateFile: templatePath}, `{"seq": 0}`, false},
+		{"missing file", ProducerConfig{PayloadTemplateFile: templatePath + ".missing"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := tt.producer.NewPayloadTemplate()
+			if tt.wantError {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.want == "" {
+				if tmpl != nil {
+					t.Error("expected no template")
+				}
+				return
+			}
+			if got := string(generator.NewTemplateRenderer(tmpl, 1).Render()); got != tt.want {
+				t.Errorf("expected payload %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNewPropertyRenderer(t *testing.T) {
+	if r, err := (&ProducerConfig{}).NewPropertyRenderer(0); r != nil || err != nil {
+		t.Errorf("expected no renderer without properties, got %v, %v", r, err)
+	}
+
+	p := &ProducerConfig{Properties: "worker={{worker}}", PropertyPadding: 2, PropertyPaddingSize: 8, PayloadSeed: 1}
+	r, err := p.NewPropertyRenderer(4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := r.Render()
+	if len(got) != 3 || got["worker"] != "4" || len(got["pad-1"]) != 8 {
+		t.Errorf("expected the worker ID and 2 padding properties, got %v", got)
+	}
+
+	// Padding is the same for every worker
+	other, _ := p.NewPropertyRenderer(5)
+	if other.Render()["pad-0"] != got["pad-0"] {
+		t.Error("expected the same padding values for every worker")
+	}
+}
+
+func TestNewReplayer(t *testing.T) {
+	if r, err := (&ProducerConfig{}).NewReplayer(); r != nil || err != nil {
+		t.Errorf("expected no replayer without a replay file, got %v, %v", r, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "capture.dat")
+	if err := os.WriteFile(path, []byte(`{"offset_ms": 0, "payload": "hello"}`+"\n"), 0644); err != nil {
+		t.Fatalf("failed to write replay file: %v", err)
+	}
+	if _, err := (&ProducerConfig{ReplayFile: path}).NewReplayer(); err == nil {
+		t.Error("expected error for a replay file without a known extension or format")
+	}
+
+	r, err := (&ProducerConfig{ReplayFile: path, ReplayFormat: generator.RecordFormatNDJSON}).NewReplayer()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer r.Close()
+	record, _, err := r.Next()
+	if err != nil || string(record.Payload) != "hello" {
+		t.Errorf("expected the recorded payload, got %q, %v", record.Payload, err)
+	}
+}
+
+func TestChunkThreshold(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  bool
+		maxChunk int
+		want     int
+	}{
+		{"chunking disabled", false, 1024, 0},
+		{"broker default", true, 0, DefaultBrokerMaxMessageSize},
+		{"custom chunk size", true, 1024 * 1024, 1024 * 1024},
+		{"chunk size above broker limit", true, 2 * DefaultBrokerMaxMessageSize, DefaultBrokerMaxMessageSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := ProducerConfig{EnableChunking: tt.enabled, ChunkMaxMessageSize: tt.maxChunk}
+			if got := p.ChunkThreshold(); got != tt.want {
+				t.Errorf("ChunkThreshold() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestCompressionTypeConstants(t *testing.T) {
 	validTypes := []string{
 		CompressionNone,
@@ -280,7 +950,7 @@ func TestLoadConfigFromEnv(t *testing.T) {
 		{"NumConsumers", cfg.Consumer.NumConsumers, 3},
 		{"SubscriptionName", cfg.Consumer.SubscriptionName, "test-sub"},
 		{"SubscriptionType", cfg.Consumer.SubscriptionType, "Exclusive"},
-		{"CollectionInterval", cfg.Metrics.CollectionInterval, 500 * time.Millisecond},
+		{"CollectionInterval", cfg.Metrics.CollectionInterval, Duration(500 * time.Millisecond)},
 		{"ExportEnabled", cfg.Metrics.ExportEnabled, true},
 		{"ExportPath", cfg.Metrics.ExportPath, "/tmp/metrics"},
 	}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Duration is a time.Duration that config files write as a Go duration string
+// ("30s", "5m", "1h30m"). Reading also accepts integer nanoseconds, the format
+// written by earlier versions of Save.
+type Duration time.Duration
+
+// String formats the duration in Go syntax (e.g., "1m30s")
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// MarshalText encodes the duration as a Go duration string
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText parses a Go duration string or integer nanoseconds. YAML and TOML
+// decoders pass numeric values here as text.
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		nanos, intErr := strconv.ParseInt(string(text), 10, 64)
+		if intErr != nil {
+			return err
+		}
+		parsed = time.Duration(nanos)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalJSON encodes the duration as a Go duration string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON accepts a Go duration string or integer nanoseconds. Unlike UnmarshalText,
+// a quoted number without a unit is rejected, since JSON can tell the two apart.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case string:
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		*d = Duration(parsed)
+		return nil
+	case float64:
+		if v != float64(int64(v)) {
+			return fmt.Errorf("invalid duration %v: nanoseconds must be an integer", v)
+		}
+		*d = Duration(int64(v))
+		return nil
+	default:
+		return fmt.Errorf("invalid duration %s: must be a string like \"30s\" or integer nanoseconds", data)
+	}
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDurationUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      Duration
+		wantError bool
+	}{
+		{"string seconds", `"30s"`, Duration(30 * time.Second), false},
+		{"string compound", `"1h30m"`, Duration(90 * time.Minute), false},
+		{"string milliseconds", `"500ms"`, Duration(500 * time.Millisecond), false},
+		{"string zero", `"0"`, 0, false},
+		{"nanoseconds", `30000000000`, Duration(30 * time.Second), false},
+		{"zero number", `0`, 0, false},
+		{"missing unit", `"30"`, 0, true},
+		{"invalid string", `"soon"`, 0, true},
+		{"fractional nanoseconds", `1.5`, 0, true},
+		{"bool", `true`, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d Duration
+			err := json.Unmarshal([]byte(tt.input), &d)
+			if tt.wantError {
+				if err == nil {
+					t.Errorf("expected error for %s, got %v", tt.input, d)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if d != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, d)
+			}
+		})
+	}
+}
+
+func TestDurationMarshalJSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Timeout Duration `json:"timeout"`
+	}{Duration(90 * time.Second)})
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	if string(data) != `{"timeout":"1m30s"}` {
+		t.Errorf("expected human-readable duration, got %s", data)
+	}
+}
+
+func TestLoadExampleJSON(t *testing.T) {
+	cfg, err := LoadConfig(filepath.Join("..", "..", "config.example.json"), "")
+	if err != nil {
+		t.Fatalf("failed to load example config: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		got  Duration
+		want time.Duration
+	}{
+		{"SendTimeout", cfg.Producer.SendTimeout, 30 * time.Second},
+		{"AckTimeout", cfg.Consumer.AckTimeout, 30 * time.Second},
+		{"Duration", cfg.Performance.Duration, 5 * time.Minute},
+		{"Warmup", cfg.Performance.Warmup, 5 * time.Second},
+		{"CollectionInterval", cfg.Metrics.CollectionInterval, time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if time.Duration(tt.got) != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, tt.got)
+			}
+		})
+	}
+}
+
+func TestLoadConfigNanosecondDurations(t *testing.T) {
+	// Files written by earlier versions of Save store durations as nanoseconds
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(configPath, []byte(`{"producer": {"send_timeout": 45000000000}}`), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath, "")
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if cfg.Producer.SendTimeout != Duration(45*time.Second) {
+		t.Errorf("expected send timeout 45s, got %v", cfg.Producer.SendTimeout)
+	}
+}
+
+func TestLoadConfigNumericDurations(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    Duration
+	}{
+		{"yaml nanoseconds", "config.yaml", "producer:\n  send_timeout: 30000000000\n", Duration(30 * time.Second)},
+		{"yaml string", "config.yaml", "producer:\n  send_timeout: 45s\n", Duration(45 * time.Second)},
+		{"yaml zero", "config.yaml", "producer:\n  send_timeout: 0\n", 0},
+		{"toml nanoseconds", "config.toml", "[producer]\nsend_timeout = 30000000000\n", Duration(30 * time.Second)},
+		{"toml string", "config.toml", "[producer]\nsend_timeout = \"45s\"\n", Duration(45 * time.Second)},
+		{"toml zero", "config.toml", "[producer]\nsend_timeout = 0\n", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+
+			cfg, err := LoadConfig(configPath, "")
+			if err != nil {
+				t.Fatalf("failed to load config: %v", err)
+			}
+			if cfg.Producer.SendTimeout != tt.want {
+				t.Errorf("expected send timeout %v, got %v", tt.want, cfg.Producer.SendTimeout)
+			}
+		})
+	}
+
+	// Values that are neither durations nor integers still fail
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("producer:\n  send_timeout: 1.5\n"), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	if _, err := LoadConfig(configPath, ""); err == nil {
+		t.Error("expected error for fractional duration")
+	}
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Config file format constants
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+)
+
+// FormatForPath returns the config file format selected by the file extension.
+// Files without an extension are read as JSON.
+func FormatForPath(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", "":
+		return FormatJSON, nil
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".toml":
+		return FormatTOML, nil
+	default:
+		return "", fmt.Errorf("unsupported config file extension %q (must be one of: .json, .yaml, .yml, .toml)", filepath.Ext(path))
+	}
+}
+
+// decodeConfig decodes data in the given format onto cfg and returns the setting keys it
+// contains. Unknown sections and keys are rejected so typos do not silently fall back to
+// the previous layer's values.
+func decodeConfig(data []byte, format string, cfg *Config) ([]string, error) {
+	sections := map[string]map[string]any{}
+	var err error
+	switch format {
+	case FormatJSON:
+		err = json.Unmarshal(data, &sections)
+	case FormatYAML:
+		err = yaml.Unmarshal(data, &sections)
+	case FormatTOML:
+		_, err = toml.Decode(string(data), &sections)
+	default:
+		return nil, fmt.Errorf("unsupported config format: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkKeys(sections); err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case FormatJSON:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(cfg)
+	case FormatYAML:
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(cfg)
+	case FormatTOML:
+		_, err = toml.Decode(string(data), cfg)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, s := range settings {
+		section, field, _ := strings.Cut(s.Key, ".")
+		if _, ok := sections[section][field]; ok {
+			keys = append(keys, s.Key)
+		}
+	}
+	return keys, nil
+}
+
+// checkKeys returns an error naming the first unknown section or key, in sorted order
+func checkKeys(sections map[string]map[string]any) error {
+	known := make(map[string]bool, len(settings))
+	knownSections := map[string]bool{}
+	for _, s := range settings {
+		section, _, _ := strings.Cut(s.Key, ".")
+		known[s.Key] = true
+		knownSections[section] = true
+	}
+
+	names := make([]string, 0, len(sections))
+	for name := range sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !knownSections[name] {
+			return fmt.Errorf("unknown section %q", name)
+		}
+		fields := make([]string, 0, len(sections[name]))
+		for field := range sections[name] {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			key := name + "." + field
+			if known[key] {
+				continue
+			}
+			if suggestion := closestSetting(key); suggestion != "" {
+				return fmt.Errorf("unknown key %q (did you mean %q?)", key, suggestion)
+			}
+			return fmt.Errorf("unknown key %q", key)
+		}
+	}
+	return nil
+}
+
+// closestSetting returns the setting key closest to key, or "" if none is a likely typo
+func closestSetting(key string) string {
+	best, bestDistance := "", 4
+	for _, s := range settings {
+		if d := editDistance(key, s.Key); d < bestDistance {
+			best, bestDistance = s.Key, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// encodeConfig encodes cfg in the given format
+func encodeConfig(cfg *Config, format string) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		return json.MarshalIndent(cfg, "", "  ")
+	case FormatYAML:
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(cfg); err != nil {
+			return nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case FormatTOML:
+		var buf bytes.Buffer
+		encoder := toml.NewEncoder(&buf)
+		encoder.Indent = ""
+		if err := encoder.Encode(cfg); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unsupported config format: %s", format)
+	}
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatForPath(t *testing.T) {
+	tests := []struct {
+		path      string
+		want      string
+		wantError bool
+	}{
+		{"config.json", FormatJSON, false},
+		{"config", FormatJSON, false},
+		{"config.yaml", FormatYAML, false},
+		{"CONFIG.YML", FormatYAML, false},
+		{"config.toml", FormatTOML, false},
+		{"config.ini", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := FormatForPath(tt.path)
+			if tt.wantError {
+				if err == nil {
+					t.Errorf("expected error for %s", tt.path)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected format %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	cfg := DefaultConfig("high-throughput")
+	cfg.Producer.MessageSize = 4096
+	cfg.Producer.SendTimeout = Duration(45 * time.Second)
+	cfg.Metrics.HistogramBuckets = []float64{1, 2.5, 10}
+
+	for _, ext := range []string{".json", ".yaml", ".toml"} {
+		t.Run(ext, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config"+ext)
+			if err := cfg.Save(configPath); err != nil {
+				t.Fatalf("failed to save config: %v", err)
+			}
+
+			loaded, err := LoadConfig(configPath, "")
+			if err != nil {
+				t.Fatalf("failed to load saved config: %v", err)
+			}
+			if !reflect.DeepEqual(loaded, cfg) {
+				t.Errorf("loaded config differs from saved config:\n got %+v\nwant %+v", loaded, cfg)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFormats(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		data string
+	}{
+		{
+			name: "yaml",
+			file: "config.yaml",
+			data: "# comment\nproducer:\n  message_size: 2048\n  send_timeout: 10s\nperformance:\n  duration: 5m\n",
+		},
+		{
+			name: "toml",
+			file: "config.toml",
+			data: "# comment\n[producer]\nmessage_size = 2048\nsend_timeout = \"10s\"\n\n[performance]\nduration = \"5m\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(configPath, []byte(tt.data), 0644); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+
+			cfg, err := LoadConfig(configPath, "")
+			if err != nil {
+				t.Fatalf("failed to load config: %v", err)
+			}
+			if cfg.Producer.MessageSize != 2048 {
+				t.Errorf("expected message size 2048, got %d", cfg.Producer.MessageSize)
+			}
+			if cfg.Producer.SendTimeout != Duration(10*time.Second) {
+				t.Errorf("expected send timeout 10s, got %v", cfg.Producer.SendTimeout)
+			}
+			if cfg.Performance.Duration != Duration(5*time.Minute) {
+				t.Errorf("expected duration 5m, got %v", cfg.Performance.Duration)
+			}
+			// Fields missing from the file keep their defaults
+			if cfg.Producer.NumProducers != 1 {
+				t.Errorf("expected default num producers 1, got %d", cfg.Producer.NumProducers)
+			}
+		})
+	}
+}
+
+func TestLoadConfigUnknownKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		data     string
+		errorMsg string
+	}{
+		{
+			name:     "json typo",
+			file:     "config.json",
+			data:     `{"producer": {"num_producer": 5}}`,
+			errorMsg: `unknown key "producer.num_producer" (did you mean "producer.num_producers"?)`,
+		},
+		{
+			name:     "yaml typo",
+			file:     "config.yaml",
+			data:     "consumer:\n  subscription: my-sub\n",
+			errorMsg: `unknown key "consumer.subscription"`,
+		},
+		{
+			name:     "toml typo",
+			file:     "config.toml",
+			data:     "[metrics]\nexport_pth = \"/tmp\"\n",
+			errorMsg: `unknown key "metrics.export_pth" (did you mean "metrics.export_path"?)`,
+		},
+		{
+			name:     "unknown section",
+			file:     "config.yaml",
+			data:     "producers:\n  message_size: 10\n",
+			errorMsg: `unknown section "producers"`,
+		},
+		{
+			name:     "unsupported extension",
+			file:     "config.ini",
+			data:     "",
+			errorMsg: "unsupported config file extension",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(configPath, []byte(tt.data), 0644); err != nil {
+				t.Fatalf("failed to write config: %v", err)
+			}
+
+			_, err := LoadConfig(configPath, "")
+			if err == nil {
+				t.Fatalf("expected error containing %q", tt.errorMsg)
+			}
+			if !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("expected error containing %q, got %v", tt.errorMsg, err)
+			}
+		})
+	}
+}
+
+func TestLoadExampleYAML(t *testing.T) {
+	cfg, err := LoadConfig(filepath.Join("..", "..", "config.example.yaml"), "")
+	if err != nil {
+		t.Fatalf("failed to load example config: %v", err)
+	}
+	if cfg.Performance.Duration != Duration(5*time.Minute) {
+		t.Errorf("expected duration 5m, got %v", cfg.Performance.Duration)
+	}
+	if cfg.Producer.NumProducers != 5 {
+		t.Errorf("expected 5 producers, got %d", cfg.Producer.NumProducers)
+	}
+}
@@ -0,0 +1,426 @@
+package config
+
+import (
+	"encoding"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Source identifies the configuration layer an effective value came from
+type Source string
+
+// Configuration layers in increasing order of precedence
+const (
+	SourceDefault Source = "default"
+	SourceProfile Source = "profile"
+	SourceFile    Source = "file"
+	SourceEnv     Source = "env"
+	SourceFlag    Source = "flag"
+)
+
+// Origin records which layer set a value. Detail names the profile, file,
+// environment variable or flag.
+type Origin struct {
+	Source Source
+	Detail string
+}
+
+// String formats the origin for display (e.g., "env PRODUCER_MESSAGE_SIZE")
+func (o Origin) String() string {
+	if o.Detail == "" {
+		return string(o.Source)
+	}
+	return fmt.Sprintf("%s %s", o.Source, o.Detail)
+}
+
+// Origins maps setting keys to the layer that set their effective value
+type Origins map[string]Origin
+
+// Setting describes one configuration field and the names it is reachable by.
+// Names are derived from the JSON keys: producer.message_size is set by the
+// PRODUCER_MESSAGE_SIZE environment variable and the --producer.message-size flag.
+type Setting struct {
+	// Key is the section and field key used in config files (e.g., producer.message_size)
+	Key string
+
+	// Env is the environment variable (e.g., PRODUCER_MESSAGE_SIZE)
+	Env string
+
+	// Flag is the command-line flag name without dashes (e.g., producer.message-size)
+	Flag string
+
+	// index is the field index path within Config
+	index []int
+}
+
+// legacyEnvNames are environment variable names kept for compatibility.
+// The canonical name takes precedence when both are set.
+var legacyEnvNames = map[string]string{
+	"producer.num_producers":        "PRODUCER_NUM_WORKERS",
+	"producer.batching_max_size":    "PRODUCER_BATCH_SIZE",
+	"producer.compression_type":     "PRODUCER_COMPRESSION",
+	"performance.target_throughput": "PRODUCER_TARGET_RATE",
+	"consumer.num_consumers":        "CONSUMER_NUM_WORKERS",
+	"consumer.subscription_name":    "CONSUMER_SUBSCRIPTION",
+	"metrics.collection_interval":   "METRICS_UPDATE_INTERVAL",
+	"metrics.export_enabled":        "METRICS_ENABLE_EXPORT",
+}
+
+// settings lists every Config field in declaration order
+var settings = buildSettings()
+
+// buildSettings derives the settings from the JSON tags of Config and its sections
+func buildSettings() []Setting {
+	var result []Setting
+	cfgType := reflect.TypeOf(Config{})
+	for i := 0; i < cfgType.NumField(); i++ {
+		section := cfgType.Field(i)
+		sectionKey := jsonKey(section)
+		for j := 0; j < section.Type.NumField(); j++ {
+			field := section.Type.Field(j)
+			fieldKey := jsonKey(field)
+			if fieldKey == "" || fieldKey == "-" {
+				continue
+			}
+			result = append(result, Setting{
+				Key:   sectionKey + "." + fieldKey,
+				Env:   strings.ToUpper(sectionKey + "_" + fieldKey),
+				Flag:  sectionKey + "." + strings.ReplaceAll(fieldKey, "_", "-"),
+				index: []int{i, j},
+			})
+		}
+	}
+	return result
+}
+
+// jsonKey returns the JSON key of a struct field
+func jsonKey(field reflect.StructField) string {
+	return strings.Split(field.Tag.Get("json"), ",")[0]
+}
+
+// Settings returns all configuration settings in declaration order
+func Settings() []Setting {
+	return append([]Setting(nil), settings...)
+}
+
+// LookupSetting returns the setting with the given key (e.g., producer.message_size)
+func LookupSetting(key string) (Setting, bool) {
+	for _, s := range settings {
+		if s.Key == key {
+			return s, true
+		}
+	}
+	return Setting{}, false
+}
+
+// field returns the addressable field of the setting in cfg
+func (s Setting) field(cfg *Config) reflect.Value {
+	return reflect.ValueOf(cfg).Elem().FieldByIndex(s.index)
+}
+
+// isBool reports whether the setting holds a bool, which lets its flag omit the value
+func (s Setting) isBool() bool {
+	return reflect.TypeOf(Config{}).FieldByIndex(s.index).Type.Kind() == reflect.Bool
+}
+
+// Set parses value and stores it in cfg. Durations use Go syntax ("500ms", "1m")
+// and lists are comma-separated ("1,5,10").
+func (s Setting) Set(cfg *Config, value string) error {
+	field := s.field(cfg)
+	if err := setValue(field, strings.TrimSpace(value)); err != nil {
+		return fmt.Errorf("invalid value %q for %s: %w", value, s.Key, err)
+	}
+	return nil
+}
+
+// setValue parses value into the field according to its type
+func setValue(field reflect.Value, value string) error {
+	if u, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(value))
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int:
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(v))
+	case reflect.Bool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(v)
+	case reflect.Float64:
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(v)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.Float64 {
+			return fmt.Errorf("unsupported list type %s", field.Type())
+		}
+		values := []float64{}
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part == "" {
+				continue
+			}
+			v, err := strconv.ParseFloat(part, 64)
+			if err != nil {
+				return err
+			}
+			values = append(values, v)
+		}
+		field.Set(reflect.ValueOf(values))
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
+
+// Get formats the value of the setting in cfg in the syntax accepted by Set
+func (s Setting) Get(cfg *Config) string {
+	value := s.field(cfg).Interface()
+	switch v := value.(type) {
+	case fmt.Stringer:
+		return v.String()
+	case []float64:
+		parts := make([]string, len(v))
+		for i, f := range v {
+			parts[i] = strconv.FormatFloat(f, 'g', -1, 64)
+		}
+		return strings.Join(parts, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// FlagOverrides collects configuration values given on the command line
+type FlagOverrides struct {
+	fs     *flag.FlagSet
+	values []flagOverride
+}
+
+// flagOverride is one setting given on the command line
+type flagOverride struct {
+	setting Setting
+	flag    string
+	value   string
+}
+
+// RegisterFlags registers a --<section>.<field> flag for every setting on fs
+// (e.g., --producer.message-size) and returns the collected overrides for Load.
+func RegisterFlags(fs *flag.FlagSet) *FlagOverrides {
+	overrides := &FlagOverrides{fs: fs}
+	for _, s := range settings {
+		fs.Var(&settingFlag{overrides: overrides, setting: s, name: s.Flag}, s.Flag, fmt.Sprintf("Set %s (env %s)", s.Key, s.Env))
+	}
+	return overrides
+}
+
+// Alias registers a shorthand flag for the setting with the given key.
+// It panics on unknown keys since aliases are fixed at compile time.
+func (fo *FlagOverrides) Alias(name, key, usage string) {
+	s, ok := LookupSetting(key)
+	if !ok {
+		panic(fmt.Sprintf("config: unknown setting %s for flag %s", key, name))
+	}
+	fo.fs.Var(&settingFlag{overrides: fo, setting: s, name: name}, name, usage)
+}
+
+// settingFlag is the flag.Value of a setting flag
+type settingFlag struct {
+	overrides *FlagOverrides
+	setting   Setting
+	name      string
+	value     string
+}
+
+// String returns the value given on the command line
+func (f *settingFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.value
+}
+
+// Set validates the value and records it as an override
+func (f *settingFlag) Set(value string) error {
+	if err := f.setting.Set(DefaultConfig(""), value); err != nil {
+		return err
+	}
+	f.value = value
+	f.overrides.values = append(f.overrides.values, flagOverride{setting: f.setting, flag: f.name, value: value})
+	return nil
+}
+
+// IsBoolFlag lets bool settings be given without a value (e.g., --recreate-topic)
+func (f *settingFlag) IsBoolFlag() bool {
+	return f.setting.isBool()
+}
+
+// LoadOptions selects the layers Load merges on top of the defaults
+type LoadOptions struct {
+	// Profile is the built-in or custom profile to apply ("" = default)
+	Profile string
+
+	// File is the configuration file to load ("" = none)
+	File string
+
+	// LookupEnv reads environment variables (nil = os.LookupEnv)
+	LookupEnv func(string) (string, bool)
+
+	// Flags holds the values given on the command line (nil = none)
+	Flags *FlagOverrides
+}
+
+// Load resolves the effective configuration from the defaults, the profile, the config
+// file, environment variables and flags, in increasing order of precedence, and
+// validates the result. The returned origins record which layer set each value.
+// When only validation fails, the resolved configuration and origins are returned with
+// the error, so the layer that set the bad value can still be printed.
+func Load(opts LoadOptions) (*Config, Origins, error) {
+	cfg := DefaultConfig("")
+	origins := make(Origins, len(settings))
+	for _, s := range settings {
+		origins[s.Key] = Origin{Source: SourceDefault}
+	}
+
+	// Profile: values that differ from the defaults
+	if opts.Profile != "" && opts.Profile != "default" {
+		before := settingValues(cfg)
+		if err := ApplyProfile(cfg, opts.Profile); err != nil {
+			return nil, nil, err
+		}
+		for _, s := range settings {
+			if s.Get(cfg) != before[s.Key] {
+				origins[s.Key] = Origin{Source: SourceProfile, Detail: opts.Profile}
+			}
+		}
+	}
+
+	// File: every key present in the file
+	if opts.File != "" {
+		keys, err := loadConfigFile(opts.File, cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, key := range keys {
+			origins[key] = Origin{Source: SourceFile, Detail: opts.File}
+		}
+	}
+
+	// Environment
+	lookupEnv := opts.LookupEnv
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+	if err := applyEnv(cfg, lookupEnv, origins); err != nil {
+		return nil, nil, err
+	}
+
+	// Flags in command-line order, so the last occurrence wins
+	if opts.Flags != nil {
+		for _, override := range opts.Flags.values {
+			if err := override.setting.Set(cfg, override.value); err != nil {
+				return nil, nil, err
+			}
+			origins[override.setting.Key] = Origin{Source: SourceFlag, Detail: "--" + override.flag}
+		}
+	}
+
+	cfg.normalize()
+	if err := cfg.Validate(); err != nil {
+		return cfg, origins, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return cfg, origins, nil
+}
+
+// settingValues returns the formatted value of every setting keyed by setting key
+func settingValues(cfg *Config) map[string]string {
+	values := make(map[string]string, len(settings))
+	for _, s := range settings {
+		values[s.Key] = s.Get(cfg)
+	}
+	return values
+}
+
+// loadConfigFile decodes a JSON, YAML or TOML config file onto cfg and returns the setting keys it contains
+func loadConfigFile(path string, cfg *Config) ([]string, error) {
+	format, err := FormatForPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	keys, err := decodeConfig(data, format, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return keys, nil
+}
+
+// applyEnv sets every setting whose environment variable (or legacy alias) is set
+func applyEnv(cfg *Config, lookupEnv func(string) (string, bool), origins Origins) error {
+	for _, s := range settings {
+		name := s.Env
+		value, ok := lookupEnv(name)
+		if !ok || value == "" {
+			if legacy, hasLegacy := legacyEnvNames[s.Key]; hasLegacy {
+				name = legacy
+				value, ok = lookupEnv(name)
+			}
+		}
+		if !ok || value == "" {
+			continue
+		}
+		if err := s.Set(cfg, value); err != nil {
+			return fmt.Errorf("environment variable %s: %w", name, err)
+		}
+		if origins != nil {
+			origins[s.Key] = Origin{Source: SourceEnv, Detail: name}
+		}
+	}
+	return nil
+}
+
+// normalize canonicalizes case-insensitive values
+func (c *Config) normalize() {
+	c.Producer.CompressionType = strings.ToUpper(c.Producer.CompressionType)
+	c.Producer.SizeDistribution = strings.ToLower(c.Producer.SizeDistribution)
+	c.Producer.PayloadFill = strings.ToLower(c.Producer.PayloadFill)
+	c.Producer.ReplayFormat = strings.ToLower(c.Producer.ReplayFormat)
+	c.Producer.PayloadChecksum = strings.ToLower(c.Producer.PayloadChecksum)
+	c.Consumer.CaptureFormat = strings.ToLower(c.Consumer.CaptureFormat)
+	c.Consumer.CapturePayload = strings.ToLower(c.Consumer.CapturePayload)
+	c.Consumer.Mode = strings.ToLower(c.Consumer.Mode)
+}
+
+// PrintConfig writes every effective setting with its value and the layer that set it
+func PrintConfig(w io.Writer, cfg *Config, origins Origins) {
+	width := 0
+	for _, s := range settings {
+		width = max(width, len(s.Key))
+	}
+	for _, s := range settings {
+		value := s.Get(cfg)
+		if value == "" {
+			value = `""`
+		}
+		fmt.Fprintf(w, "%-*s = %-40s # %s\n", width, s.Key, value, origins[s.Key])
+	}
+}
@@ -0,0 +1,285 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// envMap returns a LookupEnv function backed by a map
+func envMap(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+}
+
+// parseFlags registers the setting flags on a new flag set and parses args
+func parseFlags(t *testing.T, args ...string) *FlagOverrides {
+	t.Helper()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	overrides := RegisterFlags(fs)
+	overrides.Alias("workers", "producer.num_producers", "Number of producer workers")
+	if err := fs.Parse(args); err != nil {
+		t.Fatalf("failed to parse flags: %v", err)
+	}
+	return overrides
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	data := `{"producer": {"message_size": 4096, "num_producers": 6}, "pulsar": {"topic": "file-topic"}}`
+	if err := os.WriteFile(configPath, []byte(data), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg, origins, err := Load(LoadOptions{
+		Profile: "burst",
+		File:    configPath,
+		LookupEnv: envMap(map[string]string{
+			"PRODUCER_MESSAGE_SIZE": "2048",
+			"PULSAR_TOPIC":          "env-topic",
+		}),
+		Flags: parseFlags(t, "--pulsar.topic", "flag-topic", "--workers", "9"),
+	})
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	tests := []struct {
+		key    string
+		value  string
+		origin string
+	}{
+		{"pulsar.service_url", "pulsar://localhost:6650", "default"},
+		{"performance.target_throughput", "10000", "profile burst"},
+		{"consumer.num_consumers", "5", "profile burst"},
+		{"producer.message_size", "2048", "env PRODUCER_MESSAGE_SIZE"},
+		{"producer.num_producers", "9", "flag --workers"},
+		{"pulsar.topic", "flag-topic", "flag --pulsar.topic"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			s, ok := LookupSetting(tt.key)
+			if !ok {
+				t.Fatalf("unknown setting %s", tt.key)
+			}
+			if got := s.Get(cfg); got != tt.value {
+				t.Errorf("expected value %s, got %s", tt.value, got)
+			}
+			if got := origins[tt.key].String(); got != tt.origin {
+				t.Errorf("expected origin %q, got %q", tt.origin, got)
+			}
+		})
+	}
+
+	// A file value that is not overridden keeps the file origin
+	cfg, origins, err = Load(LoadOptions{File: configPath, LookupEnv: envMap(nil)})
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if cfg.Producer.MessageSize != 4096 {
+		t.Errorf("expected message size 4096, got %d", cfg.Producer.MessageSize)
+	}
+	if origins["producer.message_size"].Source != SourceFile {
+		t.Errorf("expected file origin, got %s", origins["producer.message_size"])
+	}
+}
+
+func TestLoadLegacyEnv(t *testing.T) {
+	cfg, origins, err := Load(LoadOptions{LookupEnv: envMap(map[string]string{
+		"PRODUCER_NUM_WORKERS":   "4",
+		"PRODUCER_COMPRESSION":   "zstd",
+		"CONSUMER_NUM_WORKERS":   "3",
+		"CONSUMER_NUM_CONSUMERS": "7",
+	})})
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if cfg.Producer.NumProducers != 4 {
+		t.Errorf("expected 4 producers, got %d", cfg.Producer.NumProducers)
+	}
+	if cfg.Producer.CompressionType != CompressionZSTD {
+		t.Errorf("expected compression ZSTD, got %s", cfg.Producer.CompressionType)
+	}
+	if origins["producer.num_producers"].Detail != "PRODUCER_NUM_WORKERS" {
+		t.Errorf("expected origin PRODUCER_NUM_WORKERS, got %s", origins["producer.num_producers"])
+	}
+
+	// The canonical name wins over the legacy name
+	if cfg.Consumer.NumConsumers != 7 {
+		t.Errorf("expected 7 consumers, got %d", cfg.Consumer.NumConsumers)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     LoadOptions
+		errorMsg string
+	}{
+		{
+			name:     "invalid env value",
+			opts:     LoadOptions{LookupEnv: envMap(map[string]string{"PRODUCER_MESSAGE_SIZE": "big"})},
+			errorMsg: "PRODUCER_MESSAGE_SIZE",
+		},
+		{
+			name:     "invalid env duration",
+			opts:     LoadOptions{LookupEnv: envMap(map[string]string{"METRICS_COLLECTION_INTERVAL": "soon"})},
+			errorMsg: "metrics.collection_interval",
+		},
+		{
+			name:     "invalid result",
+			opts:     LoadOptions{LookupEnv: envMap(map[string]string{"PRODUCER_MESSAGE_SIZE": "0"})},
+			errorMsg: "invalid configuration",
+		},
+		{
+			name:     "unknown profile",
+			opts:     LoadOptions{Profile: "no-such-profile", LookupEnv: envMap(nil)},
+			errorMsg: "no-such-profile",
+		},
+		{
+			name:     "missing file",
+			opts:     LoadOptions{File: filepath.Join(t.TempDir(), "missing.json"), LookupEnv: envMap(nil)},
+			errorMsg: "failed to read config file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Load(tt.opts)
+			if err == nil {
+				t.Fatalf("expected error containing %q", tt.errorMsg)
+			}
+			if !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("expected error containing %q, got %v", tt.errorMsg, err)
+			}
+		})
+	}
+}
+
+func TestLoadInvalidKeepsOrigins(t *testing.T) {
+	cfg, origins, err := Load(LoadOptions{LookupEnv: envMap(map[string]string{"PRODUCER_MESSAGE_SIZE": "0"})})
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	if cfg == nil || origins == nil {
+		t.Fatal("expected the resolved configuration with the validation error")
+	}
+	if origin := origins["producer.message_size"]; origin.Source != SourceEnv {
+		t.Errorf("expected producer.message_size from env, got %+v", origin)
+	}
+}
+
+func TestFlagRejectsInvalidValue(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	RegisterFlags(fs)
+
+	if err := fs.Parse([]string{"--producer.message-size", "abc"}); err == nil {
+		t.Error("expected error for invalid flag value")
+	}
+}
+
+func TestBoolFlagWithoutValue(t *testing.T) {
+	cfg, _, err := Load(LoadOptions{LookupEnv: envMap(nil), Flags: parseFlags(t, "--pulsar.recreate-topic")})
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if !cfg.Pulsar.RecreateTopic {
+		t.Error("expected recreate topic to be enabled")
+	}
+}
+
+func TestEverySettingReachable(t *testing.T) {
+	defaults := DefaultConfig("")
+	for _, s := range Settings() {
+		t.Run(s.Key, func(t *testing.T) {
+			value := s.Get(defaults)
+			if value == "" {
+				value = "x"
+			}
+
+			_, origins, err := Load(LoadOptions{LookupEnv: envMap(map[string]string{s.Env: value})})
+			if err != nil {
+				t.Fatalf("env %s: %v", s.Env, err)
+			}
+			if origins[s.Key].Source != SourceEnv {
+				t.Errorf("env %s: expected env origin, got %s", s.Env, origins[s.Key])
+			}
+
+			_, origins, err = Load(LoadOptions{LookupEnv: envMap(nil), Flags: parseFlags(t, "--"+s.Flag+"="+value)})
+			if err != nil {
+				t.Fatalf("flag --%s: %v", s.Flag, err)
+			}
+			if origins[s.Key].Source != SourceFlag {
+				t.Errorf("flag --%s: expected flag origin, got %s", s.Flag, origins[s.Key])
+			}
+		})
+	}
+}
+
+func TestSettingSetGet(t *testing.T) {
+	tests := []struct {
+		key   string
+		value string
+		want  string
+	}{
+		{"producer.message_size", "2048", "2048"},
+		{"producer.batching_enabled", "false", "false"},
+		{"producer.send_timeout", "1m", "1m0s"},
+		{"producer.payload_compression_ratio", "0.3", "0.3"},
+		{"metrics.histogram_buckets", "1, 5,10", "1,5,10"},
+		{"pulsar.topic", "my-topic", "my-topic"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			s, ok := LookupSetting(tt.key)
+			if !ok {
+				t.Fatalf("unknown setting %s", tt.key)
+			}
+			cfg := DefaultConfig("")
+			if err := s.Set(cfg, tt.value); err != nil {
+				t.Fatalf("failed to set %s: %v", tt.key, err)
+			}
+			if got := s.Get(cfg); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPrintConfig(t *testing.T) {
+	cfg, origins, err := Load(LoadOptions{
+		LookupEnv: envMap(map[string]string{"PRODUCER_SEND_TIMEOUT": "10s"}),
+		Flags:     parseFlags(t, "--workers=3"),
+	})
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	var buf bytes.Buffer
+	PrintConfig(&buf, cfg, origins)
+	output := buf.String()
+
+	if lines := strings.Count(output, "\n"); lines != len(Settings()) {
+		t.Errorf("expected %d lines, got %d", len(Settings()), lines)
+	}
+	for _, want := range []string{"# env PRODUCER_SEND_TIMEOUT", "# flag --workers", "# default", "10s"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected output to contain %q", want)
+		}
+	}
+	if cfg.Producer.SendTimeout != Duration(10*time.Second) {
+		t.Errorf("expected send timeout 10s, got %v", cfg.Producer.SendTimeout)
+	}
+}
@@ -5,24 +5,26 @@ import (
 	"time"
 )
 
+// builtinProfiles maps the built-in profile names to the function applying them.
+// The default profile keeps the base defaults.
+var builtinProfiles = map[string]func(*Config){
+	"default":         func(*Config) {},
+	"low-latency":     applyLowLatencyProfile,
+	"high-throughput": applyHighThroughputProfile,
+	"burst":           applyBurstProfile,
+	"sustained":       applySustainedProfile,
+}
+
 // GetProfile returns a configuration for the specified profile name.
-// Available profiles: default, low-latency, high-throughput, burst, sustained.
+// Built-in profiles: default, low-latency, high-throughput, burst, sustained;
+// custom profiles are available after LoadProfiles.
 // Returns an error if the profile name is not recognized.
 func GetProfile(name string) (*Config, error) {
-	switch name {
-	case "default", "":
-		return DefaultProfile(), nil
-	case "low-latency":
-		return LowLatencyProfile(), nil
-	case "high-throughput":
-		return HighThroughputProfile(), nil
-	case "burst":
-		return BurstProfile(), nil
-	case "sustained":
-		return SustainedProfile(), nil
-	default:
-		return nil, fmt.Errorf("unknown profile: %s (available profiles: %v)", name, ListProfiles())
+	cfg := DefaultConfig("")
+	if err := ApplyProfile(cfg, name); err != nil {
+		return nil, err
 	}
+	return cfg, nil
 }
 
 // ListProfiles returns a list of all available profile names.
@@ -30,22 +32,21 @@ func ListProfiles() []string {
 	return GetAvailableProfiles()
 }
 
-// ApplyProfile applies a predefined performance profile to the configuration.
-// This modifies the configuration in place. If the profile name is not recognized,
-// the configuration remains unchanged.
-func ApplyProfile(cfg *Config, profile string) {
-	switch profile {
-	case "low-latency":
-		applyLowLatencyProfile(cfg)
-	case "high-throughput":
-		applyHighThroughputProfile(cfg)
-	case "burst":
-		applyBurstProfile(cfg)
-	case "sustained":
-		applySustainedProfile(cfg)
-	default:
-		// Keep default/current config
+// ApplyProfile applies a built-in or custom performance profile to the configuration.
+// This modifies the configuration in place. An empty name applies the default profile.
+// Returns an error if the profile name is not recognized.
+func ApplyProfile(cfg *Config, profile string) error {
+	if profile == "" {
+		return nil
 	}
+	if apply, ok := builtinProfiles[profile]; ok {
+		apply(cfg)
+		return nil
+	}
+	if custom := getCustomProfile(profile); custom != nil {
+		return custom.apply(cfg, nil)
+	}
+	return fmt.Errorf("unknown profile: %s (available profiles: %v)", profile, ListProfiles())
 }
 
 // DefaultProfile returns a balanced configuration suitable for general testing.
@@ -128,7 +129,7 @@ func applyLowLatencyProfile(cfg *Config) {
 	cfg.Performance.RateLimitEnabled = true
 
 	// Metrics
-	cfg.Metrics.CollectionInterval = 100 * time.Millisecond
+	cfg.Metrics.CollectionInterval = Duration(100 * time.Millisecond)
 	cfg.Metrics.HistogramBuckets = []float64{0.1, 0.5, 1, 2, 5, 10, 25, 50, 100}
 }
 
@@ -152,7 +153,7 @@ func applyHighThroughputProfile(cfg *Config) {
 	cfg.Performance.RateLimitEnabled = false
 
 	// Metrics
-	cfg.Metrics.CollectionInterval = 1 * time.Second
+	cfg.Metrics.CollectionInterval = Duration(1 * time.Second)
 }
 
 // applyBurstProfile simulates bursty traffic patterns.
@@ -172,10 +173,10 @@ func applyBurstProfile(cfg *Config) {
 	// Performance settings
 	cfg.Performance.TargetThroughput = 10000
 	cfg.Performance.RateLimitEnabled = true
-	cfg.Performance.Duration = 5 * time.Minute
+	cfg.Performance.Duration = Duration(5 * time.Minute)
 
 	// Metrics
-	cfg.Metrics.CollectionInterval = 500 * time.Millisecond
+	cfg.Metrics.CollectionInterval = Duration(500 * time.Millisecond)
 }
 
 // applySustainedProfile for long-running sustained load.
@@ -198,19 +199,20 @@ func applySustainedProfile(cfg *Config) {
 	cfg.Performance.Duration = 0 // unlimited
 
 	// Metrics
-	cfg.Metrics.CollectionInterval = 1 * time.Second
+	cfg.Metrics.CollectionInterval = Duration(1 * time.Second)
 	cfg.Metrics.ExportEnabled = true
 }
 
-// GetAvailableProfiles returns a list of available profile names
+// GetAvailableProfiles returns the built-in profile names followed by the custom ones
 func GetAvailableProfiles() []string {
-	return []string{
+	profiles := []string{
 		"default",
 		"low-latency",
 		"high-throughput",
 		"burst",
 		"sustained",
 	}
+	return append(profiles, customProfileNames()...)
 }
 
 // GetProfileDescription returns a description for a profile
@@ -222,5 +224,11 @@ func GetProfileDescription(profile string) string {
 		"burst":           "Simulates bursty traffic with rate limiting",
 		"sustained":       "Long-running sustained load with metrics export enabled",
 	}
-	return descriptions[profile]
+	if desc, ok := descriptions[profile]; ok {
+		return desc
+	}
+	if custom := getCustomProfile(profile); custom != nil {
+		return custom.summary()
+	}
+	return ""
 }
@@ -114,7 +114,7 @@ func TestLowLatencyProfile(t *testing.T) {
 	if !cfg.Performance.RateLimitEnabled {
 		t.Error("rate limiting should be enabled for low latency")
 	}
-	if cfg.Metrics.CollectionInterval != 100*time.Millisecond {
+	if cfg.Metrics.CollectionInterval != Duration(100*time.Millisecond) {
 		t.Errorf("expected 100ms collection interval, got %v", cfg.Metrics.CollectionInterval)
 	}
 }
@@ -172,10 +172,10 @@ func TestBurstProfile(t *testing.T) {
 	if !cfg.Performance.RateLimitEnabled {
 		t.Error("rate limiting should be enabled for burst")
 	}
-	if cfg.Performance.Duration != 5*time.Minute {
+	if cfg.Performance.Duration != Duration(5*time.Minute) {
 		t.Errorf("expected 5 minute duration, got %v", cfg.Performance.Duration)
 	}
-	if cfg.Metrics.CollectionInterval != 500*time.Millisecond {
+	if cfg.Metrics.CollectionInterval != Duration(500*time.Millisecond) {
 		t.Errorf("expected 500ms collection interval, got %v", cfg.Metrics.CollectionInterval)
 	}
 }
@@ -234,25 +234,28 @@ func TestApplyProfile(t *testing.T) {
 				}
 			},
 		},
-		{
-			name:    "apply unknown profile",
-			profile: "unknown",
-			verify: func(t *testing.T, cfg *Config) {
-				// Should not modify the config
-				if cfg.Producer.NumProducers != 1 {
-					t.Errorf("unknown profile should not change config")
-				}
-			},
-		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cfg := DefaultConfig("")
-			ApplyProfile(cfg, tt.profile)
+			if err := ApplyProfile(cfg, tt.profile); err != nil {
+				t.Fatalf("ApplyProfile(%q) error = %v", tt.profile, err)
+			}
 			tt.verify(t, cfg)
 		})
 	}
+
+	t.Run("apply unknown profile", func(t *testing.T) {
+		cfg := DefaultConfig("")
+		if err := ApplyProfile(cfg, "unknown"); err == nil {
+			t.Error("expected error for unknown profile, got nil")
+		}
+		// Should not modify the config
+		if cfg.Producer.NumProducers != 1 {
+			t.Errorf("unknown profile should not change config")
+		}
+	})
 }
 
 func TestGetAvailableProfiles(t *testing.T) {
@@ -340,6 +343,6 @@ func BenchmarkApplyProfile(b *testing.B) {
 	cfg := DefaultConfig("")
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		ApplyProfile(cfg, "low-latency")
+		_ = ApplyProfile(cfg, "low-latency")
 	}
 }
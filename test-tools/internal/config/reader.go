@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Consumer modes
+const (
+	ConsumerModeSubscription = "subscription"
+	ConsumerModeReader       = "reader"
+	ConsumerModeBacklog      = "backlog"
+)
+
+// Reader start positions
+const (
+	ReaderStartEarliest = "earliest"
+	ReaderStartLatest   = "latest"
+	ReaderStartMessage  = "message"
+	ReaderStartTime     = "time"
+)
+
+// ReaderStart is a parsed reader start position
+type ReaderStart struct {
+	Position  string // ReaderStartEarliest, ReaderStartLatest, ReaderStartMessage or ReaderStartTime
+	LedgerID  int64  // message ID to start at (ReaderStartMessage)
+	EntryID   int64
+	Partition int32     // partition of the message ID, -1 when not given
+	Time      time.Time // publish time to start at (ReaderStartTime)
+}
+
+// ParseReaderStart parses a reader start position: earliest, latest, a message ID in the
+// <ledger>:<entry>[:<partition>] form messages are logged with, an RFC 3339 timestamp, or
+// a duration such as 10m meaning that long before now.
+func ParseReaderStart(s string, now time.Time) (ReaderStart, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "" || strings.EqualFold(s, ReaderStartEarliest):
+		return ReaderStart{Position: ReaderStartEarliest}, nil
+	case strings.EqualFold(s, ReaderStartLatest):
+		return ReaderStart{Position: ReaderStartLatest}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return ReaderStart{Position: ReaderStartTime, Time: t}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		if d < 0 {
+			return ReaderStart{}, fmt.Errorf("duration before now must be non-negative, got %v", d)
+		}
+		return ReaderStart{Position: ReaderStartTime, Time: now.Add(-d)}, nil
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 && len(parts) != 3 {
+		return ReaderStart{}, fmt.Errorf("%q is not earliest, latest, a message ID, a timestamp or a duration", s)
+	}
+	start := ReaderStart{Position: ReaderStartMessage, Partition: -1}
+	var err error
+	if start.LedgerID, err = strconv.ParseInt(parts[0], 10, 64); err != nil || start.LedgerID < 0 {
+		return ReaderStart{}, fmt.Errorf("invalid ledger ID in message ID %q", s)
+	}
+	if start.EntryID, err = strconv.ParseInt(parts[1], 10, 64); err != nil || start.EntryID < 0 {
+		return ReaderStart{}, fmt.Errorf("invalid entry ID in message ID %q", s)
+	}
+	if len(parts) == 3 {
+		partition, err := strconv.ParseInt(parts[2], 10, 32)
+		if err != nil || partition < -1 {
+			return ReaderStart{}, fmt.Errorf("invalid partition in message ID %q", s)
+		}
+		start.Partition = int32(partition)
+	}
+	return start, nil
+}
+
+// ReaderMode reports whether consumers read the topic with readers instead of a subscription
+func (c *ConsumerConfig) ReaderMode() bool {
+	return c.Mode == ConsumerModeReader
+}
+
+// validateReader checks the consumer mode and, in reader mode, the start position and topics
+func (c *Config) validateReader() error {
+	switch c.Consumer.Mode {
+	case "", ConsumerModeSubscription, ConsumerModeReader, ConsumerModeBacklog:
+	default:
+		return fmt.Errorf("invalid consumer mode: %s (must be one of: subscription, reader, backlog)", c.Consumer.Mode)
+	}
+	if !c.Consumer.ReaderMode() {
+		return nil
+	}
+	if _, err := ParseReaderStart(c.Consumer.ReaderStart, time.Now()); err != nil {
+		return fmt.Errorf("invalid reader start position: %w", err)
+	}
+	if c.Consumer.TopicsPattern != "" {
+		return fmt.Errorf("topics pattern is not supported in reader mode")
+	}
+	if topics := len(c.Pulsar.TopicNames()); c.Consumer.NumConsumers > 0 && c.Consumer.NumConsumers < topics {
+		return fmt.Errorf("reader mode needs at least one worker per topic, got %d workers for %d topics",
+			c.Consumer.NumConsumers, topics)
+	}
+	return nil
+}
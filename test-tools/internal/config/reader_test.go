@@ -0,0 +1,87 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseReaderStart(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		input   string
+		want    ReaderStart
+		wantErr string
+	}{
+		{"", ReaderStart{Position: ReaderStartEarliest}, ""},
+		{"Earliest", ReaderStart{Position: ReaderStartEarliest}, ""},
+		{"latest", ReaderStart{Position: ReaderStartLatest}, ""},
+		{"12:34", ReaderStart{Position: ReaderStartMessage, LedgerID: 12, EntryID: 34, Partition: -1}, ""},
+		{"12:34:2", ReaderStart{Position: ReaderStartMessage, LedgerID: 12, EntryID: 34, Partition: 2}, ""},
+		{"2025-01-01T11:00:00Z", ReaderStart{Position: ReaderStartTime, Time: now.Add(-time.Hour)}, ""},
+		{"10m", ReaderStart{Position: ReaderStartTime, Time: now.Add(-10 * time.Minute)}, ""},
+		{"-10m", ReaderStart{}, "must be non-negative"},
+		{"oldest", ReaderStart{}, "is not earliest, latest"},
+		{"x:34", ReaderStart{}, "invalid ledger ID"},
+		{"12:-1", ReaderStart{}, "invalid entry ID"},
+		{"12:34:p", ReaderStart{}, "invalid partition"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseReaderStart(tt.input, now)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValidateReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr string
+	}{
+		{"defaults", func(c *Config) {}, ""},
+		{"reader", func(c *Config) { c.Consumer.Mode = ConsumerModeReader; c.Consumer.ReaderStart = "latest" }, ""},
+		{"invalid mode", func(c *Config) { c.Consumer.Mode = "browse" }, "invalid consumer mode"},
+		{"invalid start", func(c *Config) { c.Consumer.Mode = ConsumerModeReader; c.Consumer.ReaderStart = "oldest" }, "invalid reader start position"},
+		{"start ignored for subscriptions", func(c *Config) { c.Consumer.ReaderStart = "oldest" }, ""},
+		{"pattern", func(c *Config) {
+			c.Consumer.Mode = ConsumerModeReader
+			c.Consumer.TopicsPattern = "persistent://public/default/perf-test-.*"
+		}, "not supported in reader mode"},
+		{"too few workers", func(c *Config) {
+			c.Consumer.Mode = ConsumerModeReader
+			c.Pulsar.TopicCount = 3
+			c.Consumer.NumConsumers = 2
+		}, "at least one worker per topic"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig("")
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
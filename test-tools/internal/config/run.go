@@ -0,0 +1,94 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// RunIDAuto requests a freshly generated run ID
+const RunIDAuto = "auto"
+
+// runIDTimeFormat is the timestamp layout embedded in generated run IDs
+const runIDTimeFormat = "20060102150405"
+
+// runSuffixPrefix separates the base topic/subscription name from the run ID
+const runSuffixPrefix = "-run-"
+
+var (
+	// validRunID restricts run IDs to characters allowed in topic and subscription names
+	validRunID = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+	// generatedRunSuffix matches the suffix of names created with a generated run ID
+	generatedRunSuffix = regexp.MustCompile(`-run-(\d{14})-[0-9a-f]{4}$`)
+)
+
+// NewRunID generates a run ID of the form <UTC timestamp>-<4 random hex chars>,
+// e.g. 20250101120000-3fa2. The timestamp lets cleanup find stale runs by age.
+func NewRunID() string {
+	return newRunIDAt(time.Now())
+}
+
+// newRunIDAt generates a run ID for the given time
+func newRunIDAt(t time.Time) string {
+	b := make([]byte, 2)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("%s-%s", t.UTC().Format(runIDTimeFormat), hex.EncodeToString(b))
+}
+
+// RunSuffix returns the suffix appended to topic and subscription names for a run ID
+func RunSuffix(runID string) string {
+	return runSuffixPrefix + runID
+}
+
+// ParseRunTime extracts the start time encoded in a name that ends with a generated run ID suffix.
+// It returns false for names without a generated run ID.
+func ParseRunTime(name string) (time.Time, bool) {
+	match := generatedRunSuffix.FindStringSubmatch(name)
+	if match == nil {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(runIDTimeFormat, match[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// ApplyRunID isolates the run by suffixing the topic and subscription names with the run ID.
+// Every topic of an explicit topic list is suffixed; generated topics derive their names from
+// the suffixed topic (see PulsarConfig.TopicNames).
+// A RunID of "auto" is replaced with a generated ID first. Names that already carry the
+// suffix are left unchanged so the method is safe to call more than once.
+func (c *Config) ApplyRunID() error {
+	if c.Pulsar.RunID == "" {
+		return nil
+	}
+	if c.Pulsar.RunID == RunIDAuto {
+		c.Pulsar.RunID = NewRunID()
+	}
+	if !validRunID.MatchString(c.Pulsar.RunID) {
+		return fmt.Errorf("invalid run ID %q: only letters, digits, '-' and '_' are allowed", c.Pulsar.RunID)
+	}
+
+	suffix := RunSuffix(c.Pulsar.RunID)
+	if !strings.HasSuffix(c.Pulsar.Topic, suffix) {
+		c.Pulsar.Topic += suffix
+	}
+	if c.Pulsar.Topics != "" {
+		topics := c.Pulsar.TopicNames()
+		for i, topic := range topics {
+			if !strings.HasSuffix(topic, suffix) {
+				topics[i] = topic + suffix
+			}
+		}
+		c.Pulsar.Topics = strings.Join(topics, ",")
+	}
+	if c.Consumer.SubscriptionName != "" && !strings.HasSuffix(c.Consumer.SubscriptionName, suffix) {
+		c.Consumer.SubscriptionName += suffix
+	}
+	return nil
+}
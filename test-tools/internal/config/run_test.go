@@ -0,0 +1,95 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewRunID(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	id := newRunIDAt(now)
+
+	if !strings.HasPrefix(id, "20250102030405-") {
+		t.Errorf("newRunIDAt() = %s, want prefix 20250102030405-", id)
+	}
+
+	started, ok := ParseRunTime("perf-test" + RunSuffix(id))
+	if !ok {
+		t.Fatalf("ParseRunTime() could not parse generated run ID %s", id)
+	}
+	if !started.Equal(now) {
+		t.Errorf("ParseRunTime() = %v, want %v", started, now)
+	}
+
+	if NewRunID() == NewRunID() {
+		t.Error("NewRunID() should not return the same ID twice")
+	}
+}
+
+func TestParseRunTime(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		wantOK bool
+	}{
+		{"generated run topic", "persistent://public/default/perf-test-run-20250101120000-3fa2", true},
+		{"custom run ID", "persistent://public/default/perf-test-run-nightly", false},
+		{"plain topic", "persistent://public/default/perf-test", false},
+		{"partition of run topic", "persistent://public/default/perf-test-run-20250101120000-3fa2-partition-0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := ParseRunTime(tt.input); ok != tt.wantOK {
+				t.Errorf("ParseRunTime(%s) ok = %v, want %v", tt.input, ok, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestApplyRunID(t *testing.T) {
+	cfg := DefaultConfig("")
+	cfg.Pulsar.RunID = "nightly"
+
+	if err := cfg.ApplyRunID(); err != nil {
+		t.Fatalf("ApplyRunID() error = %v", err)
+	}
+	if cfg.Pulsar.Topic != "persistent://public/default/perf-test-run-nightly" {
+		t.Errorf("Topic = %s, want run suffix", cfg.Pulsar.Topic)
+	}
+	if cfg.Consumer.SubscriptionName != "perf-test-sub-run-nightly" {
+		t.Errorf("SubscriptionName = %s, want run suffix", cfg.Consumer.SubscriptionName)
+	}
+
+	// Applying twice must not add the suffix again
+	if err := cfg.ApplyRunID(); err != nil {
+		t.Fatalf("second ApplyRunID() error = %v", err)
+	}
+	if strings.Count(cfg.Pulsar.Topic, "-run-") != 1 {
+		t.Errorf("Topic = %s, suffix applied twice", cfg.Pulsar.Topic)
+	}
+
+	// No run ID leaves names untouched
+	plain := DefaultConfig("")
+	if err := plain.ApplyRunID(); err != nil || plain.Pulsar.Topic != "persistent://public/default/perf-test" {
+		t.Errorf("ApplyRunID() without run ID changed topic to %s (err %v)", plain.Pulsar.Topic, err)
+	}
+
+	// auto generates a parseable ID
+	auto := DefaultConfig("")
+	auto.Pulsar.RunID = RunIDAuto
+	if err := auto.ApplyRunID(); err != nil {
+		t.Fatalf("ApplyRunID() with auto error = %v", err)
+	}
+	if _, ok := ParseRunTime(auto.Pulsar.Topic); !ok {
+		t.Errorf("auto run topic %s should carry a generated run ID", auto.Pulsar.Topic)
+	}
+
+	// Invalid characters are rejected
+	invalid := DefaultConfig("")
+	invalid.Pulsar.RunID = "bad/id"
+	if err := invalid.ApplyRunID(); err == nil {
+		t.Error("ApplyRunID() should reject run IDs with '/'")
+	}
+}
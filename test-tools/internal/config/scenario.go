@@ -0,0 +1,209 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Load shape constants
+const (
+	ShapeConstant = "constant"
+	ShapeRamp     = "ramp"
+)
+
+// Scenario describes a multi-phase test plan that runs its phases back to back.
+//
+// Example YAML scenario:
+//
+//	name: spike-test
+//	description: Warm up, hold steady, spike and recover
+//	phases:
+//	  - name: warmup
+//	    duration: 30s
+//	    producers: 2
+//	    consumers: 2
+//	    shape: ramp
+//	    rate: 100
+//	    rate_end: 1000
+//	  - name: steady
+//	    duration: 2m
+//	    rate: 1000
+//	  - name: spike
+//	    duration: 30s
+//	    producers: 8
+//	    rate: 10000
+//	    message_size: 4096
+//	    compression: ZSTD
+//	  - name: recover
+//	    duration: 1m
+//	    producers: 2
+//	    rate: 1000
+//
+// The same structure can be written as JSON with the same keys.
+type Scenario struct {
+	// Name identifies the scenario in logs and reports
+	Name string `json:"name" yaml:"name"`
+
+	// Description is a free-form summary of the scenario
+	Description string `json:"description,omitempty" yaml:"description"`
+
+	// Phases are executed in order
+	Phases []ScenarioPhase `json:"phases" yaml:"phases"`
+}
+
+// ScenarioPhase describes one step of a scenario.
+// Zero values (and an omitted rate) keep the setting of the previous phase.
+type ScenarioPhase struct {
+	// Name identifies the phase in logs and reports
+	Name string `json:"name" yaml:"name"`
+
+	// Duration is how long the phase runs (e.g., "30s", "5m")
+	Duration time.Duration `json:"duration" yaml:"duration"`
+
+	// Producers is the number of producer workers (0 = keep)
+	Producers int `json:"producers,omitempty" yaml:"producers"`
+
+	// Consumers is the number of consumer workers (0 = keep)
+	Consumers int `json:"consumers,omitempty" yaml:"consumers"`
+
+	// Shape is the load shape: constant (default) or ramp
+	Shape string `json:"shape,omitempty" yaml:"shape"`
+
+	// Rate is the target rate in msg/s, or the start rate of a ramp (0 = unlimited, omitted = keep)
+	Rate *int `json:"rate,omitempty" yaml:"rate"`
+
+	// RateEnd is the rate reached at the end of a ramp
+	RateEnd int `json:"rate_end,omitempty" yaml:"rate_end"`
+
+	// MessageSize is the payload size in bytes (0 = keep)
+	MessageSize int `json:"message_size,omitempty" yaml:"message_size"`
+
+	// Compression is the compression type (empty = keep)
+	Compression string `json:"compression,omitempty" yaml:"compression"`
+}
+
+// LoadScenario loads and validates a scenario from a YAML or JSON file
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario file: %w", err)
+	}
+
+	scenario, err := ParseScenario(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse scenario file %s: %w", path, err)
+	}
+	return scenario, nil
+}
+
+// ParseScenario parses and validates a scenario. JSON is a subset of YAML, so both
+// formats share the YAML decoder. Unknown keys are rejected to catch typos.
+func ParseScenario(data []byte) (*Scenario, error) {
+	var scenario Scenario
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&scenario); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("scenario is empty")
+		}
+		return nil, err
+	}
+
+	for i := range scenario.Phases {
+		phase := &scenario.Phases[i]
+		phase.Shape = strings.ToLower(phase.Shape)
+		phase.Compression = strings.ToUpper(phase.Compression)
+		if phase.Name == "" {
+			phase.Name = fmt.Sprintf("phase-%d", i+1)
+		}
+	}
+
+	if err := scenario.Validate(); err != nil {
+		return nil, err
+	}
+	return &scenario, nil
+}
+
+// Validate checks the scenario for invalid phases
+func (s *Scenario) Validate() error {
+	if len(s.Phases) == 0 {
+		return fmt.Errorf("scenario must have at least one phase")
+	}
+
+	validCompressionTypes := map[string]bool{
+		"":                true,
+		CompressionNone:   true,
+		CompressionLZ4:    true,
+		CompressionZLIB:   true,
+		CompressionZSTD:   true,
+		CompressionSNAPPY: true,
+	}
+
+	for i, phase := range s.Phases {
+		if phase.Duration <= 0 {
+			return fmt.Errorf("phase %d (%s): duration must be positive, got %v", i+1, phase.Name, phase.Duration)
+		}
+		if phase.Producers < 0 || phase.Consumers < 0 {
+			return fmt.Errorf("phase %d (%s): worker counts must be non-negative", i+1, phase.Name)
+		}
+		if phase.Rate != nil && *phase.Rate < 0 {
+			return fmt.Errorf("phase %d (%s): rate must be non-negative, got %d", i+1, phase.Name, *phase.Rate)
+		}
+		if phase.MessageSize < 0 {
+			return fmt.Errorf("phase %d (%s): message size must be non-negative, got %d", i+1, phase.Name, phase.MessageSize)
+		}
+		if !validCompressionTypes[phase.Compression] {
+			return fmt.Errorf("phase %d (%s): invalid compression type: %s (must be one of: NONE, LZ4, ZLIB, ZSTD, SNAPPY)", i+1, phase.Name, phase.Compression)
+		}
+
+		switch phase.Shape {
+		case "", ShapeConstant:
+		case ShapeRamp:
+			if phase.Rate == nil {
+				return fmt.Errorf("phase %d (%s): ramp requires a start rate", i+1, phase.Name)
+			}
+			if phase.RateEnd <= 0 {
+				return fmt.Errorf("phase %d (%s): ramp requires a positive rate_end, got %d", i+1, phase.Name, phase.RateEnd)
+			}
+		default:
+			return fmt.Errorf("phase %d (%s): invalid shape: %s (must be one of: constant, ramp)", i+1, phase.Name, phase.Shape)
+		}
+	}
+
+	return nil
+}
+
+// TotalDuration returns the combined duration of all phases
+func (s *Scenario) TotalDuration() time.Duration {
+	var total time.Duration
+	for _, phase := range s.Phases {
+		total += phase.Duration
+	}
+	return total
+}
+
+// RateAt returns the target rate at the given offset into the phase.
+// Ramps interpolate linearly from Rate to RateEnd; ok is false when the phase keeps the previous rate.
+func (p *ScenarioPhase) RateAt(elapsed time.Duration) (rate int, ok bool) {
+	if p.Rate == nil {
+		return 0, false
+	}
+	if p.Shape != ShapeRamp {
+		return *p.Rate, true
+	}
+
+	progress := float64(elapsed) / float64(p.Duration)
+	if progress < 0 {
+		progress = 0
+	} else if progress > 1 {
+		progress = 1
+	}
+	return *p.Rate + int(float64(p.RateEnd-*p.Rate)*progress), true
+}
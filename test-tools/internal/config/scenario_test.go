@@ -0,0 +1,127 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseScenario(t *testing.T) {
+	yamlScenario := `
+name: spike
+phases:
+  - name: warmup
+    duration: 30s
+    producers: 2
+    shape: ramp
+    rate: 100
+    rate_end: 1000
+  - duration: 1m
+    rate: 0
+    compression: zstd
+`
+	jsonScenario := `{
+  "name": "spike",
+  "phases": [
+    {"name": "warmup", "duration": "30s", "producers": 2, "shape": "ramp", "rate": 100, "rate_end": 1000},
+    {"duration": "1m", "rate": 0, "compression": "zstd"}
+  ]
+}`
+
+	for name, data := range map[string]string{"yaml": yamlScenario, "json": jsonScenario} {
+		t.Run(name, func(t *testing.T) {
+			scenario, err := ParseScenario([]byte(data))
+			if err != nil {
+				t.Fatalf("ParseScenario() error = %v", err)
+			}
+			if scenario.Name != "spike" || len(scenario.Phases) != 2 {
+				t.Fatalf("unexpected scenario: %+v", scenario)
+			}
+
+			warmup := scenario.Phases[0]
+			if warmup.Duration != 30*time.Second || warmup.Producers != 2 || warmup.Shape != ShapeRamp || warmup.RateEnd != 1000 {
+				t.Errorf("unexpected warmup phase: %+v", warmup)
+			}
+
+			steady := scenario.Phases[1]
+			if steady.Name != "phase-2" {
+				t.Errorf("unnamed phase got name %q, want phase-2", steady.Name)
+			}
+			if steady.Rate == nil || *steady.Rate != 0 {
+				t.Errorf("explicit rate 0 should be kept as unlimited, got %v", steady.Rate)
+			}
+			if steady.Compression != CompressionZSTD {
+				t.Errorf("Compression = %q, want %q", steady.Compression, CompressionZSTD)
+			}
+			if scenario.TotalDuration() != 90*time.Second {
+				t.Errorf("TotalDuration() = %v, want 1m30s", scenario.TotalDuration())
+			}
+		})
+	}
+}
+
+func TestParseScenarioErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"empty", "", "empty"},
+		{"no phases", "name: x", "at least one phase"},
+		{"unknown key", "phases:\n  - duration: 1s\n    rat: 10", "rat"},
+		{"missing duration", "phases:\n  - rate: 10", "duration must be positive"},
+		{"negative workers", "phases:\n  - duration: 1s\n    producers: -1", "non-negative"},
+		{"negative rate", "phases:\n  - duration: 1s\n    rate: -5", "rate must be non-negative"},
+		{"invalid compression", "phases:\n  - duration: 1s\n    compression: gzip", "invalid compression type"},
+		{"invalid shape", "phases:\n  - duration: 1s\n    shape: sine", "invalid shape"},
+		{"ramp without rate", "phases:\n  - duration: 1s\n    shape: ramp\n    rate_end: 10", "start rate"},
+		{"ramp without end", "phases:\n  - duration: 1s\n    shape: ramp\n    rate: 10", "rate_end"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseScenario([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseScenario() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScenarioPhaseRateAt(t *testing.T) {
+	rate := func(v int) *int { return &v }
+
+	tests := []struct {
+		name    string
+		phase   ScenarioPhase
+		elapsed time.Duration
+		want    int
+		ok      bool
+	}{
+		{"keep", ScenarioPhase{Duration: time.Minute}, 0, 0, false},
+		{"constant", ScenarioPhase{Duration: time.Minute, Rate: rate(500)}, 30 * time.Second, 500, true},
+		{"ramp start", ScenarioPhase{Duration: time.Minute, Shape: ShapeRamp, Rate: rate(100), RateEnd: 1100}, 0, 100, true},
+		{"ramp middle", ScenarioPhase{Duration: time.Minute, Shape: ShapeRamp, Rate: rate(100), RateEnd: 1100}, 30 * time.Second, 600, true},
+		{"ramp down", ScenarioPhase{Duration: time.Minute, Shape: ShapeRamp, Rate: rate(1000), RateEnd: 200}, 45 * time.Second, 400, true},
+		{"ramp past end", ScenarioPhase{Duration: time.Minute, Shape: ShapeRamp, Rate: rate(100), RateEnd: 1100}, 2 * time.Minute, 1100, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.phase.RateAt(tt.elapsed)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("RateAt(%v) = %d, %v, want %d, %v", tt.elapsed, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestLoadScenarioExample(t *testing.T) {
+	scenario, err := LoadScenario("../../scenario.example.yaml")
+	if err != nil {
+		t.Fatalf("LoadScenario() error = %v", err)
+	}
+	if len(scenario.Phases) != 4 || scenario.Phases[2].Name != "spike" {
+		t.Errorf("unexpected example scenario: %+v", scenario)
+	}
+}
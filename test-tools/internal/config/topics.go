@@ -0,0 +1,110 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pulsar-local-lab/perf-test/internal/generator"
+)
+
+// TopicNames returns the topics producers send to and consumers subscribe to: the Topics
+// list, TopicCount topics generated from Topic (<topic>-0 to <topic>-<N-1>), or Topic alone.
+// Generated topics keep the run ID suffix last so cleanup recognizes them as run topics.
+func (p *PulsarConfig) TopicNames() []string {
+	if p.Topics != "" {
+		var topics []string
+		for _, topic := range strings.Split(p.Topics, ",") {
+			if topic = strings.TrimSpace(topic); topic != "" {
+				topics = append(topics, topic)
+			}
+		}
+		return topics
+	}
+	if p.TopicCount > 0 {
+		base, suffix := p.Topic, ""
+		if p.RunID != "" && strings.HasSuffix(p.Topic, RunSuffix(p.RunID)) {
+			suffix = RunSuffix(p.RunID)
+			base = strings.TrimSuffix(p.Topic, suffix)
+		}
+		topics := make([]string, p.TopicCount)
+		for i := range topics {
+			topics[i] = fmt.Sprintf("%s-%d%s", base, i, suffix)
+		}
+		return topics
+	}
+	return []string{p.Topic}
+}
+
+// TopicWeightList returns the relative weight of each topic in TopicNames, or nil when
+// producers spread messages evenly. Weights are ignored for a single topic.
+func (p *PulsarConfig) TopicWeightList() ([]float64, error) {
+	topics := p.TopicNames()
+	if p.TopicWeights == "" || len(topics) < 2 {
+		return nil, nil
+	}
+
+	entries := strings.Split(p.TopicWeights, ",")
+	if len(entries) != len(topics) {
+		return nil, fmt.Errorf("expected %d topic weights, one per topic, got %d", len(topics), len(entries))
+	}
+	weights := make([]float64, len(entries))
+	var total float64
+	for i, entry := range entries {
+		weight, err := strconv.ParseFloat(strings.TrimSpace(entry), 64)
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid weight %q for topic %s", entry, topics[i])
+		}
+		weights[i] = weight
+		total += weight
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("topic weights must have a positive total")
+	}
+	return weights, nil
+}
+
+// NewTopicChoice builds the weighted choice of the topic in TopicNames each message is sent to
+func (p *PulsarConfig) NewTopicChoice() (*generator.WeightedChoice, error) {
+	weights, err := p.TopicWeightList()
+	if err != nil {
+		return nil, fmt.Errorf("invalid topic weights: %w", err)
+	}
+	return generator.NewWeightedChoice(len(p.TopicNames()), weights)
+}
+
+// MultiTopic reports whether the workload spans more than one topic, in which case
+// metrics are also tracked per topic
+func (c *Config) MultiTopic() bool {
+	return len(c.Pulsar.TopicNames()) > 1 || c.Consumer.TopicsPattern != ""
+}
+
+// TrackPartitions reports whether metrics are tracked per partition. Only a single
+// partitioned topic is tracked: partition indexes repeat across topics, so partition 0 of
+// every topic would share one row.
+func (c *Config) TrackPartitions() bool {
+	return c.Pulsar.TopicPartitions > 0 && !c.MultiTopic()
+}
+
+// validateTopics checks the topic list, topic weights and consumer topic pattern
+func (c *Config) validateTopics() error {
+	if c.Pulsar.TopicCount < 0 {
+		return fmt.Errorf("topic count must be non-negative, got %d", c.Pulsar.TopicCount)
+	}
+	if len(c.Pulsar.TopicNames()) == 0 {
+		return fmt.Errorf("topic list is empty")
+	}
+	if _, err := c.Pulsar.TopicWeightList(); err != nil {
+		return fmt.Errorf("invalid topic weights: %w", err)
+	}
+	if c.Consumer.TopicsPattern != "" {
+		if _, err := regexp.Compile(c.Consumer.TopicsPattern); err != nil {
+			return fmt.Errorf("invalid topics pattern: %w", err)
+		}
+	}
+	if c.Consumer.PatternDiscoveryInterval < 0 {
+		return fmt.Errorf("pattern discovery interval must be non-negative, got %v", c.Consumer.PatternDiscoveryInterval)
+	}
+	return nil
+}
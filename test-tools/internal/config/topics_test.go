@@ -0,0 +1,156 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTopicNames(t *testing.T) {
+	tests := []struct {
+		name   string
+		pulsar PulsarConfig
+		want   []string
+	}{
+		{
+			name:   "single topic",
+			pulsar: PulsarConfig{Topic: "perf-test"},
+			want:   []string{"perf-test"},
+		},
+		{
+			name:   "generated topics",
+			pulsar: PulsarConfig{Topic: "perf-test", TopicCount: 3},
+			want:   []string{"perf-test-0", "perf-test-1", "perf-test-2"},
+		},
+		{
+			name:   "explicit list wins",
+			pulsar: PulsarConfig{Topic: "perf-test", TopicCount: 3, Topics: "orders, payments,,refunds"},
+			want:   []string{"orders", "payments", "refunds"},
+		},
+		{
+			name:   "generated run topics",
+			pulsar: PulsarConfig{Topic: "perf-test-run-nightly", TopicCount: 2, RunID: "nightly"},
+			want:   []string{"perf-test-0-run-nightly", "perf-test-1-run-nightly"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.pulsar.TopicNames(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TopicNames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTopicWeightList(t *testing.T) {
+	tests := []struct {
+		name    string
+		pulsar  PulsarConfig
+		want    []float64
+		wantErr string
+	}{
+		{"uniform", PulsarConfig{Topic: "t", TopicCount: 3}, nil, ""},
+		{"weighted", PulsarConfig{Topic: "t", TopicCount: 3, TopicWeights: "5, 1,0"}, []float64{5, 1, 0}, ""},
+		{"single topic ignores weights", PulsarConfig{Topic: "t", TopicWeights: "x"}, nil, ""},
+		{"count mismatch", PulsarConfig{Topics: "a,b", TopicWeights: "1"}, nil, "expected 2 topic weights"},
+		{"negative", PulsarConfig{Topics: "a,b", TopicWeights: "1,-1"}, nil, "invalid weight"},
+		{"zero total", PulsarConfig{Topics: "a,b", TopicWeights: "0,0"}, nil, "positive total"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.pulsar.TopicWeightList()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TopicWeightList() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyRunIDTopicList(t *testing.T) {
+	cfg := DefaultConfig("")
+	cfg.Pulsar.Topics = "orders,payments"
+	cfg.Pulsar.RunID = "nightly"
+
+	for i := 0; i < 2; i++ {
+		if err := cfg.ApplyRunID(); err != nil {
+			t.Fatalf("ApplyRunID() error = %v", err)
+		}
+	}
+	want := []string{"orders-run-nightly", "payments-run-nightly"}
+	if got := cfg.Pulsar.TopicNames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("TopicNames() = %v, want %v", got, want)
+	}
+}
+
+func TestTrackPartitions(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		want   bool
+	}{
+		{"non-partitioned", func(c *Config) {}, false},
+		{"partitioned", func(c *Config) { c.Pulsar.TopicPartitions = 4 }, true},
+		{"several partitioned topics", func(c *Config) {
+			c.Pulsar.TopicPartitions = 4
+			c.Pulsar.TopicCount = 3
+		}, false},
+		{"topic pattern", func(c *Config) {
+			c.Pulsar.TopicPartitions = 4
+			c.Consumer.TopicsPattern = "persistent://public/default/perf-.*"
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig("")
+			tt.modify(cfg)
+			if got := cfg.TrackPartitions(); got != tt.want {
+				t.Errorf("TrackPartitions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTopics(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr string
+	}{
+		{"defaults", func(c *Config) {}, ""},
+		{"negative count", func(c *Config) { c.Pulsar.TopicCount = -1 }, "topic count must be non-negative"},
+		{"empty list", func(c *Config) { c.Pulsar.Topics = " , " }, "topic list is empty"},
+		{"bad weights", func(c *Config) { c.Pulsar.TopicCount = 2; c.Pulsar.TopicWeights = "1,x" }, "invalid topic weights"},
+		{"valid pattern", func(c *Config) { c.Consumer.TopicsPattern = "persistent://public/default/perf-test-.*" }, ""},
+		{"invalid pattern", func(c *Config) { c.Consumer.TopicsPattern = "perf-test-(" }, "invalid topics pattern"},
+		{"negative discovery", func(c *Config) { c.Consumer.PatternDiscoveryInterval = -1 }, "pattern discovery interval"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig("")
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
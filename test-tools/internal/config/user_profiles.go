@@ -0,0 +1,204 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ProfileDirEnv overrides the directory custom profiles are loaded from
+const ProfileDirEnv = "PULSAR_PERF_PROFILE_DIR"
+
+// Profile is a custom performance profile loaded from a JSON file.
+//
+// Example profile (~/.config/pulsar-perf/profiles/big-batches.json):
+//
+//	{
+//	  "description": "High throughput with 64 KB messages",
+//	  "extends": "high-throughput",
+//	  "config": {
+//	    "producer": {"message_size": 65536, "compression_type": "ZSTD"},
+//	    "performance": {"target_throughput": 20000}
+//	  }
+//	}
+//
+// The config object uses the keys of a configuration file and only needs the
+// fields that differ from the extended profile (default when extends is empty).
+type Profile struct {
+	// Name is the profile name (defaults to the file name without extension)
+	Name string `json:"name"`
+
+	// Description is shown by --list-profiles
+	Description string `json:"description"`
+
+	// Extends names a built-in or custom profile to start from
+	Extends string `json:"extends"`
+
+	// Config holds the overridden configuration fields
+	Config json.RawMessage `json:"config"`
+
+	// path is the file the profile was loaded from
+	path string
+}
+
+var (
+	customProfilesMu sync.RWMutex
+	customProfiles   = map[string]*Profile{}
+)
+
+// ProfileDir returns the directory custom profiles are loaded from:
+// $PULSAR_PERF_PROFILE_DIR, or pulsar-perf/profiles in the user config directory
+// (~/.config/pulsar-perf/profiles on Linux).
+func ProfileDir() string {
+	if dir := os.Getenv(ProfileDirEnv); dir != "" {
+		return dir
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "pulsar-perf", "profiles")
+}
+
+// LoadProfiles loads all *.json profiles from dir, replacing previously loaded custom profiles.
+// A missing directory is not an error. Every profile is resolved and validated, so broken
+// files, unknown parents and inheritance cycles are reported here rather than on use.
+func LoadProfiles(dir string) error {
+	if dir == "" {
+		return nil
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return fmt.Errorf("failed to list profiles in %s: %w", dir, err)
+	}
+
+	profiles := make(map[string]*Profile, len(paths))
+	for _, path := range paths {
+		profile, err := loadProfile(path)
+		if err != nil {
+			return err
+		}
+		if _, ok := builtinProfiles[profile.Name]; ok {
+			return fmt.Errorf("profile %s in %s: name is reserved for a built-in profile", profile.Name, path)
+		}
+		if other, ok := profiles[profile.Name]; ok {
+			return fmt.Errorf("profile %s is defined in both %s and %s", profile.Name, other.path, path)
+		}
+		profiles[profile.Name] = profile
+	}
+
+	customProfilesMu.Lock()
+	previous := customProfiles
+	customProfiles = profiles
+	customProfilesMu.Unlock()
+
+	// Resolve every profile now that all parents are registered
+	for _, name := range customProfileNames() {
+		cfg, err := GetProfile(name)
+		if err == nil {
+			err = cfg.Validate()
+		}
+		if err != nil {
+			customProfilesMu.Lock()
+			customProfiles = previous
+			customProfilesMu.Unlock()
+			return fmt.Errorf("profile %s in %s: %w", name, profiles[name].path, err)
+		}
+	}
+
+	return nil
+}
+
+// loadProfile parses a single profile file
+func loadProfile(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile file: %w", err)
+	}
+
+	var profile Profile
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&profile); err != nil {
+		return nil, fmt.Errorf("failed to parse profile file %s: %w", path, err)
+	}
+
+	if profile.Name == "" {
+		profile.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	profile.path = path
+	return &profile, nil
+}
+
+// apply applies the extended profile chain and then the profile's own overrides.
+// seen holds the profiles already being applied to detect inheritance cycles.
+func (p *Profile) apply(cfg *Config, seen []string) error {
+	for _, name := range seen {
+		if name == p.Name {
+			return fmt.Errorf("profile inheritance cycle: %s -> %s", strings.Join(seen, " -> "), p.Name)
+		}
+	}
+	seen = append(seen, p.Name)
+
+	if p.Extends != "" {
+		if apply, ok := builtinProfiles[p.Extends]; ok {
+			apply(cfg)
+		} else if parent := getCustomProfile(p.Extends); parent != nil {
+			if err := parent.apply(cfg, seen); err != nil {
+				return err
+			}
+		} else {
+			return fmt.Errorf("profile %s extends unknown profile: %s", p.Name, p.Extends)
+		}
+	}
+
+	if len(p.Config) == 0 {
+		return nil
+	}
+
+	// Decoding onto the existing config only overwrites the fields present in the profile
+	decoder := json.NewDecoder(bytes.NewReader(p.Config))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(cfg); err != nil {
+		return fmt.Errorf("invalid config in profile %s: %w", p.Name, err)
+	}
+	return nil
+}
+
+// summary returns the description shown in profile listings
+func (p *Profile) summary() string {
+	desc := p.Description
+	if desc == "" {
+		desc = "Custom profile"
+	}
+	if p.Extends != "" {
+		desc = fmt.Sprintf("%s (extends %s)", desc, p.Extends)
+	}
+	return desc
+}
+
+// getCustomProfile returns the loaded custom profile with the given name, or nil
+func getCustomProfile(name string) *Profile {
+	customProfilesMu.RLock()
+	defer customProfilesMu.RUnlock()
+	return customProfiles[name]
+}
+
+// customProfileNames returns the names of the loaded custom profiles in sorted order
+func customProfileNames() []string {
+	customProfilesMu.RLock()
+	defer customProfilesMu.RUnlock()
+
+	names := make([]string, 0, len(customProfiles))
+	for name := range customProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
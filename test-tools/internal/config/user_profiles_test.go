@@ -0,0 +1,169 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeProfiles writes profile files into a new directory and unloads custom profiles after the test
+func writeProfiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write profile: %v", err)
+		}
+	}
+	t.Cleanup(func() {
+		if err := LoadProfiles(t.TempDir()); err != nil {
+			t.Errorf("failed to unload profiles: %v", err)
+		}
+	})
+	return dir
+}
+
+func TestLoadProfiles(t *testing.T) {
+	dir := writeProfiles(t, map[string]string{
+		"big-batches.json": `{
+			"description": "High throughput with large messages",
+			"extends": "high-throughput",
+			"config": {"producer": {"message_size": 65536, "compression_type": "ZSTD"}}
+		}`,
+		"big-batches-limited.json": `{
+			"extends": "big-batches",
+			"config": {"performance": {"target_throughput": 2000, "rate_limit_enabled": true}}
+		}`,
+		"plain.json": `{"name": "renamed", "config": {"consumer": {"subscription_type": "Failover"}}}`,
+		"notes.txt":  `not a profile`,
+	})
+
+	if err := LoadProfiles(dir); err != nil {
+		t.Fatalf("LoadProfiles() error = %v", err)
+	}
+
+	cfg, err := GetProfile("big-batches-limited")
+	if err != nil {
+		t.Fatalf("GetProfile() error = %v", err)
+	}
+	// Inherited from high-throughput, big-batches and the profile itself
+	if cfg.Producer.BatchingMaxSize != 10000 || cfg.Producer.NumProducers != 10 {
+		t.Errorf("high-throughput settings not inherited: %+v", cfg.Producer)
+	}
+	if cfg.Producer.MessageSize != 65536 || cfg.Producer.CompressionType != CompressionZSTD {
+		t.Errorf("big-batches overrides not inherited: %+v", cfg.Producer)
+	}
+	if cfg.Performance.TargetThroughput != 2000 || !cfg.Performance.RateLimitEnabled {
+		t.Errorf("own overrides not applied: %+v", cfg.Performance)
+	}
+
+	renamed, err := GetProfile("renamed")
+	if err != nil {
+		t.Fatalf("GetProfile(renamed) error = %v", err)
+	}
+	if renamed.Consumer.SubscriptionType != SubscriptionFailover || renamed.Producer.NumProducers != 1 {
+		t.Errorf("profile without extends should start from defaults, got %+v", renamed.Consumer)
+	}
+
+	profiles := GetAvailableProfiles()
+	want := []string{"default", "low-latency", "high-throughput", "burst", "sustained", "big-batches", "big-batches-limited", "renamed"}
+	if strings.Join(profiles, ",") != strings.Join(want, ",") {
+		t.Errorf("GetAvailableProfiles() = %v, want %v", profiles, want)
+	}
+
+	if desc := GetProfileDescription("big-batches"); desc != "High throughput with large messages (extends high-throughput)" {
+		t.Errorf("GetProfileDescription(big-batches) = %q", desc)
+	}
+	if desc := GetProfileDescription("renamed"); desc != "Custom profile" {
+		t.Errorf("GetProfileDescription(renamed) = %q", desc)
+	}
+
+	loaded, err := LoadConfig("", "big-batches")
+	if err != nil || loaded.Producer.MessageSize != 65536 {
+		t.Errorf("LoadConfig with custom profile = %+v, %v", loaded, err)
+	}
+}
+
+func TestLoadProfilesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name:    "unknown parent",
+			files:   map[string]string{"a.json": `{"extends": "missing"}`},
+			wantErr: "extends unknown profile: missing",
+		},
+		{
+			name: "cycle",
+			files: map[string]string{
+				"a.json": `{"extends": "b"}`,
+				"b.json": `{"extends": "a"}`,
+			},
+			wantErr: "cycle",
+		},
+		{
+			name:    "built-in name",
+			files:   map[string]string{"burst.json": `{}`},
+			wantErr: "reserved",
+		},
+		{
+			name: "duplicate name",
+			files: map[string]string{
+				"a.json": `{"name": "same"}`,
+				"b.json": `{"name": "same"}`,
+			},
+			wantErr: "defined in both",
+		},
+		{
+			name:    "unknown profile key",
+			files:   map[string]string{"a.json": `{"extend": "burst"}`},
+			wantErr: "extend",
+		},
+		{
+			name:    "unknown config key",
+			files:   map[string]string{"a.json": `{"config": {"producer": {"message_sise": 10}}}`},
+			wantErr: "message_sise",
+		},
+		{
+			name:    "invalid result",
+			files:   map[string]string{"a.json": `{"config": {"producer": {"message_size": 0}}}`},
+			wantErr: "message size must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeProfiles(t, tt.files)
+			err := LoadProfiles(dir)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("LoadProfiles() error = %v, want error containing %q", err, tt.wantErr)
+			}
+			if len(customProfileNames()) != 0 {
+				t.Errorf("failed load should keep previous profiles, got %v", customProfileNames())
+			}
+		})
+	}
+}
+
+func TestLoadProfilesMissingDir(t *testing.T) {
+	if err := LoadProfiles(filepath.Join(t.TempDir(), "missing")); err != nil {
+		t.Errorf("LoadProfiles() on missing dir error = %v, want nil", err)
+	}
+}
+
+func TestProfileDir(t *testing.T) {
+	t.Setenv(ProfileDirEnv, "/tmp/profiles")
+	if dir := ProfileDir(); dir != "/tmp/profiles" {
+		t.Errorf("ProfileDir() = %q, want env override", dir)
+	}
+
+	t.Setenv(ProfileDirEnv, "")
+	t.Setenv("XDG_CONFIG_HOME", "/home/test/.config")
+	t.Setenv("HOME", "/home/test")
+	if dir := ProfileDir(); dir != filepath.Join("/home/test/.config", "pulsar-perf", "profiles") {
+		t.Errorf("ProfileDir() = %q, want user config dir", dir)
+	}
+}
@@ -0,0 +1,81 @@
+package generator
+
+import (
+	"fmt"
+	"hash/crc32"
+	"strconv"
+	"strings"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// Payload checksum algorithms
+const (
+	ChecksumNone   = "none"
+	ChecksumCRC32C = "crc32c"
+	ChecksumXXHash = "xxhash"
+)
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// checksumFunc returns the digest function of an algorithm
+func checksumFunc(algorithm string) (func([]byte) uint64, error) {
+	switch algorithm {
+	case ChecksumCRC32C:
+		return func(payload []byte) uint64 {
+			return uint64(crc32.Checksum(payload, crc32cTable))
+		}, nil
+	case ChecksumXXHash:
+		return xxhash.Sum64, nil
+	default:
+		return nil, fmt.Errorf("unknown checksum algorithm: %s (must be one of: none, crc32c, xxhash)", algorithm)
+	}
+}
+
+// Checksummer computes payload checksums with a single algorithm. Checksums are
+// self-describing ("<algorithm>:<hex digest>") so consumers can verify them without
+// knowing the producer's settings.
+type Checksummer struct {
+	algorithm string
+	sum       func([]byte) uint64
+}
+
+// NewChecksummer creates a checksummer for an algorithm (crc32c, xxhash), or returns nil
+// for none or an empty algorithm
+func NewChecksummer(algorithm string) (*Checksummer, error) {
+	if algorithm == "" || algorithm == ChecksumNone {
+		return nil, nil
+	}
+	sum, err := checksumFunc(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	return &Checksummer{algorithm: algorithm, sum: sum}, nil
+}
+
+// Sum returns the checksum of a payload
+func (c *Checksummer) Sum(payload []byte) string {
+	return c.algorithm + ":" + strconv.FormatUint(c.sum(payload), 16)
+}
+
+// VerifyChecksum checks a payload against a checksum returned by Checksummer.Sum.
+// A malformed checksum is reported as an error like a mismatch, since either means the
+// message was corrupted.
+func VerifyChecksum(checksum string, payload []byte) error {
+	algorithm, digest, ok := strings.Cut(checksum, ":")
+	if !ok {
+		return fmt.Errorf("malformed checksum %q", checksum)
+	}
+	sum, err := checksumFunc(algorithm)
+	if err != nil {
+		return err
+	}
+	expected, err := strconv.ParseUint(digest, 16, 64)
+	if err != nil {
+		return fmt.Errorf("malformed checksum %q", checksum)
+	}
+	if actual := sum(payload); actual != expected {
+		return fmt.Errorf("%s checksum mismatch: expected %x, got %x", algorithm, expected, actual)
+	}
+	return nil
+}
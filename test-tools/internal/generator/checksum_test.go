@@ -0,0 +1,109 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewChecksummer(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		wantNil   bool
+		errMsg    string
+	}{
+		{"", true, ""},
+		{ChecksumNone, true, ""},
+		{ChecksumCRC32C, false, ""},
+		{ChecksumXXHash, false, ""},
+		{"md5", true, "unknown checksum algorithm"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			c, err := NewChecksummer(tt.algorithm)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("expected error containing %q, got %v", tt.errMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (c == nil) != tt.wantNil {
+				t.Errorf("expected nil checksummer: %v, got %v", tt.wantNil, c)
+			}
+		})
+	}
+}
+
+func TestChecksummerSum(t *testing.T) {
+	c, err := NewChecksummer(ChecksumCRC32C)
+	if err != nil {
+		t.Fatalf("failed to create checksummer: %v", err)
+	}
+	// Standard CRC-32C check value
+	if got := c.Sum([]byte("123456789")); got != "crc32c:e3069283" {
+		t.Errorf("expected crc32c:e3069283, got %s", got)
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	payload := []byte("the quick brown fox jumps over the lazy dog")
+	corrupted := append([]byte(nil), payload...)
+	corrupted[7] ^= 0x01
+
+	for _, algorithm := range []string{ChecksumCRC32C, ChecksumXXHash} {
+		c, err := NewChecksummer(algorithm)
+		if err != nil {
+			t.Fatalf("failed to create %s checksummer: %v", algorithm, err)
+		}
+		checksum := c.Sum(payload)
+
+		if err := VerifyChecksum(checksum, payload); err != nil {
+			t.Errorf("%s: expected intact payload to verify, got %v", algorithm, err)
+		}
+		if err := VerifyChecksum(checksum, corrupted); err == nil || !strings.Contains(err.Error(), "mismatch") {
+			t.Errorf("%s: expected mismatch for corrupted payload, got %v", algorithm, err)
+		}
+	}
+
+	malformed := []struct {
+		name     string
+		checksum string
+		errMsg   string
+	}{
+		{"no algorithm", "e3069283", "malformed checksum"},
+		{"bad digest", "crc32c:xyz", "malformed checksum"},
+		{"unknown algorithm", "md5:e3069283", "unknown checksum algorithm"},
+	}
+	for _, tt := range malformed {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyChecksum(tt.checksum, payload)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func BenchmarkVerifyChecksum(b *testing.B) {
+	payload := make([]byte, 64<<10)
+	NewPCGFiller(1).Fill(payload)
+
+	for _, algorithm := range []string{ChecksumCRC32C, ChecksumXXHash} {
+		c, err := NewChecksummer(algorithm)
+		if err != nil {
+			b.Fatalf("failed to create %s checksummer: %v", algorithm, err)
+		}
+		checksum := c.Sum(payload)
+		b.Run(algorithm, func(b *testing.B) {
+			b.SetBytes(int64(len(payload)))
+			for i := 0; i < b.N; i++ {
+				if err := VerifyChecksum(checksum, payload); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
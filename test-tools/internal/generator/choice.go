@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+)
+
+// WeightedChoice picks indexes from 0 to n-1 with probability proportional to their weight,
+// e.g. the topic each message is sent to
+type WeightedChoice struct {
+	n          int
+	weights    []float64
+	cumulative []float64 // nil for a uniform choice
+}
+
+// NewWeightedChoice creates a choice over n indexes. Nil weights pick every index
+// equally often; otherwise there must be one non-negative weight per index.
+func NewWeightedChoice(n int, weights []float64) (*WeightedChoice, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("weighted choice requires at least one option")
+	}
+	c := &WeightedChoice{n: n}
+	if weights == nil {
+		return c, nil
+	}
+	if len(weights) != n {
+		return nil, fmt.Errorf("expected %d weights, got %d", n, len(weights))
+	}
+
+	c.weights = append([]float64(nil), weights...)
+	c.cumulative = make([]float64, n)
+	total := 0.0
+	for i, w := range weights {
+		if w < 0 {
+			return nil, fmt.Errorf("invalid weight %g for option %d", w, i)
+		}
+		total += w
+		c.cumulative[i] = total
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("weighted choice requires a positive total weight")
+	}
+	return c, nil
+}
+
+// Next returns the next index
+func (c *WeightedChoice) Next(rng *rand.Rand) int {
+	if c.cumulative == nil {
+		if c.n == 1 {
+			return 0
+		}
+		return rng.IntN(c.n)
+	}
+	r := rng.Float64() * c.cumulative[c.n-1]
+	i := sort.SearchFloat64s(c.cumulative, r)
+	if i >= c.n {
+		i = c.n - 1
+	}
+	// Skip zero-weight options that share a cumulative value with their successor
+	for c.weights[i] == 0 && i < c.n-1 {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,74 @@
+package generator
+
+import (
+	"math"
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+func TestNewWeightedChoice(t *testing.T) {
+	tests := []struct {
+		name    string
+		n       int
+		weights []float64
+		errMsg  string
+	}{
+		{"uniform", 3, nil, ""},
+		{"weighted", 3, []float64{5, 1, 0}, ""},
+		{"no options", 0, nil, "at least one option"},
+		{"count mismatch", 3, []float64{1, 1}, "expected 3 weights"},
+		{"negative", 2, []float64{1, -1}, "invalid weight"},
+		{"zero total", 2, []float64{0, 0}, "positive total"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewWeightedChoice(tt.n, tt.weights)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("expected error containing %q, got %v", tt.errMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestWeightedChoiceNext(t *testing.T) {
+	tests := []struct {
+		name    string
+		n       int
+		weights []float64
+		want    []float64 // expected share of each index
+	}{
+		{"uniform", 4, nil, []float64{0.25, 0.25, 0.25, 0.25}},
+		{"weighted", 3, []float64{6, 3, 1}, []float64{0.6, 0.3, 0.1}},
+		{"zero weights", 4, []float64{0, 1, 0, 1}, []float64{0, 0.5, 0, 0.5}},
+		{"single", 1, nil, []float64{1}},
+	}
+
+	const samples = 100000
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewWeightedChoice(tt.n, tt.weights)
+			if err != nil {
+				t.Fatalf("failed to create choice: %v", err)
+			}
+			rng := rand.New(rand.NewPCG(1, 2))
+			counts := make([]int, tt.n)
+			for i := 0; i < samples; i++ {
+				counts[c.Next(rng)]++
+			}
+			for i, want := range tt.want {
+				got := float64(counts[i]) / samples
+				if math.Abs(got-want) > 0.01 {
+					t.Errorf("index %d: expected share %.2f, got %.3f", i, want, got)
+				}
+			}
+		})
+	}
+}
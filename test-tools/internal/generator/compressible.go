@@ -0,0 +1,81 @@
+package generator
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// compressibleBlockSize is the block size over which GenerateCompressiblePayloadTo
+// mixes random and repeated bytes. It is well below the window of every codec Pulsar
+// supports, so each block's filler compresses against the filler before it.
+const compressibleBlockSize = 1024
+
+// fillerPattern is the repeated text that makes up the compressible part of a payload
+var fillerPattern = []byte("perftest")
+
+// ValidateCompressionRatio checks that ratio is a usable target compression ratio:
+// greater than 0 and at most 1
+func ValidateCompressionRatio(ratio float64) error {
+	if ratio <= 0 || ratio > 1 {
+		return fmt.Errorf("compression ratio %g must be greater than 0 and at most 1", ratio)
+	}
+	return nil
+}
+
+// GenerateCompressiblePayload generates a payload of the specified size that
+// compresses to roughly ratio times its size (compressed size / original size).
+// See GenerateCompressiblePayloadTo.
+//
+// Example:
+//
+//	payload := GenerateCompressiblePayload(4096, 0.3) // compresses to ~1.2 KB
+func GenerateCompressiblePayload(size int, ratio float64) []byte {
+	return GenerateCompressiblePayloadTo(make([]byte, size), ratio)
+}
+
+// GenerateCompressiblePayloadTo fills the provided buffer so that it compresses to
+// roughly ratio times its size, making compression codec comparisons meaningful.
+// Random payloads never compress and pattern payloads compress almost entirely;
+// real data lies in between.
+//
+// Every 1 KB block starts with ratio × block size random bytes, which no codec can
+// shrink, followed by repeated filler text, which every codec reduces to a few
+// bytes. A ratio of 1 produces a fully random payload. Returns the same buffer for
+// convenience in chaining operations.
+//
+// The achieved ratio is slightly above the target because of codec framing, and
+// varies a little between LZ4, ZLIB, ZSTD and Snappy.
+//
+// Example with PayloadPool:
+//
+//	pool := NewPayloadPool(1024, 100)
+//	buf := pool.Get()
+//	GenerateCompressiblePayloadTo(buf, 0.5)
+//	// ... use buffer ...
+//	pool.Put(buf)
+func GenerateCompressiblePayloadTo(buf []byte, ratio float64) []byte {
+	rand.Read(buf)
+	mixFiller(buf, ratio)
+	return buf
+}
+
+// mixFiller overwrites the end of every block of a random buffer with filler text,
+// keeping ratio of each block random
+func mixFiller(buf []byte, ratio float64) {
+	if ratio >= 1 {
+		return
+	}
+	for start := 0; start < len(buf); start += compressibleBlockSize {
+		block := buf[start:min(start+compressibleBlockSize, len(buf))]
+		randomLen := int(float64(len(block))*ratio + 0.5)
+		fillPattern(block[randomLen:])
+	}
+}
+
+// fillPattern fills buf with repetitions of fillerPattern
+func fillPattern(buf []byte) {
+	n := copy(buf, fillerPattern)
+	for n < len(buf) {
+		n += copy(buf[n:], buf[:n])
+	}
+}
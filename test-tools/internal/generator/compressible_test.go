@@ -0,0 +1,94 @@
+package generator
+
+import (
+	"bytes"
+	"compress/flate"
+	"math"
+	"testing"
+)
+
+// deflateRatio returns the compressed size of payload divided by its size
+func deflateRatio(t *testing.T, payload []byte) float64 {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		t.Fatalf("failed to create writer: %v", err)
+	}
+	w.Write(payload)
+	w.Close()
+	return float64(buf.Len()) / float64(len(payload))
+}
+
+func TestGenerateCompressiblePayload(t *testing.T) {
+	tests := []struct {
+		name      string
+		size      int
+		ratio     float64
+		tolerance float64
+	}{
+		{"ratio 0.1", 64 * 1024, 0.1, 0.05},
+		{"ratio 0.3", 64 * 1024, 0.3, 0.05},
+		{"ratio 0.5", 64 * 1024, 0.5, 0.05},
+		{"ratio 0.8", 64 * 1024, 0.8, 0.05},
+		{"ratio 1", 64 * 1024, 1, 0.05},
+		{"partial block", 2500, 0.3, 0.05},
+		// Codec framing weighs more on small payloads
+		{"small payload", 300, 0.5, 0.1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := GenerateCompressiblePayload(tt.size, tt.ratio)
+			if len(payload) != tt.size {
+				t.Fatalf("expected payload size %d, got %d", tt.size, len(payload))
+			}
+
+			got := deflateRatio(t, payload)
+			if math.Abs(got-tt.ratio) > tt.tolerance {
+				t.Errorf("expected compression ratio near %.2f, got %.3f", tt.ratio, got)
+			}
+		})
+	}
+}
+
+func TestGenerateCompressiblePayloadToVaries(t *testing.T) {
+	buf1 := GenerateCompressiblePayloadTo(make([]byte, 1024), 0.5)
+	buf2 := GenerateCompressiblePayloadTo(make([]byte, 1024), 0.5)
+	if bytes.Equal(buf1, buf2) {
+		t.Error("two compressible payloads should not be identical")
+	}
+}
+
+func TestValidateCompressionRatio(t *testing.T) {
+	tests := []struct {
+		ratio     float64
+		wantError bool
+	}{
+		{0.3, false},
+		{1, false},
+		{0, true},
+		{-0.5, true},
+		{1.5, true},
+	}
+
+	for _, tt := range tests {
+		err := ValidateCompressionRatio(tt.ratio)
+		if tt.wantError && err == nil {
+			t.Errorf("expected error for ratio %g", tt.ratio)
+		}
+		if !tt.wantError && err != nil {
+			t.Errorf("unexpected error for ratio %g: %v", tt.ratio, err)
+		}
+	}
+}
+
+func BenchmarkGenerateCompressiblePayloadTo(b *testing.B) {
+	buf := make([]byte, 1024)
+	b.SetBytes(1024)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		GenerateCompressiblePayloadTo(buf, 0.5)
+	}
+}
@@ -0,0 +1,158 @@
+package generator
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	mathrand "math/rand/v2"
+	"sync"
+)
+
+// Payload fill strategies
+const (
+	FillCrypto   = "crypto"
+	FillChaCha8  = "chacha8"
+	FillPCG      = "pcg"
+	FillRotating = "rotating"
+)
+
+// rotatingBufferSize is the size of the pre-generated random data RotatingFillers
+// copy payloads from
+const rotatingBufferSize = 4 << 20
+
+// rotatingData is the random data shared by all RotatingFillers, generated on first use
+var rotatingData = sync.OnceValue(func() []byte {
+	data := make([]byte, rotatingBufferSize)
+	NewPCGFiller(0).Fill(data)
+	return data
+})
+
+// Filler fills payload buffers. Fillers are not safe for concurrent use; each
+// producer worker creates its own.
+type Filler interface {
+	// Fill fills the entire buffer
+	Fill(buf []byte)
+}
+
+// NewFiller creates a filler for the named strategy. seed makes the seeded strategies
+// reproducible; workers should pass distinct seeds so their payloads differ.
+//
+// Strategies, from slowest to fastest:
+//   - crypto: crypto/rand, the default and the only unseeded strategy
+//   - chacha8: seeded ChaCha8 stream, cryptographically strong but without syscalls
+//   - pcg: seeded PCG, the fastest generator that still fills every byte
+//   - rotating: copies from a pre-generated random buffer and writes only a unique header
+func NewFiller(strategy string, seed uint64) (Filler, error) {
+	switch strategy {
+	case "", FillCrypto:
+		return CryptoFiller{}, nil
+	case FillChaCha8:
+		return NewChaCha8Filler(seed), nil
+	case FillPCG:
+		return NewPCGFiller(seed), nil
+	case FillRotating:
+		return NewRotatingFiller(seed), nil
+	default:
+		return nil, fmt.Errorf("unknown payload fill strategy: %s (must be one of: crypto, chacha8, pcg, rotating)", strategy)
+	}
+}
+
+// CryptoFiller fills buffers from crypto/rand, like GenerateRandomPayloadTo.
+// Unlike the other fillers it is safe for concurrent use.
+type CryptoFiller struct{}
+
+// Fill fills buf with cryptographically secure random bytes
+func (CryptoFiller) Fill(buf []byte) {
+	rand.Read(buf)
+}
+
+// ChaCha8Filler fills buffers from a seeded ChaCha8 stream
+type ChaCha8Filler struct {
+	rng *mathrand.ChaCha8
+}
+
+// NewChaCha8Filler creates a ChaCha8 filler with the given seed
+func NewChaCha8Filler(seed uint64) *ChaCha8Filler {
+	var key [32]byte
+	binary.LittleEndian.PutUint64(key[:], seed)
+	return &ChaCha8Filler{rng: mathrand.NewChaCha8(key)}
+}
+
+// Fill fills buf with the next bytes of the ChaCha8 stream
+func (f *ChaCha8Filler) Fill(buf []byte) {
+	f.rng.Read(buf)
+}
+
+// PCGFiller fills buffers from a seeded PCG generator, eight bytes at a time
+type PCGFiller struct {
+	rng *mathrand.PCG
+}
+
+// NewPCGFiller creates a PCG filler with the given seed
+func NewPCGFiller(seed uint64) *PCGFiller {
+	return &PCGFiller{rng: mathrand.NewPCG(seed, seed^0x9e3779b97f4a7c15)}
+}
+
+// Fill fills buf with pseudo-random bytes
+func (f *PCGFiller) Fill(buf []byte) {
+	i := 0
+	for ; i+8 <= len(buf); i += 8 {
+		binary.LittleEndian.PutUint64(buf[i:], f.rng.Uint64())
+	}
+	if i < len(buf) {
+		var tail [8]byte
+		binary.LittleEndian.PutUint64(tail[:], f.rng.Uint64())
+		copy(buf[i:], tail[:])
+	}
+}
+
+// RotatingFiller copies payloads from a pre-generated 4 MB random buffer shared by all
+// rotating fillers, each payload continuing where the previous one ended, and writes a
+// unique 16-byte header (a per-filler ID and a sequence number). No two payloads are
+// identical while almost no random data is generated per message, and payload bodies
+// only repeat once the whole buffer has been used, far beyond the size of a batch.
+type RotatingFiller struct {
+	data   []byte
+	offset int
+	id     uint64
+	seq    uint64
+}
+
+// NewRotatingFiller creates a rotating filler. seed selects the filler ID and where in
+// the shared buffer it starts.
+func NewRotatingFiller(seed uint64) *RotatingFiller {
+	id := NewPCGFiller(seed).rng.Uint64()
+	return &RotatingFiller{
+		data:   rotatingData(),
+		offset: int(id % rotatingBufferSize),
+		id:     id,
+	}
+}
+
+// Fill writes the header and copies the rest of buf from the rotating buffer
+func (f *RotatingFiller) Fill(buf []byte) {
+	var header [16]byte
+	binary.BigEndian.PutUint64(header[:8], f.id)
+	binary.BigEndian.PutUint64(header[8:], f.seq)
+	f.seq++
+	n := copy(buf, header[:])
+
+	for n < len(buf) {
+		copied := copy(buf[n:], f.data[f.offset:])
+		n += copied
+		f.offset = (f.offset + copied) % len(f.data)
+	}
+}
+
+// CompressibleFiller mixes random bytes from another filler with repeated filler text
+// so payloads compress to roughly Ratio times their size (see GenerateCompressiblePayloadTo)
+type CompressibleFiller struct {
+	Random Filler
+	Ratio  float64
+}
+
+// Fill fills buf with random bytes and repeated filler text
+func (f CompressibleFiller) Fill(buf []byte) {
+	f.Random.Fill(buf)
+	mixFiller(buf, f.Ratio)
+}
@@ -0,0 +1,99 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func TestNewFiller(t *testing.T) {
+	for _, strategy := range []string{"", FillCrypto, FillChaCha8, FillPCG, FillRotating} {
+		t.Run(strategy, func(t *testing.T) {
+			filler, err := NewFiller(strategy, 42)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, size := range []int{0, 7, 13, 1024, rotatingBufferSize + 100} {
+				buf := make([]byte, size)
+				filler.Fill(buf)
+				// The trailing bytes must be filled too; 8 zero bytes in a row are vanishingly unlikely
+				if size >= 8 && bytes.Equal(buf[size-8:], make([]byte, 8)) {
+					t.Errorf("size %d: buffer tail was not filled", size)
+				}
+			}
+
+			buf1 := make([]byte, 256)
+			buf2 := make([]byte, 256)
+			filler.Fill(buf1)
+			filler.Fill(buf2)
+			if bytes.Equal(buf1, buf2) {
+				t.Error("two payloads from the same filler should not be identical")
+			}
+		})
+	}
+
+	if _, err := NewFiller("xorshift", 1); err == nil {
+		t.Error("expected error for unknown strategy")
+	}
+}
+
+func TestSeededFillersReproducible(t *testing.T) {
+	for _, strategy := range []string{FillChaCha8, FillPCG, FillRotating} {
+		t.Run(strategy, func(t *testing.T) {
+			fill := func(seed uint64) []byte {
+				filler, err := NewFiller(strategy, seed)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				buf := make([]byte, 1000)
+				filler.Fill(buf)
+				return buf
+			}
+
+			if !bytes.Equal(fill(7), fill(7)) {
+				t.Error("fillers with the same seed should produce the same payloads")
+			}
+			if bytes.Equal(fill(7), fill(8)) {
+				t.Error("fillers with different seeds should produce different payloads")
+			}
+		})
+	}
+}
+
+func TestRotatingFiller(t *testing.T) {
+	filler := NewRotatingFiller(1)
+
+	buf1 := make([]byte, 1024)
+	buf2 := make([]byte, 1024)
+	filler.Fill(buf1)
+	filler.Fill(buf2)
+
+	if !bytes.Equal(buf1[:8], buf2[:8]) {
+		t.Error("payloads from one filler should share the filler ID")
+	}
+	if seq1, seq2 := binary.BigEndian.Uint64(buf1[8:16]), binary.BigEndian.Uint64(buf2[8:16]); seq2 != seq1+1 {
+		t.Errorf("expected consecutive sequence numbers, got %d and %d", seq1, seq2)
+	}
+	// The second body continues where the first ended, so bodies do not overlap
+	if bytes.Contains(buf1, buf2[16:64]) {
+		t.Error("consecutive payload bodies should not overlap")
+	}
+
+	short := make([]byte, 10)
+	filler.Fill(short)
+	if !bytes.Equal(short[:8], buf1[:8]) {
+		t.Error("payloads shorter than the header should hold a truncated header")
+	}
+}
+
+func TestCompressibleFiller(t *testing.T) {
+	filler := CompressibleFiller{Random: NewPCGFiller(1), Ratio: 0.4}
+	buf := make([]byte, 64*1024)
+	filler.Fill(buf)
+
+	if got := deflateRatio(t, buf); math.Abs(got-0.4) > 0.05 {
+		t.Errorf("expected compression ratio near 0.40, got %.3f", got)
+	}
+}
@@ -3,15 +3,24 @@ package generator
 import (
 	"crypto/rand"
 	"encoding/binary"
+	"math/bits"
 	"sync"
 )
 
+// maxSizeClass is the largest power-of-two size class pooled by GetSize (64 MB)
+const maxSizeClass = 26
+
 // PayloadPool manages reusable byte buffers to minimize allocations.
 // It uses sync.Pool for thread-safe buffer pooling, significantly reducing
 // GC pressure in high-throughput scenarios.
+//
+// Buffers of the configured size come from a dedicated pool. Variable sizes
+// requested through GetSize are served from power-of-two size classes, so a
+// buffer is at most twice as large as the payload it holds.
 type PayloadPool struct {
-	size int
-	pool *sync.Pool
+	size    int
+	pool    *sync.Pool
+	classes [maxSizeClass + 1]sync.Pool
 }
 
 // NewPayloadPool creates a new buffer pool for payloads of the specified size.
@@ -48,12 +57,49 @@ func (p *PayloadPool) Get() []byte {
 	return p.pool.Get().([]byte)
 }
 
+// GetSize retrieves a buffer of length size. Sizes other than the pool's configured
+// size are served from the smallest power-of-two size class that fits them; sizes
+// above 64 MB are allocated and not pooled.
+//
+// The buffer must be returned to the pool via Put() when no longer needed.
+func (p *PayloadPool) GetSize(size int) []byte {
+	if size == p.size {
+		return p.Get()
+	}
+	class := sizeClass(size)
+	if class > maxSizeClass {
+		return make([]byte, size)
+	}
+	if buf, ok := p.classes[class].Get().([]byte); ok {
+		return buf[:size]
+	}
+	return make([]byte, size, 1<<class)
+}
+
 // Put returns a buffer to the pool for reuse. The buffer should not be used
-// after calling Put(). Only buffers obtained from Get() should be returned.
+// after calling Put(). Only buffers obtained from Get() or GetSize() should be returned.
 //
-// Buffers of incorrect size will still be accepted but may reduce pool efficiency.
+// Buffers that fit neither the configured size nor a size class are dropped, so
+// Get never hands out a buffer of the wrong length.
 func (p *PayloadPool) Put(buf []byte) {
-	p.pool.Put(buf)
+	if len(buf) == p.size && cap(buf) == p.size {
+		p.pool.Put(buf)
+		return
+	}
+	// Size-class buffers have a power-of-two capacity
+	if c := cap(buf); c > 0 && c&(c-1) == 0 {
+		if class := sizeClass(c); class <= maxSizeClass {
+			p.classes[class].Put(buf[:c])
+		}
+	}
+}
+
+// sizeClass returns the exponent of the smallest power of two that holds size
+func sizeClass(size int) int {
+	if size <= 1 {
+		return 0
+	}
+	return bits.Len(uint(size - 1))
 }
 
 // GenerateRandomPayload generates a payload of the specified size filled with
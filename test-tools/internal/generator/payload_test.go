@@ -2,7 +2,9 @@ package generator
 
 import (
 	"bytes"
+	"fmt"
 	"sync"
+	"sync/atomic"
 	"testing"
 )
 
@@ -277,6 +279,29 @@ func TestPayloadPool(t *testing.T) {
 
 		wg.Wait()
 	})
+
+	t.Run("variable sizes", func(t *testing.T) {
+		pool := NewPayloadPool(1024, 10)
+
+		for _, size := range []int{1, 100, 1024, 1025, 4096, 70000} {
+			buf := pool.GetSize(size)
+			if len(buf) != size {
+				t.Errorf("expected buffer size %d, got %d", size, len(buf))
+			}
+			if size != 1024 && cap(buf) >= 2*size && size > 1 {
+				t.Errorf("size %d: capacity %d exceeds twice the size", size, cap(buf))
+			}
+			pool.Put(buf)
+		}
+
+		// A size-class buffer returned to the pool never comes back from Get
+		pool.Put(pool.GetSize(2048)[:1024])
+		for i := 0; i < 10; i++ {
+			if buf := pool.Get(); cap(buf) != 1024 {
+				t.Fatalf("expected capacity 1024 from Get, got %d", cap(buf))
+			}
+		}
+	})
 }
 
 func TestGenerateRandomPayloadTo(t *testing.T) {
@@ -534,3 +559,69 @@ func BenchmarkExtractSequenceNumber(b *testing.B) {
 		_, _ = ExtractSequenceNumber(payload)
 	}
 }
+
+// fillStrategies lists every fill strategy for the strategy comparison benchmarks
+var fillStrategies = []string{FillCrypto, FillChaCha8, FillPCG, FillRotating}
+
+func BenchmarkFillStrategies(b *testing.B) {
+	sizes := []int{64, 1024, 16 * 1024, 1024 * 1024}
+
+	for _, strategy := range fillStrategies {
+		for _, size := range sizes {
+			b.Run(fmt.Sprintf("%s/%d", strategy, size), func(b *testing.B) {
+				filler, err := NewFiller(strategy, 1)
+				if err != nil {
+					b.Fatalf("failed to create filler: %v", err)
+				}
+				buf := make([]byte, size)
+				b.SetBytes(int64(size))
+				b.ReportAllocs()
+				b.ResetTimer()
+
+				for i := 0; i < b.N; i++ {
+					filler.Fill(buf)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkFillStrategiesParallel(b *testing.B) {
+	// Every goroutine has its own filler, like producer workers do
+	for _, strategy := range fillStrategies {
+		b.Run(strategy, func(b *testing.B) {
+			b.SetBytes(1024)
+			var seed atomic.Uint64
+			b.RunParallel(func(pb *testing.PB) {
+				filler, err := NewFiller(strategy, seed.Add(1))
+				if err != nil {
+					b.Errorf("failed to create filler: %v", err)
+					return
+				}
+				buf := make([]byte, 1024)
+				for pb.Next() {
+					filler.Fill(buf)
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkCompressibleFill(b *testing.B) {
+	for _, strategy := range fillStrategies {
+		b.Run(strategy, func(b *testing.B) {
+			random, err := NewFiller(strategy, 1)
+			if err != nil {
+				b.Fatalf("failed to create filler: %v", err)
+			}
+			filler := CompressibleFiller{Random: random, Ratio: 0.5}
+			buf := make([]byte, 1024)
+			b.SetBytes(1024)
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				filler.Fill(buf)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package generator
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+)
+
+// PropertyTemplate is a compiled set of message properties. Values are static text or
+// templates whose placeholders (see Template) are rendered for every message, e.g.:
+//
+//	region=us-east,seq={{seq}},worker={{worker}},ts={{now "unixms"}},tier={{choice "gold" "free"}}
+//
+// A PropertyTemplate is immutable once built and can be shared; rendering state lives in
+// a PropertyRenderer.
+type PropertyTemplate struct {
+	static    map[string]string
+	generated []generatedProperty
+}
+
+// generatedProperty is a property whose value is rendered from a template
+type generatedProperty struct {
+	name     string
+	template *Template
+}
+
+// ParseProperties compiles a comma-separated list of name=value properties; a name
+// without a value is a property with an empty value. Commas inside placeholders do not
+// separate properties.
+func ParseProperties(spec string) (*PropertyTemplate, error) {
+	t := &PropertyTemplate{static: make(map[string]string)}
+	seen := make(map[string]bool)
+	for _, entry := range splitProperties(spec) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("invalid property %q (expected name=value)", entry)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate property %s", name)
+		}
+		seen[name] = true
+
+		value = strings.TrimSpace(value)
+		if !strings.Contains(value, "{{") {
+			t.static[name] = value
+			continue
+		}
+		tmpl, err := ParseTemplate(value)
+		if err != nil {
+			return nil, fmt.Errorf("property %s: %w", name, err)
+		}
+		t.generated = append(t.generated, generatedProperty{name: name, template: tmpl})
+	}
+	return t, nil
+}
+
+// splitProperties splits a property list at commas outside {{ }}
+func splitProperties(spec string) []string {
+	var entries []string
+	depth, start := 0, 0
+	for i := 0; i < len(spec); i++ {
+		switch {
+		case strings.HasPrefix(spec[i:], "{{"):
+			depth++
+			i++
+		case strings.HasPrefix(spec[i:], "}}") && depth > 0:
+			depth--
+			i++
+		case spec[i] == ',' && depth == 0:
+			entries = append(entries, spec[start:i])
+			start = i + 1
+		}
+	}
+	return append(entries, spec[start:])
+}
+
+// AddPadding adds count static properties named pad-0, pad-1, ... with random values of
+// size bytes, to measure the overhead of large property maps. seed makes the values
+// reproducible.
+func (t *PropertyTemplate) AddPadding(count, size int, seed uint64) {
+	rng := rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+	for i := 0; i < count; i++ {
+		t.static[fmt.Sprintf("pad-%d", i)] = string(appendRandString(nil, rng, size))
+	}
+}
+
+// Len returns the number of properties
+func (t *PropertyTemplate) Len() int {
+	return len(t.static) + len(t.generated)
+}
+
+// PropertyRenderer renders the properties of successive messages. Renderers are not safe
+// for concurrent use; each producer worker creates its own.
+type PropertyRenderer struct {
+	template   *PropertyTemplate
+	renderers  []*TemplateRenderer
+	properties map[string]string
+}
+
+// NewPropertyRenderer creates a renderer for the given worker whose random values are
+// seeded with seed
+func NewPropertyRenderer(t *PropertyTemplate, worker int, seed uint64) *PropertyRenderer {
+	r := &PropertyRenderer{
+		template:   t,
+		renderers:  make([]*TemplateRenderer, len(t.generated)),
+		properties: make(map[string]string, t.Len()+1),
+	}
+	for i, p := range t.generated {
+		// Distinct seeds keep random values of different properties independent
+		r.renderers[i] = NewTemplateRenderer(p.template, seed+uint64(i)*0x9e3779b97f4a7c15)
+		r.renderers[i].SetWorker(worker)
+	}
+	return r
+}
+
+// Render returns the properties of the next message. The map is reused by the next
+// call, so it must not be retained; callers may add properties to it.
+func (r *PropertyRenderer) Render() map[string]string {
+	clear(r.properties)
+	for name, value := range r.template.static {
+		r.properties[name] = value
+	}
+	for i, p := range r.template.generated {
+		r.properties[p.name] = string(r.renderers[i].Render())
+	}
+	return r.properties
+}
@@ -0,0 +1,99 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseProperties(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		static  int
+		dynamic int
+		errMsg  string
+	}{
+		{"empty", "", 0, 0, ""},
+		{"static", "region=us-east, tier=gold", 2, 0, ""},
+		{"generated", `seq={{seq}},worker={{worker}},ts={{now "unixms"}}`, 0, 3, ""},
+		{"comma in placeholder", `tier={{choice "a,b" "c"}},region=eu`, 1, 1, ""},
+		{"empty value", "flag=", 1, 0, ""},
+		{"name only", "flag", 1, 0, ""},
+		{"missing name", "=eu", 0, 0, "expected name=value"},
+		{"duplicate", "a=1,a=2", 0, 0, "duplicate property a"},
+		{"invalid placeholder", "id={{uid}}", 0, 0, "property id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			props, err := ParseProperties(tt.spec)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("expected error containing %q, got %v", tt.errMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(props.static) != tt.static || len(props.generated) != tt.dynamic {
+				t.Errorf("expected %d static and %d generated properties, got %d and %d",
+					tt.static, tt.dynamic, len(props.static), len(props.generated))
+			}
+		})
+	}
+}
+
+func TestPropertyRenderer(t *testing.T) {
+	props, err := ParseProperties(`region=us-east,seq={{seq}},worker={{worker}},tier={{choice "gold" "free"}}`)
+	if err != nil {
+		t.Fatalf("failed to parse properties: %v", err)
+	}
+	r := NewPropertyRenderer(props, 3, 1)
+
+	for i := 0; i < 10; i++ {
+		got := r.Render()
+		if len(got) != 4 {
+			t.Fatalf("message %d: expected 4 properties, got %v", i, got)
+		}
+		if got["region"] != "us-east" || got["seq"] != strconv.Itoa(i) || got["worker"] != "3" {
+			t.Errorf("message %d: unexpected properties %v", i, got)
+		}
+		if got["tier"] != "gold" && got["tier"] != "free" {
+			t.Errorf("message %d: tier %q is not one of the choices", i, got["tier"])
+		}
+		// Properties added by the caller do not leak into the next message
+		got["extra"] = "x"
+	}
+}
+
+func TestPropertyPadding(t *testing.T) {
+	props, _ := ParseProperties("region=eu")
+	props.AddPadding(20, 64, 1)
+	if props.Len() != 21 {
+		t.Fatalf("expected 21 properties, got %d", props.Len())
+	}
+
+	got := NewPropertyRenderer(props, 0, 1).Render()
+	for i := 0; i < 20; i++ {
+		if value := got[fmt.Sprintf("pad-%d", i)]; len(value) != 64 {
+			t.Errorf("pad-%d: expected a 64-byte value, got %q", i, value)
+		}
+	}
+}
+
+func BenchmarkPropertyRender(b *testing.B) {
+	for _, padding := range []int{0, 10, 100} {
+		props, _ := ParseProperties(`region=us-east,seq={{seq}},worker={{worker}},tier={{choice "gold" "free"}}`)
+		props.AddPadding(padding, 32, 1)
+		r := NewPropertyRenderer(props, 0, 1)
+		b.Run(fmt.Sprintf("padding-%d", padding), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				r.Render()
+			}
+		})
+	}
+}
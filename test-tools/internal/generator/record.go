@@ -0,0 +1,306 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Record file formats
+const (
+	RecordFormatNDJSON = "ndjson"
+	RecordFormatBinary = "binary"
+)
+
+// maxRecordFieldSize bounds the length prefixes of binary records so a corrupt file
+// fails instead of allocating gigabytes
+const maxRecordFieldSize = 256 << 20
+
+// Record is one message of a traffic file: what a producer sends when replaying it
+type Record struct {
+	Payload    []byte
+	Key        string
+	Properties map[string]string
+	Offset     time.Duration // time since the first record of the file
+}
+
+// ndjsonRecord is the NDJSON encoding of a Record. Text payloads are stored as a string,
+// binary payloads in base64. Unknown fields are ignored, so files may carry extra data.
+type ndjsonRecord struct {
+	OffsetMs      float64           `json:"offset_ms"`
+	Key           string            `json:"key,omitempty"`
+	Properties    map[string]string `json:"properties,omitempty"`
+	Payload       *string           `json:"payload,omitempty"`
+	PayloadBase64 *string           `json:"payload_base64,omitempty"`
+}
+
+// RecordFormatForPath returns the record format of a file from its extension:
+// .ndjson, .jsonl and .json are NDJSON, .bin is binary
+func RecordFormatForPath(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ndjson", ".jsonl", ".json":
+		return RecordFormatNDJSON, nil
+	case ".bin":
+		return RecordFormatBinary, nil
+	default:
+		return "", fmt.Errorf("cannot tell the record format of %s from its extension (use .ndjson, .jsonl or .bin, or set the format)", path)
+	}
+}
+
+// RecordReader reads records from a traffic file
+type RecordReader interface {
+	// Next returns the next record, or io.EOF at the end of the file
+	Next() (Record, error)
+}
+
+// NewRecordReader creates a reader for the given format
+func NewRecordReader(r io.Reader, format string) (RecordReader, error) {
+	switch format {
+	case RecordFormatNDJSON:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxRecordFieldSize)
+		return &ndjsonReader{scanner: scanner}, nil
+	case RecordFormatBinary:
+		return &binaryReader{r: bufio.NewReader(r)}, nil
+	default:
+		return nil, fmt.Errorf("unknown record format: %s (must be one of: ndjson, binary)", format)
+	}
+}
+
+// ndjsonReader reads one JSON record per line; blank lines are skipped
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// Next returns the next record
+func (nr *ndjsonReader) Next() (Record, error) {
+	for nr.scanner.Scan() {
+		nr.line++
+		line := nr.scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var raw ndjsonRecord
+		if err := json.Unmarshal(line, &raw); err != nil {
+			return Record{}, fmt.Errorf("line %d: %w", nr.line, err)
+		}
+		record := Record{
+			Key:        raw.Key,
+			Properties: raw.Properties,
+			Offset:     time.Duration(raw.OffsetMs * float64(time.Millisecond)),
+		}
+		switch {
+		case raw.Payload != nil:
+			record.Payload = []byte(*raw.Payload)
+		case raw.PayloadBase64 != nil:
+			payload, err := base64.StdEncoding.DecodeString(*raw.PayloadBase64)
+			if err != nil {
+				return Record{}, fmt.Errorf("line %d: invalid payload_base64: %w", nr.line, err)
+			}
+			record.Payload = payload
+		}
+		return record, nil
+	}
+	if err := nr.scanner.Err(); err != nil {
+		return Record{}, err
+	}
+	return Record{}, io.EOF
+}
+
+// binaryReader reads length-prefixed binary records (see RecordWriter)
+type binaryReader struct {
+	r     *bufio.Reader
+	index int
+}
+
+// Next returns the next record
+func (br *binaryReader) Next() (Record, error) {
+	var offset int64
+	if err := binary.Read(br.r, binary.BigEndian, &offset); err != nil {
+		if errors.Is(err, io.EOF) {
+			return Record{}, io.EOF
+		}
+		return Record{}, fmt.Errorf("record %d: %w", br.index, err)
+	}
+
+	record := Record{Offset: time.Duration(offset)}
+	key, err := br.readField()
+	if err != nil {
+		return Record{}, fmt.Errorf("record %d: key: %w", br.index, err)
+	}
+	record.Key = string(key)
+
+	count, err := br.readLength()
+	if err != nil {
+		return Record{}, fmt.Errorf("record %d: properties: %w", br.index, err)
+	}
+	if count > 0 {
+		record.Properties = make(map[string]string, count)
+	}
+	for i := uint32(0); i < count; i++ {
+		name, err := br.readField()
+		if err != nil {
+			return Record{}, fmt.Errorf("record %d: property %d: %w", br.index, i, err)
+		}
+		value, err := br.readField()
+		if err != nil {
+			return Record{}, fmt.Errorf("record %d: property %s: %w", br.index, name, err)
+		}
+		record.Properties[string(name)] = string(value)
+	}
+
+	if record.Payload, err = br.readField(); err != nil {
+		return Record{}, fmt.Errorf("record %d: payload: %w", br.index, err)
+	}
+	br.index++
+	return record, nil
+}
+
+// readLength reads a uint32 length prefix
+func (br *binaryReader) readLength() (uint32, error) {
+	var n uint32
+	if err := binary.Read(br.r, binary.BigEndian, &n); err != nil {
+		return 0, noEOF(err)
+	}
+	if n > maxRecordFieldSize {
+		return 0, fmt.Errorf("length %d exceeds the %d byte limit", n, maxRecordFieldSize)
+	}
+	return n, nil
+}
+
+// readField reads a length-prefixed byte string
+func (br *binaryReader) readField() ([]byte, error) {
+	n, err := br.readLength()
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(br.r, buf); err != nil {
+		return nil, noEOF(err)
+	}
+	return buf, nil
+}
+
+// noEOF reports a file that ends inside a record as truncated
+func noEOF(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("truncated record")
+	}
+	return err
+}
+
+// RecordWriter writes records in NDJSON or the binary format.
+//
+// A binary record is a sequence of big-endian fields:
+//
+//	int64   offset in nanoseconds
+//	uint32  key length, key
+//	uint32  property count, then per property: uint32 name length, name,
+//	        uint32 value length, value
+//	uint32  payload length, payload
+type RecordWriter struct {
+	w      *bufio.Writer
+	format string
+}
+
+// NewRecordWriter creates a writer for the given format. Call Flush when done.
+func NewRecordWriter(w io.Writer, format string) (*RecordWriter, error) {
+	if format != RecordFormatNDJSON && format != RecordFormatBinary {
+		return nil, fmt.Errorf("unknown record format: %s (must be one of: ndjson, binary)", format)
+	}
+	return &RecordWriter{w: bufio.NewWriter(w), format: format}, nil
+}
+
+// Write writes one record and returns the number of bytes written
+func (rw *RecordWriter) Write(record Record) (int, error) {
+	if rw.format == RecordFormatNDJSON {
+		return rw.writeNDJSON(record, nil)
+	}
+	return rw.writeBinary(record)
+}
+
+// WriteJSON writes one NDJSON record with extra fields, which record readers ignore
+// but other tools can use. It returns the number of bytes written.
+func (rw *RecordWriter) WriteJSON(record Record, extra map[string]any) (int, error) {
+	if rw.format != RecordFormatNDJSON {
+		return 0, fmt.Errorf("extra fields require the ndjson format")
+	}
+	return rw.writeNDJSON(record, extra)
+}
+
+// writeNDJSON writes a record as one JSON line
+func (rw *RecordWriter) writeNDJSON(record Record, extra map[string]any) (int, error) {
+	fields := make(map[string]any, len(extra)+4)
+	for name, value := range extra {
+		fields[name] = value
+	}
+	fields["offset_ms"] = float64(record.Offset) / float64(time.Millisecond)
+	if record.Key != "" {
+		fields["key"] = record.Key
+	}
+	if len(record.Properties) > 0 {
+		fields["properties"] = record.Properties
+	}
+	if record.Payload != nil {
+		if utf8.Valid(record.Payload) {
+			fields["payload"] = string(record.Payload)
+		} else {
+			fields["payload_base64"] = base64.StdEncoding.EncodeToString(record.Payload)
+		}
+	}
+
+	line, err := json.Marshal(fields)
+	if err != nil {
+		return 0, err
+	}
+	line = append(line, '\n')
+	return rw.w.Write(line)
+}
+
+// writeBinary writes a length-prefixed binary record
+func (rw *RecordWriter) writeBinary(record Record) (int, error) {
+	var scratch [8]byte
+	var n int
+	var err error
+	write := func(b []byte) {
+		if err != nil {
+			return
+		}
+		var written int
+		written, err = rw.w.Write(b)
+		n += written
+	}
+	writeField := func(b []byte) {
+		binary.BigEndian.PutUint32(scratch[:4], uint32(len(b)))
+		write(scratch[:4])
+		write(b)
+	}
+
+	binary.BigEndian.PutUint64(scratch[:], uint64(record.Offset))
+	write(scratch[:])
+	writeField([]byte(record.Key))
+	binary.BigEndian.PutUint32(scratch[:4], uint32(len(record.Properties)))
+	write(scratch[:4])
+	for name, value := range record.Properties {
+		writeField([]byte(name))
+		writeField([]byte(value))
+	}
+	writeField(record.Payload)
+	return n, err
+}
+
+// Flush writes buffered records to the underlying writer
+func (rw *RecordWriter) Flush() error {
+	return rw.w.Flush()
+}
@@ -0,0 +1,162 @@
+package generator
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testRecords() []Record {
+	return []Record{
+		{Payload: []byte(`{"id": 1}`), Key: "user-1", Properties: map[string]string{"type": "order"}},
+		{Payload: []byte{0x00, 0xff, 0xfe, 0x80}, Offset: 1500 * time.Microsecond},
+		{Payload: []byte{}, Key: "user-2", Offset: 2 * time.Second},
+	}
+}
+
+func TestRecordRoundTrip(t *testing.T) {
+	for _, format := range []string{RecordFormatNDJSON, RecordFormatBinary} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewRecordWriter(&buf, format)
+			if err != nil {
+				t.Fatalf("failed to create writer: %v", err)
+			}
+			for _, record := range testRecords() {
+				if _, err := w.Write(record); err != nil {
+					t.Fatalf("failed to write record: %v", err)
+				}
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatalf("failed to flush: %v", err)
+			}
+
+			r, err := NewRecordReader(&buf, format)
+			if err != nil {
+				t.Fatalf("failed to create reader: %v", err)
+			}
+			for i, want := range testRecords() {
+				got, err := r.Next()
+				if err != nil {
+					t.Fatalf("record %d: %v", i, err)
+				}
+				if !bytes.Equal(got.Payload, want.Payload) || got.Key != want.Key ||
+					got.Offset != want.Offset || !reflect.DeepEqual(got.Properties, want.Properties) {
+					t.Errorf("record %d: got %+v, want %+v", i, got, want)
+				}
+			}
+			if _, err := r.Next(); err != io.EOF {
+				t.Errorf("expected io.EOF after the last record, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNDJSONRecordReader(t *testing.T) {
+	input := `{"offset_ms": 0, "key": "a", "payload": "hello", "extra": 1}
+
+{"offset_ms": 12.5, "payload_base64": "AP8="}
+`
+	r, err := NewRecordReader(strings.NewReader(input), RecordFormatNDJSON)
+	if err != nil {
+		t.Fatalf("failed to create reader: %v", err)
+	}
+
+	first, err := r.Next()
+	if err != nil {
+		t.Fatalf("first record: %v", err)
+	}
+	if string(first.Payload) != "hello" || first.Key != "a" {
+		t.Errorf("first record: got %+v", first)
+	}
+
+	second, err := r.Next()
+	if err != nil {
+		t.Fatalf("second record: %v", err)
+	}
+	if !bytes.Equal(second.Payload, []byte{0x00, 0xff}) || second.Offset != 12500*time.Microsecond {
+		t.Errorf("second record: got %+v", second)
+	}
+}
+
+func TestRecordReaderErrors(t *testing.T) {
+	var full bytes.Buffer
+	w, _ := NewRecordWriter(&full, RecordFormatBinary)
+	w.Write(testRecords()[0])
+	w.Flush()
+
+	tests := []struct {
+		name   string
+		format string
+		input  []byte
+		errMsg string
+	}{
+		{"invalid json", RecordFormatNDJSON, []byte("{\"payload\": \"a\"}\nnot json\n"), "line 2"},
+		{"invalid base64", RecordFormatNDJSON, []byte(`{"payload_base64": "!!"}`), "invalid payload_base64"},
+		{"truncated binary", RecordFormatBinary, full.Bytes()[:full.Len()-2], "truncated record"},
+		{"oversized field", RecordFormatBinary, append(make([]byte, 8), 0xff, 0xff, 0xff, 0xff), "exceeds"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewRecordReader(bytes.NewReader(tt.input), tt.format)
+			if err != nil {
+				t.Fatalf("failed to create reader: %v", err)
+			}
+			for {
+				_, err = r.Next()
+				if err != nil {
+					break
+				}
+			}
+			if err == io.EOF || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestRecordWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	w, _ := NewRecordWriter(&buf, RecordFormatNDJSON)
+	if _, err := w.WriteJSON(Record{Payload: []byte("x")}, map[string]any{"message_id": "1:2:0"}); err != nil {
+		t.Fatalf("failed to write record: %v", err)
+	}
+	w.Flush()
+	if !strings.Contains(buf.String(), `"message_id":"1:2:0"`) {
+		t.Errorf("expected the extra field in %s", buf.String())
+	}
+
+	binary, _ := NewRecordWriter(&buf, RecordFormatBinary)
+	if _, err := binary.WriteJSON(Record{}, nil); err == nil {
+		t.Error("expected an error for extra fields in the binary format")
+	}
+}
+
+func TestRecordFormatForPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{"capture.ndjson", RecordFormatNDJSON, false},
+		{"capture.JSONL", RecordFormatNDJSON, false},
+		{"capture.bin", RecordFormatBinary, false},
+		{"capture.txt", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := RecordFormatForPath(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RecordFormatForPath(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("RecordFormatForPath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// ReplayOptions configures a Replayer
+type ReplayOptions struct {
+	// Format is the record format; empty selects it from the file extension
+	Format string
+
+	// Speed scales the original inter-arrival times: 1 preserves them, 2 replays twice
+	// as fast. 0 replays as fast as possible.
+	Speed float64
+
+	// Loop restarts the file at its end instead of finishing the replay
+	Loop bool
+}
+
+// Replayer replays the records of a traffic file. It is shared by all producer
+// workers of a pool, which take records in file order; with a speed set, each record
+// is due at its original offset from the start of the replay divided by the speed.
+type Replayer struct {
+	path   string
+	format string
+	opts   ReplayOptions
+
+	mu       sync.Mutex
+	file     *os.File
+	reader   RecordReader
+	start    time.Time     // start of the replay, set by the first Next
+	base     time.Duration // offset of the current loop iteration
+	last     time.Duration // offset of the last record read
+	replayed uint64
+}
+
+// NewReplayer opens a traffic file for replay. The first record is read right away
+// so an unreadable file fails here instead of in the workers.
+func NewReplayer(path string, opts ReplayOptions) (*Replayer, error) {
+	format := opts.Format
+	if format == "" {
+		var err error
+		if format, err = RecordFormatForPath(path); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Speed < 0 {
+		return nil, fmt.Errorf("replay speed must be non-negative, got %g", opts.Speed)
+	}
+
+	r := &Replayer{path: path, format: format, opts: opts}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	if _, err := r.reader.Next(); err != nil {
+		r.file.Close()
+		if err == io.EOF {
+			return nil, fmt.Errorf("replay file %s has no records", path)
+		}
+		return nil, fmt.Errorf("failed to read replay file %s: %w", path, err)
+	}
+	// Rewind so the first record is replayed
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// open (re)opens the file at its first record
+func (r *Replayer) open() error {
+	if r.file != nil {
+		r.file.Close()
+	}
+	file, err := os.Open(r.path)
+	if err != nil {
+		return fmt.Errorf("failed to open replay file: %w", err)
+	}
+	reader, err := NewRecordReader(file, r.format)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	r.file, r.reader = file, reader
+	return nil
+}
+
+// Next returns the next record and the time it is due to be sent, which is zero when
+// replaying as fast as possible. It returns io.EOF once the file has been replayed
+// (never when looping).
+func (r *Replayer) Next() (Record, time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.reader == nil {
+		return Record{}, time.Time{}, io.EOF
+	}
+
+	record, err := r.reader.Next()
+	if err == io.EOF && r.opts.Loop && r.replayed > 0 {
+		// Continue the timeline where the previous iteration ended
+		r.base += r.last
+		if err = r.open(); err != nil {
+			return Record{}, time.Time{}, err
+		}
+		record, err = r.reader.Next()
+	}
+	if err != nil {
+		r.file.Close()
+		r.reader = nil
+		if err != io.EOF {
+			err = fmt.Errorf("failed to read replay file %s: %w", r.path, err)
+		}
+		return Record{}, time.Time{}, err
+	}
+
+	r.last = record.Offset
+	r.replayed++
+	if r.start.IsZero() {
+		r.start = time.Now()
+	}
+	if r.opts.Speed <= 0 {
+		return record, time.Time{}, nil
+	}
+	offset := time.Duration(float64(r.base+record.Offset) / r.opts.Speed)
+	return record, r.start.Add(offset), nil
+}
+
+// Wait returns the next record once it is due, or ctx's error if ctx is done first
+func (r *Replayer) Wait(ctx context.Context) (Record, error) {
+	record, due, err := r.Next()
+	if err != nil {
+		return Record{}, err
+	}
+	if delay := time.Until(due); !due.IsZero() && delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return Record{}, ctx.Err()
+		}
+	}
+	return record, nil
+}
+
+// Replayed returns the number of records handed out so far
+func (r *Replayer) Replayed() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.replayed
+}
+
+// Close closes the replay file
+func (r *Replayer) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.reader == nil {
+		return nil
+	}
+	r.reader = nil
+	return r.file.Close()
+}
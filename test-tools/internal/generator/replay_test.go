@@ -0,0 +1,187 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// writeReplayFile writes records with the given offsets to a temporary NDJSON file
+func writeReplayFile(t *testing.T, offsets ...time.Duration) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "traffic.ndjson")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create replay file: %v", err)
+	}
+	defer file.Close()
+
+	w, _ := NewRecordWriter(file, RecordFormatNDJSON)
+	for i, offset := range offsets {
+		w.Write(Record{Payload: []byte{byte('a' + i)}, Offset: offset})
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("failed to write replay file: %v", err)
+	}
+	return path
+}
+
+func TestReplayerOrder(t *testing.T) {
+	path := writeReplayFile(t, 0, time.Second, 2*time.Second)
+	r, err := NewReplayer(path, ReplayOptions{})
+	if err != nil {
+		t.Fatalf("failed to create replayer: %v", err)
+	}
+	defer r.Close()
+
+	var got []string
+	for {
+		record, due, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !due.IsZero() {
+			t.Errorf("expected no due time when replaying as fast as possible, got %v", due)
+		}
+		got = append(got, string(record.Payload))
+	}
+	if strings.Join(got, "") != "abc" {
+		t.Errorf("expected records abc in order, got %v", got)
+	}
+	if r.Replayed() != 3 {
+		t.Errorf("expected 3 replayed records, got %d", r.Replayed())
+	}
+}
+
+func TestReplayerSpeed(t *testing.T) {
+	path := writeReplayFile(t, 0, time.Second, 3*time.Second)
+	r, err := NewReplayer(path, ReplayOptions{Speed: 2})
+	if err != nil {
+		t.Fatalf("failed to create replayer: %v", err)
+	}
+	defer r.Close()
+
+	_, start, _ := r.Next()
+	for _, want := range []time.Duration{500 * time.Millisecond, 1500 * time.Millisecond} {
+		_, due, err := r.Next()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := due.Sub(start); got != want {
+			t.Errorf("expected record due %v after the first, got %v", want, got)
+		}
+	}
+}
+
+func TestReplayerLoop(t *testing.T) {
+	path := writeReplayFile(t, 0, time.Second)
+	r, err := NewReplayer(path, ReplayOptions{Speed: 1, Loop: true})
+	if err != nil {
+		t.Fatalf("failed to create replayer: %v", err)
+	}
+	defer r.Close()
+
+	var payloads string
+	var dues []time.Time
+	for i := 0; i < 5; i++ {
+		record, due, err := r.Next()
+		if err != nil {
+			t.Fatalf("record %d: %v", i, err)
+		}
+		payloads += string(record.Payload)
+		dues = append(dues, due)
+	}
+	if payloads != "ababa" {
+		t.Errorf("expected the file to repeat, got %s", payloads)
+	}
+	// Each iteration continues the timeline of the previous one
+	if got := dues[4].Sub(dues[0]); got != 2*time.Second {
+		t.Errorf("expected the fifth record 2s after the first, got %v", got)
+	}
+}
+
+func TestReplayerWait(t *testing.T) {
+	path := writeReplayFile(t, 0, time.Hour)
+	r, err := NewReplayer(path, ReplayOptions{Speed: 1})
+	if err != nil {
+		t.Fatalf("failed to create replayer: %v", err)
+	}
+	defer r.Close()
+
+	if _, err := r.Wait(context.Background()); err != nil {
+		t.Fatalf("first record: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := r.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the wait for a record due in an hour to be cancelled, got %v", err)
+	}
+}
+
+func TestReplayerConcurrent(t *testing.T) {
+	offsets := make([]time.Duration, 200)
+	path := writeReplayFile(t, offsets...)
+	r, err := NewReplayer(path, ReplayOptions{})
+	if err != nil {
+		t.Fatalf("failed to create replayer: %v", err)
+	}
+	defer r.Close()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	count := 0
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				if _, err := r.Wait(context.Background()); err != nil {
+					return
+				}
+				mu.Lock()
+				count++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if count != len(offsets) {
+		t.Errorf("expected %d records replayed once each, got %d", len(offsets), count)
+	}
+}
+
+func TestNewReplayerErrors(t *testing.T) {
+	empty := filepath.Join(t.TempDir(), "empty.ndjson")
+	os.WriteFile(empty, nil, 0644)
+
+	tests := []struct {
+		name   string
+		path   string
+		opts   ReplayOptions
+		errMsg string
+	}{
+		{"missing file", filepath.Join(t.TempDir(), "missing.ndjson"), ReplayOptions{}, "failed to open"},
+		{"empty file", empty, ReplayOptions{}, "no records"},
+		{"unknown extension", "traffic.txt", ReplayOptions{}, "record format"},
+		{"negative speed", empty, ReplayOptions{Speed: -1}, "non-negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewReplayer(tt.path, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package generator
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SizeDistribution samples payload sizes. Implementations are immutable and safe for
+// concurrent use; each caller passes its own random source.
+type SizeDistribution interface {
+	// Next returns the next payload size in bytes
+	Next(rng *rand.Rand) int
+}
+
+// FixedSize always returns the same size
+type FixedSize int
+
+// Next returns the fixed size
+func (s FixedSize) Next(*rand.Rand) int {
+	return int(s)
+}
+
+// UniformSize samples sizes uniformly from [Min, Max]
+type UniformSize struct {
+	Min int
+	Max int
+}
+
+// Next returns a uniformly distributed size
+func (s UniformSize) Next(rng *rand.Rand) int {
+	return s.Min + rng.IntN(s.Max-s.Min+1)
+}
+
+// NormalSize samples sizes from a normal distribution clamped to [Min, Max]
+type NormalSize struct {
+	Mean   float64
+	StdDev float64
+	Min    int
+	Max    int // 0 = unbounded
+}
+
+// Next returns a normally distributed size
+func (s NormalSize) Next(rng *rand.Rand) int {
+	return clampSize(math.Round(rng.NormFloat64()*s.StdDev+s.Mean), s.Min, s.Max)
+}
+
+// LogNormalSize samples sizes from a log-normal distribution clamped to [Min, Max].
+// Log-normal sizes are always positive and long-tailed, like most real payloads.
+type LogNormalSize struct {
+	mu    float64
+	sigma float64
+	min   int
+	max   int
+}
+
+// NewLogNormalSize creates a log-normal distribution with the given mean and standard
+// deviation in bytes, clamped to [min, max] (max 0 = unbounded)
+func NewLogNormalSize(mean, stddev float64, min, max int) LogNormalSize {
+	sigma2 := math.Log(1 + (stddev*stddev)/(mean*mean))
+	return LogNormalSize{
+		mu:    math.Log(mean) - sigma2/2,
+		sigma: math.Sqrt(sigma2),
+		min:   min,
+		max:   max,
+	}
+}
+
+// Next returns a log-normally distributed size
+func (s LogNormalSize) Next(rng *rand.Rand) int {
+	return clampSize(math.Round(math.Exp(rng.NormFloat64()*s.sigma+s.mu)), s.min, s.max)
+}
+
+// clampSize converts a sampled size to an int within [min, max] (max 0 = unbounded)
+func clampSize(size float64, min, max int) int {
+	if size < float64(min) {
+		return min
+	}
+	if max > 0 && size > float64(max) {
+		return max
+	}
+	return int(size)
+}
+
+// SizeBucket is a weighted range of payload sizes; Min == Max for a single size
+type SizeBucket struct {
+	Min    int
+	Max    int
+	Weight float64
+}
+
+// WeightedSize samples a bucket by weight, then a size uniformly within the bucket.
+// It backs both weighted size lists and empirical histograms.
+type WeightedSize struct {
+	buckets    []SizeBucket
+	cumulative []float64
+}
+
+// NewWeightedSize creates a weighted distribution over the given buckets
+func NewWeightedSize(buckets []SizeBucket) (*WeightedSize, error) {
+	if len(buckets) == 0 {
+		return nil, fmt.Errorf("weighted size distribution requires at least one size")
+	}
+
+	w := &WeightedSize{
+		buckets:    append([]SizeBucket(nil), buckets...),
+		cumulative: make([]float64, len(buckets)),
+	}
+	total := 0.0
+	for i, b := range buckets {
+		if b.Min <= 0 || b.Max < b.Min {
+			return nil, fmt.Errorf("invalid size range %d-%d", b.Min, b.Max)
+		}
+		if b.Weight < 0 {
+			return nil, fmt.Errorf("invalid weight %g for size %d", b.Weight, b.Min)
+		}
+		total += b.Weight
+		w.cumulative[i] = total
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("weighted size distribution requires a positive total weight")
+	}
+	return w, nil
+}
+
+// Next returns a size drawn from the weighted buckets
+func (w *WeightedSize) Next(rng *rand.Rand) int {
+	r := rng.Float64() * w.cumulative[len(w.cumulative)-1]
+	i := sort.SearchFloat64s(w.cumulative, r)
+	if i >= len(w.buckets) {
+		i = len(w.buckets) - 1
+	}
+	// Skip zero-weight buckets that share a cumulative value with their successor
+	for w.buckets[i].Weight == 0 && i < len(w.buckets)-1 {
+		i++
+	}
+	b := w.buckets[i]
+	if b.Max == b.Min {
+		return b.Min
+	}
+	return b.Min + rng.IntN(b.Max-b.Min+1)
+}
+
+// MaxSize returns the largest size the distribution can return
+func (w *WeightedSize) MaxSize() int {
+	max := 0
+	for _, b := range w.buckets {
+		if b.Weight > 0 && b.Max > max {
+			max = b.Max
+		}
+	}
+	return max
+}
+
+// ParseSizeWeights parses a weighted size list such as "512:70,4096:25,65536:5".
+// Each entry is size:weight, where size is a byte count or a min-max range.
+func ParseSizeWeights(s string) ([]SizeBucket, error) {
+	var buckets []SizeBucket
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		size, weight, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid size weight %q (expected size:weight)", entry)
+		}
+		bucket, err := parseSizeBucket(size, weight)
+		if err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, bucket)
+	}
+	if len(buckets) == 0 {
+		return nil, fmt.Errorf("size weight list is empty")
+	}
+	return buckets, nil
+}
+
+// ParseSizeHistogram parses an empirical size histogram, one bucket per line:
+//
+//	# size (or min-max range) and count, separated by whitespace or a comma
+//	100       5230
+//	101-1024  18211
+//	1025-65536, 940
+//
+// Blank lines and lines starting with # are ignored.
+func ParseSizeHistogram(r io.Reader) ([]SizeBucket, error) {
+	var buckets []SizeBucket
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.FieldsFunc(text, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected size and count, got %q", line, text)
+		}
+		bucket, err := parseSizeBucket(fields[0], fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		buckets = append(buckets, bucket)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(buckets) == 0 {
+		return nil, fmt.Errorf("size histogram is empty")
+	}
+	return buckets, nil
+}
+
+// LoadSizeHistogram loads an empirical size histogram file (see ParseSizeHistogram)
+func LoadSizeHistogram(path string) ([]SizeBucket, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open size histogram: %w", err)
+	}
+	defer file.Close()
+
+	buckets, err := ParseSizeHistogram(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse size histogram %s: %w", path, err)
+	}
+	return buckets, nil
+}
+
+// parseSizeBucket parses a size or min-max range and its weight
+func parseSizeBucket(size, weight string) (SizeBucket, error) {
+	var bucket SizeBucket
+	var err error
+
+	minText, maxText, isRange := strings.Cut(strings.TrimSpace(size), "-")
+	if bucket.Min, err = strconv.Atoi(strings.TrimSpace(minText)); err != nil {
+		return bucket, fmt.Errorf("invalid size %q", size)
+	}
+	bucket.Max = bucket.Min
+	if isRange {
+		if bucket.Max, err = strconv.Atoi(strings.TrimSpace(maxText)); err != nil {
+			return bucket, fmt.Errorf("invalid size %q", size)
+		}
+	}
+	if bucket.Min <= 0 || bucket.Max < bucket.Min {
+		return bucket, fmt.Errorf("invalid size %q", size)
+	}
+
+	if bucket.Weight, err = strconv.ParseFloat(strings.TrimSpace(weight), 64); err != nil || bucket.Weight < 0 {
+		return bucket, fmt.Errorf("invalid weight %q for size %s", weight, size)
+	}
+	return bucket, nil
+}
@@ -0,0 +1,185 @@
+package generator
+
+import (
+	"math"
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+func TestSizeDistributions(t *testing.T) {
+	weighted, err := NewWeightedSize([]SizeBucket{{Min: 512, Max: 512, Weight: 70}, {Min: 4096, Max: 8192, Weight: 30}})
+	if err != nil {
+		t.Fatalf("failed to create weighted distribution: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		dist     SizeDistribution
+		min      int
+		max      int
+		wantMean float64
+	}{
+		{"fixed", FixedSize(1024), 1024, 1024, 1024},
+		{"uniform", UniformSize{Min: 100, Max: 300}, 100, 300, 200},
+		{"normal", NormalSize{Mean: 1000, StdDev: 100, Min: 1, Max: 2000}, 1, 2000, 1000},
+		{"lognormal", NewLogNormalSize(1000, 500, 1, 0), 1, math.MaxInt, 1000},
+		{"weighted", weighted, 512, 8192, 0.7*512 + 0.3*6144},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rng := rand.New(rand.NewPCG(1, 2))
+			const samples = 20000
+			sum := 0.0
+			for i := 0; i < samples; i++ {
+				size := tt.dist.Next(rng)
+				if size < tt.min || size > tt.max {
+					t.Fatalf("size %d outside [%d, %d]", size, tt.min, tt.max)
+				}
+				sum += float64(size)
+			}
+			mean := sum / samples
+			if math.Abs(mean-tt.wantMean) > tt.wantMean*0.05 {
+				t.Errorf("expected mean near %.0f, got %.0f", tt.wantMean, mean)
+			}
+		})
+	}
+}
+
+func TestClampedSizes(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	dist := NormalSize{Mean: 100, StdDev: 1000, Min: 50, Max: 150}
+	for i := 0; i < 1000; i++ {
+		if size := dist.Next(rng); size < 50 || size > 150 {
+			t.Fatalf("size %d outside [50, 150]", size)
+		}
+	}
+}
+
+func TestWeightedSizeSkipsZeroWeights(t *testing.T) {
+	dist, err := NewWeightedSize([]SizeBucket{{Min: 10, Max: 10, Weight: 0}, {Min: 20, Max: 20, Weight: 1}, {Min: 30, Max: 30, Weight: 0}})
+	if err != nil {
+		t.Fatalf("failed to create weighted distribution: %v", err)
+	}
+	if dist.MaxSize() != 20 {
+		t.Errorf("expected max size 20, got %d", dist.MaxSize())
+	}
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	for i := 0; i < 1000; i++ {
+		if size := dist.Next(rng); size != 20 {
+			t.Fatalf("expected only size 20, got %d", size)
+		}
+	}
+}
+
+func TestNewWeightedSizeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		buckets []SizeBucket
+	}{
+		{"empty", nil},
+		{"zero size", []SizeBucket{{Min: 0, Max: 0, Weight: 1}}},
+		{"inverted range", []SizeBucket{{Min: 10, Max: 5, Weight: 1}}},
+		{"negative weight", []SizeBucket{{Min: 10, Max: 10, Weight: -1}}},
+		{"zero total weight", []SizeBucket{{Min: 10, Max: 10, Weight: 0}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewWeightedSize(tt.buckets); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestParseSizeWeights(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      []SizeBucket
+		wantError bool
+	}{
+		{
+			name:  "sizes",
+			input: "512:70,4096:25,65536:5",
+			want:  []SizeBucket{{512, 512, 70}, {4096, 4096, 25}, {65536, 65536, 5}},
+		},
+		{
+			name:  "ranges and spaces",
+			input: " 100-200 : 1.5 , 1024:0.5,",
+			want:  []SizeBucket{{100, 200, 1.5}, {1024, 1024, 0.5}},
+		},
+		{name: "empty", input: "", wantError: true},
+		{name: "missing weight", input: "512", wantError: true},
+		{name: "invalid size", input: "big:1", wantError: true},
+		{name: "invalid weight", input: "512:lots", wantError: true},
+		{name: "negative weight", input: "512:-1", wantError: true},
+		{name: "inverted range", input: "200-100:1", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSizeWeights(tt.input)
+			if tt.wantError {
+				if err == nil {
+					t.Errorf("expected error for %q, got %v", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %d buckets, got %d", len(tt.want), len(got))
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("bucket %d: expected %+v, got %+v", i, tt.want[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseSizeHistogram(t *testing.T) {
+	input := `# captured from production
+100       5230
+
+101-1024  18211
+1025-65536, 940
+`
+	got, err := ParseSizeHistogram(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []SizeBucket{{100, 100, 5230}, {101, 1024, 18211}, {1025, 65536, 940}}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d buckets, got %d", len(want), len(got))
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("bucket %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+
+	errorTests := []struct {
+		name     string
+		input    string
+		errorMsg string
+	}{
+		{"empty", "# nothing\n", "size histogram is empty"},
+		{"missing count", "100\n", "line 1"},
+		{"invalid count", "100 5\n200 many\n", "line 2"},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSizeHistogram(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("expected error containing %q, got %v", tt.errorMsg, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,321 @@
+package generator
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// alphanumeric is the alphabet of {{randstring}} and the local part of {{email}}
+const alphanumeric = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// emailDomains are the domains {{email}} picks from
+var emailDomains = []string{"example.com", "example.org", "example.net", "test.io"}
+
+// Template is a compiled payload template: literal text, typically JSON, with
+// placeholders that are filled in every time a payload is rendered.
+//
+// Placeholders:
+//   - {{uuid}}: a random version 4 UUID
+//   - {{seq}}: the renderer's sequence number, starting at 0
+//   - {{worker}}: the ID of the producer worker rendering the template
+//   - {{now}}: the current time in RFC 3339 with nanoseconds; {{now "unix"}} and
+//     {{now "unixms"}} give epoch seconds and milliseconds, any other argument is
+//     a Go time layout
+//   - {{int 1 100}}: a random integer in the inclusive range
+//   - {{choice "a" "b"}}: one of the quoted strings, picked uniformly
+//   - {{email}}: a random email address
+//   - {{randstring 32}}: a random alphanumeric string of the given length
+//
+// Values are inserted as is, so a template that must produce JSON quotes string
+// placeholders itself (e.g., "id": "{{uuid}}"). A Template is immutable and can be
+// shared; rendering state lives in a TemplateRenderer.
+type Template struct {
+	parts []templatePart
+	size  int // length of the literal text, used to size render buffers
+}
+
+// templatePart is a literal or a placeholder that appends its value to dst
+type templatePart struct {
+	literal []byte
+	render  func(dst []byte, r *TemplateRenderer) []byte
+}
+
+// ParseTemplate compiles a payload template
+func ParseTemplate(text string) (*Template, error) {
+	t := &Template{}
+	rest := text
+	for rest != "" {
+		start := strings.Index(rest, "{{")
+		if start < 0 {
+			t.addLiteral(rest)
+			break
+		}
+		t.addLiteral(rest[:start])
+
+		end := strings.Index(rest[start:], "}}")
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed placeholder at offset %d", len(text)-len(rest)+start)
+		}
+		action := rest[start+2 : start+end]
+		render, err := parsePlaceholder(action)
+		if err != nil {
+			return nil, fmt.Errorf("invalid placeholder {{%s}}: %w", action, err)
+		}
+		t.parts = append(t.parts, templatePart{render: render})
+		rest = rest[start+end+2:]
+	}
+	if len(t.parts) == 0 {
+		return nil, fmt.Errorf("template is empty")
+	}
+	return t, nil
+}
+
+// LoadTemplate loads and compiles a payload template file
+func LoadTemplate(path string) (*Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read payload template: %w", err)
+	}
+	t, err := ParseTemplate(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse payload template %s: %w", path, err)
+	}
+	return t, nil
+}
+
+// addLiteral appends literal text to the template
+func (t *Template) addLiteral(text string) {
+	if text == "" {
+		return
+	}
+	t.parts = append(t.parts, templatePart{literal: []byte(text)})
+	t.size += len(text)
+}
+
+// parsePlaceholder parses the function and arguments of a placeholder
+func parsePlaceholder(action string) (func([]byte, *TemplateRenderer) []byte, error) {
+	args, err := splitArgs(action)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("missing function name")
+	}
+
+	name, args := args[0], args[1:]
+	switch name {
+	case "uuid":
+		return renderUUID, checkArgs(name, args, 0)
+	case "seq":
+		return renderSeq, checkArgs(name, args, 0)
+	case "worker":
+		return renderWorker, checkArgs(name, args, 0)
+	case "email":
+		return renderEmail, checkArgs(name, args, 0)
+	case "now":
+		if len(args) > 1 {
+			return nil, fmt.Errorf("now takes at most 1 argument, got %d", len(args))
+		}
+		return parseNow(args)
+	case "int":
+		if err := checkArgs(name, args, 2); err != nil {
+			return nil, err
+		}
+		min, errMin := strconv.ParseInt(args[0], 10, 64)
+		max, errMax := strconv.ParseInt(args[1], 10, 64)
+		if errMin != nil || errMax != nil || max < min {
+			return nil, fmt.Errorf("int requires integer bounds min <= max, got %s %s", args[0], args[1])
+		}
+		span := uint64(max-min) + 1
+		return func(dst []byte, r *TemplateRenderer) []byte {
+			if span == 0 {
+				// The range covers every int64
+				return strconv.AppendInt(dst, int64(r.rng.Uint64()), 10)
+			}
+			return strconv.AppendInt(dst, min+int64(r.rng.Uint64N(span)), 10)
+		}, nil
+	case "choice":
+		if len(args) == 0 {
+			return nil, fmt.Errorf("choice requires at least one option")
+		}
+		options := make([][]byte, len(args))
+		for i, arg := range args {
+			options[i] = []byte(arg)
+		}
+		return func(dst []byte, r *TemplateRenderer) []byte {
+			return append(dst, options[r.rng.IntN(len(options))]...)
+		}, nil
+	case "randstring":
+		if err := checkArgs(name, args, 1); err != nil {
+			return nil, err
+		}
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("randstring requires a positive length, got %s", args[0])
+		}
+		return func(dst []byte, r *TemplateRenderer) []byte {
+			return appendRandString(dst, r.rng, n)
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown function %q", name)
+	}
+}
+
+// checkArgs checks the argument count of a placeholder function
+func checkArgs(name string, args []string, want int) error {
+	if len(args) != want {
+		return fmt.Errorf("%s takes %d arguments, got %d", name, want, len(args))
+	}
+	return nil
+}
+
+// splitArgs splits a placeholder into whitespace-separated words; double-quoted
+// words are unquoted with Go string syntax
+func splitArgs(action string) ([]string, error) {
+	var args []string
+	rest := strings.TrimSpace(action)
+	for rest != "" {
+		if rest[0] == '"' {
+			quoted, err := strconv.QuotedPrefix(rest)
+			if err != nil {
+				return nil, fmt.Errorf("unterminated string %s", rest)
+			}
+			value, _ := strconv.Unquote(quoted)
+			args = append(args, value)
+			rest = rest[len(quoted):]
+		} else {
+			end := strings.IndexFunc(rest, unicode.IsSpace)
+			if end < 0 {
+				end = len(rest)
+			}
+			args = append(args, rest[:end])
+			rest = rest[end:]
+		}
+		rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+	}
+	return args, nil
+}
+
+// parseNow parses the optional format of {{now}}
+func parseNow(args []string) (func([]byte, *TemplateRenderer) []byte, error) {
+	format := time.RFC3339Nano
+	if len(args) == 1 {
+		format = args[0]
+	}
+	switch format {
+	case "unix":
+		return func(dst []byte, _ *TemplateRenderer) []byte {
+			return strconv.AppendInt(dst, time.Now().Unix(), 10)
+		}, nil
+	case "unixms":
+		return func(dst []byte, _ *TemplateRenderer) []byte {
+			return strconv.AppendInt(dst, time.Now().UnixMilli(), 10)
+		}, nil
+	default:
+		return func(dst []byte, _ *TemplateRenderer) []byte {
+			return time.Now().AppendFormat(dst, format)
+		}, nil
+	}
+}
+
+// renderUUID appends a random version 4 UUID
+func renderUUID(dst []byte, r *TemplateRenderer) []byte {
+	var id [16]byte
+	for i := 0; i < 16; i += 8 {
+		v := r.rng.Uint64()
+		for j := 0; j < 8; j++ {
+			id[i+j] = byte(v >> (8 * j))
+		}
+	}
+	id[6] = id[6]&0x0f | 0x40 // version 4
+	id[8] = id[8]&0x3f | 0x80 // RFC 4122 variant
+
+	var text [36]byte
+	hex.Encode(text[0:8], id[0:4])
+	text[8] = '-'
+	hex.Encode(text[9:13], id[4:6])
+	text[13] = '-'
+	hex.Encode(text[14:18], id[6:8])
+	text[18] = '-'
+	hex.Encode(text[19:23], id[8:10])
+	text[23] = '-'
+	hex.Encode(text[24:], id[10:])
+	return append(dst, text[:]...)
+}
+
+// renderSeq appends the renderer's sequence number
+func renderSeq(dst []byte, r *TemplateRenderer) []byte {
+	return strconv.AppendUint(dst, r.seq, 10)
+}
+
+// renderWorker appends the renderer's worker ID
+func renderWorker(dst []byte, r *TemplateRenderer) []byte {
+	return strconv.AppendInt(dst, int64(r.worker), 10)
+}
+
+// renderEmail appends a random email address
+func renderEmail(dst []byte, r *TemplateRenderer) []byte {
+	dst = appendRandString(dst, r.rng, 5+r.rng.IntN(8))
+	dst = append(dst, '@')
+	return append(dst, emailDomains[r.rng.IntN(len(emailDomains))]...)
+}
+
+// appendRandString appends n random alphanumeric characters, drawing ten characters
+// from every random number
+func appendRandString(dst []byte, rng *rand.Rand, n int) []byte {
+	for n > 0 {
+		v := rng.Uint64()
+		for i := 0; i < 10 && n > 0; i++ {
+			dst = append(dst, alphanumeric[v%uint64(len(alphanumeric))])
+			v /= uint64(len(alphanumeric))
+			n--
+		}
+	}
+	return dst
+}
+
+// TemplateRenderer renders payloads from a template. Renderers are not safe for
+// concurrent use; each producer worker creates its own.
+type TemplateRenderer struct {
+	template *Template
+	rng      *rand.Rand
+	seq      uint64
+	worker   int
+	buf      []byte
+}
+
+// NewTemplateRenderer creates a renderer whose random values are seeded with seed
+func NewTemplateRenderer(t *Template, seed uint64) *TemplateRenderer {
+	return &TemplateRenderer{
+		template: t,
+		rng:      rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15)),
+		buf:      make([]byte, 0, t.size*2),
+	}
+}
+
+// SetWorker sets the worker ID rendered by {{worker}}
+func (r *TemplateRenderer) SetWorker(id int) {
+	r.worker = id
+}
+
+// Render renders the next payload and advances the sequence number. The returned
+// slice is reused by the next call, so it must not be retained.
+func (r *TemplateRenderer) Render() []byte {
+	buf := r.buf[:0]
+	for _, part := range r.template.parts {
+		if part.render != nil {
+			buf = part.render(buf, r)
+		} else {
+			buf = append(buf, part.literal...)
+		}
+	}
+	r.buf = buf
+	r.seq++
+	return buf
+}
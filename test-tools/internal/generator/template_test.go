@@ -0,0 +1,159 @@
+package generator
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTemplateRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		pattern  string
+	}{
+		{"literal only", `hello`, `^hello$`},
+		{"uuid", `{{uuid}}`, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+		{"seq", `id={{seq}}`, `^id=0$`},
+		{"worker", `w{{worker}}`, `^w0$`},
+		{"now", `{{now}}`, `^\d{4}-\d{2}-\d{2}T`},
+		{"now unix", `{{now "unix"}}`, `^\d{10}$`},
+		{"now unixms", `{{now "unixms"}}`, `^\d{13}$`},
+		{"now layout", `{{now "2006-01-02"}}`, `^\d{4}-\d{2}-\d{2}$`},
+		{"int", `{{int 5 7}}`, `^[5-7]$`},
+		{"negative int", `{{ int -3 -1 }}`, `^-[1-3]$`},
+		{"choice", `{{choice "a b" "c"}}`, `^(a b|c)$`},
+		{"email", `{{email}}`, `^[a-zA-Z0-9]{5,12}@[a-z]+\.[a-z]+$`},
+		{"randstring", `{{randstring 32}}`, `^[a-zA-Z0-9]{32}$`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseTemplate(tt.template)
+			if err != nil {
+				t.Fatalf("failed to parse template: %v", err)
+			}
+			r := NewTemplateRenderer(tmpl, 1)
+			re := regexp.MustCompile(tt.pattern)
+			for i := 0; i < 20; i++ {
+				got := string(r.Render())
+				if tt.name == "seq" {
+					re = regexp.MustCompile(`^id=` + strconv.Itoa(i) + `$`)
+				}
+				if !re.MatchString(got) {
+					t.Fatalf("render %d: %q does not match %s", i, got, re)
+				}
+			}
+		})
+	}
+}
+
+func TestTemplateRenderJSON(t *testing.T) {
+	tmpl, err := ParseTemplate(`{"id": "{{uuid}}", "seq": {{seq}}, "ts": "{{now}}", "qty": {{int 1 100}},` +
+		` "status": "{{choice "new" "paid" "shipped"}}", "email": "{{email}}", "note": "{{randstring 16}}"}`)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+
+	r := NewTemplateRenderer(tmpl, 1)
+	for i := 0; i < 100; i++ {
+		var event struct {
+			ID     string    `json:"id"`
+			Seq    int       `json:"seq"`
+			TS     time.Time `json:"ts"`
+			Qty    int       `json:"qty"`
+			Status string    `json:"status"`
+		}
+		if err := json.Unmarshal(r.Render(), &event); err != nil {
+			t.Fatalf("rendered payload is not valid JSON: %v", err)
+		}
+		if event.Seq != i || event.Qty < 1 || event.Qty > 100 || event.TS.IsZero() {
+			t.Fatalf("unexpected event %+v", event)
+		}
+	}
+}
+
+func TestTemplateRendererSeeded(t *testing.T) {
+	tmpl, err := ParseTemplate(`{{uuid}} {{int 1 1000000}}`)
+	if err != nil {
+		t.Fatalf("failed to parse template: %v", err)
+	}
+
+	a := string(NewTemplateRenderer(tmpl, 7).Render())
+	b := string(NewTemplateRenderer(tmpl, 7).Render())
+	c := string(NewTemplateRenderer(tmpl, 8).Render())
+	if a != b {
+		t.Errorf("renderers with the same seed should match: %q != %q", a, b)
+	}
+	if a == c {
+		t.Errorf("renderers with different seeds should differ: %q", a)
+	}
+}
+
+func TestParseTemplateErrors(t *testing.T) {
+	tests := []struct {
+		template string
+		errorMsg string
+	}{
+		{``, "template is empty"},
+		{`{"id": {{uuid}`, "unclosed placeholder"},
+		{`{{}}`, "missing function name"},
+		{`{{name}}`, `unknown function "name"`},
+		{`{{uuid 4}}`, "uuid takes 0 arguments"},
+		{`{{int 1}}`, "int takes 2 arguments"},
+		{`{{int 10 1}}`, "min <= max"},
+		{`{{int a b}}`, "integer bounds"},
+		{`{{choice}}`, "at least one option"},
+		{`{{choice "a}}`, "unterminated string"},
+		{`{{randstring 0}}`, "positive length"},
+		{`{{now "unix" "x"}}`, "at most 1 argument"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			_, err := ParseTemplate(tt.template)
+			if err == nil || !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("expected error containing %q, got %v", tt.errorMsg, err)
+			}
+		})
+	}
+}
+
+func TestLoadTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "event.json")
+	if err := os.WriteFile(path, []byte(`{"seq": {{seq}}}`+"\n"), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+
+	tmpl, err := LoadTemplate(path)
+	if err != nil {
+		t.Fatalf("failed to load template: %v", err)
+	}
+	if got := string(NewTemplateRenderer(tmpl, 1).Render()); got != "{\"seq\": 0}\n" {
+		t.Errorf("unexpected payload %q", got)
+	}
+
+	if _, err := LoadTemplate(path + ".missing"); err == nil {
+		t.Error("expected error for missing template")
+	}
+}
+
+func BenchmarkTemplateRender(b *testing.B) {
+	tmpl, err := ParseTemplate(`{"id": "{{uuid}}", "seq": {{seq}}, "ts": "{{now}}", "qty": {{int 1 100}},` +
+		` "status": "{{choice "new" "paid" "shipped"}}", "email": "{{email}}", "note": "{{randstring 32}}"}`)
+	if err != nil {
+		b.Fatalf("failed to parse template: %v", err)
+	}
+	r := NewTemplateRenderer(tmpl, 1)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		r.Render()
+	}
+}
@@ -0,0 +1,97 @@
+package metrics
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// BrokerStats holds topic statistics reported by the broker through the admin API.
+// Rates are computed by the broker over its own stats interval, not by the client.
+type BrokerStats struct {
+	Topic            string  // topic name, or the comma-separated names of summed topics
+	Topics           int     // number of topics the stats are summed over
+	Partitions       int     // 0 for non-partitioned topics
+	MsgRateIn        float64 // messages per second
+	MsgRateOut       float64 // messages per second
+	MsgThroughputIn  float64 // bytes per second
+	MsgThroughputOut float64 // bytes per second
+	StorageSize      int64   // bytes
+	BacklogSize      int64   // bytes
+	Publishers       int
+	Subscriptions    []SubscriptionStats
+	UpdatedAt        time.Time // time of the last successful poll
+	LastError        string    // error of the most recent poll, empty if it succeeded
+}
+
+// SubscriptionStats holds broker-side statistics for a single subscription
+type SubscriptionStats struct {
+	Name             string
+	Type             string
+	MsgBacklog       int64
+	UnackedMessages  int64
+	MsgRateOut       float64
+	MsgRateRedeliver float64
+	Consumers        int
+}
+
+// Subscription returns the stats of the named subscription, or nil if it is not present
+func (b *BrokerStats) Subscription(name string) *SubscriptionStats {
+	for i := range b.Subscriptions {
+		if b.Subscriptions[i].Name == name {
+			return &b.Subscriptions[i]
+		}
+	}
+	return nil
+}
+
+// TotalBacklog returns the number of unconsumed messages summed over all subscriptions
+func (b *BrokerStats) TotalBacklog() int64 {
+	var total int64
+	for _, s := range b.Subscriptions {
+		total += s.MsgBacklog
+	}
+	return total
+}
+
+// SumBrokerStats combines the stats of the topics of a multi-topic workload. Rates, sizes,
+// publishers and partitions are summed, and subscriptions with the same name are merged.
+func SumBrokerStats(stats []BrokerStats) BrokerStats {
+	var total BrokerStats
+	var names []string
+	subs := make(map[string]*SubscriptionStats)
+	for _, s := range stats {
+		names = append(names, s.Topic)
+		total.Topics += s.Topics
+		total.Partitions += s.Partitions
+		total.MsgRateIn += s.MsgRateIn
+		total.MsgRateOut += s.MsgRateOut
+		total.MsgThroughputIn += s.MsgThroughputIn
+		total.MsgThroughputOut += s.MsgThroughputOut
+		total.StorageSize += s.StorageSize
+		total.BacklogSize += s.BacklogSize
+		total.Publishers += s.Publishers
+
+		for _, sub := range s.Subscriptions {
+			merged, ok := subs[sub.Name]
+			if !ok {
+				merged = &SubscriptionStats{Name: sub.Name, Type: sub.Type}
+				subs[sub.Name] = merged
+			}
+			merged.MsgBacklog += sub.MsgBacklog
+			merged.UnackedMessages += sub.UnackedMessages
+			merged.MsgRateOut += sub.MsgRateOut
+			merged.MsgRateRedeliver += sub.MsgRateRedeliver
+			merged.Consumers += sub.Consumers
+		}
+	}
+	total.Topic = strings.Join(names, ",")
+
+	for _, sub := range subs {
+		total.Subscriptions = append(total.Subscriptions, *sub)
+	}
+	sort.Slice(total.Subscriptions, func(i, j int) bool {
+		return total.Subscriptions[i].Name < total.Subscriptions[j].Name
+	})
+	return total
+}
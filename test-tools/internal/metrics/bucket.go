@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// BucketHistogram counts observations per bucket without keeping the samples, so its
+// memory stays constant however many values are observed. Percentiles are estimated by
+// interpolating within the bucket that holds them; min, max and mean are exact.
+// It is meant for secondary metrics recorded per message, such as sizes and per-partition
+// latencies, where Histogram's sample buffer would grow for the whole run.
+type BucketHistogram struct {
+	mu      sync.RWMutex
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+	min     float64
+	max     float64
+}
+
+// NewBucketHistogram creates a new bucket histogram with specified bucket boundaries
+func NewBucketHistogram(buckets []float64) *BucketHistogram {
+	sorted := make([]float64, len(buckets))
+	copy(sorted, buckets)
+	sort.Float64s(sorted)
+
+	return &BucketHistogram{
+		buckets: sorted,
+		counts:  make([]uint64, len(sorted)+1),
+		min:     math.MaxFloat64,
+	}
+}
+
+// Observe records a new observation
+func (h *BucketHistogram) Observe(value float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.sum += value
+	h.count++
+	if value < h.min {
+		h.min = value
+	}
+	if value > h.max {
+		h.max = value
+	}
+	h.counts[sort.SearchFloat64s(h.buckets, value)]++
+}
+
+// GetStats returns statistics with percentiles estimated from the bucket counts
+func (h *BucketHistogram) GetStats() LatencyStats {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.count == 0 {
+		return LatencyStats{}
+	}
+
+	return LatencyStats{
+		Min:   h.min,
+		Max:   h.max,
+		Mean:  h.sum / float64(h.count),
+		P50:   h.percentile(0.50),
+		P95:   h.percentile(0.95),
+		P99:   h.percentile(0.99),
+		P999:  h.percentile(0.999),
+		Count: h.count,
+	}
+}
+
+// percentile estimates a percentile by linear interpolation inside the bucket holding it.
+// Bucket bounds are narrowed to the observed min and max. Callers must hold the lock.
+func (h *BucketHistogram) percentile(p float64) float64 {
+	rank := p * float64(h.count)
+	var cumulative uint64
+	for i, count := range h.counts {
+		if count == 0 {
+			continue
+		}
+		if float64(cumulative+count) < rank {
+			cumulative += count
+			continue
+		}
+
+		lower := h.min
+		if i > 0 {
+			lower = math.Max(lower, h.buckets[i-1])
+		}
+		upper := h.max
+		if i < len(h.buckets) {
+			upper = math.Min(upper, h.buckets[i])
+		}
+		fraction := (rank - float64(cumulative)) / float64(count)
+		return lower + (upper-lower)*fraction
+	}
+	return h.max
+}
+
+// Buckets returns the observation count of every bucket, ending with the overflow bucket
+func (h *BucketHistogram) Buckets() []BucketCount {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	buckets := make([]BucketCount, len(h.counts))
+	for i, count := range h.counts {
+		upper := math.Inf(1)
+		if i < len(h.buckets) {
+			upper = h.buckets[i]
+		}
+		buckets[i] = BucketCount{UpperBound: upper, Count: count}
+	}
+	return buckets
+}
+
+// Reset clears all histogram data
+func (h *BucketHistogram) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i := range h.counts {
+		h.counts[i] = 0
+	}
+	h.sum = 0
+	h.count = 0
+	h.min = math.MaxFloat64
+	h.max = 0
+}
@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBucketHistogramPercentiles(t *testing.T) {
+	hist := NewBucketHistogram([]float64{10, 50, 100})
+
+	// Add 100 observations from 1 to 100
+	for i := 1; i <= 100; i++ {
+		hist.Observe(float64(i))
+	}
+
+	stats := hist.GetStats()
+
+	tests := []struct {
+		name      string
+		got       float64
+		expected  float64
+		tolerance float64
+	}{
+		{"Min", stats.Min, 1, 0},
+		{"Max", stats.Max, 100, 0},
+		{"Mean", stats.Mean, 50.5, 0.001},
+		{"P50", stats.P50, 50.0, 5.0},
+		{"P95", stats.P95, 95.0, 5.0},
+		{"P99", stats.P99, 99.0, 2.0},
+		{"P999", stats.P999, 100.0, 1.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if math.Abs(tt.got-tt.expected) > tt.tolerance {
+				t.Errorf("Expected %s near %f (±%f), got %f",
+					tt.name, tt.expected, tt.tolerance, tt.got)
+			}
+		})
+	}
+}
+
+func TestBucketHistogramEstimatesWithinObservedRange(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+	}{
+		{"single value", []float64{7}},
+		{"identical values", []float64{42, 42, 42, 42}},
+		{"overflow bucket", []float64{150, 200, 5000}},
+		{"below first bucket", []float64{0.1, 0.2, 0.3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hist := NewBucketHistogram([]float64{10, 50, 100})
+			for _, v := range tt.values {
+				hist.Observe(v)
+			}
+
+			stats := hist.GetStats()
+			for _, p := range []float64{stats.P50, stats.P95, stats.P99, stats.P999} {
+				if p < stats.Min || p > stats.Max {
+					t.Errorf("percentile %f outside observed range [%f, %f]", p, stats.Min, stats.Max)
+				}
+			}
+		})
+	}
+}
+
+func TestBucketHistogramBucketsAndReset(t *testing.T) {
+	hist := NewBucketHistogram([]float64{10, 50, 100})
+	for _, v := range []float64{1, 10, 11, 50, 99, 150, 1000} {
+		hist.Observe(v)
+	}
+
+	want := []BucketCount{
+		{UpperBound: 10, Count: 2},
+		{UpperBound: 50, Count: 2},
+		{UpperBound: 100, Count: 1},
+		{UpperBound: math.Inf(1), Count: 2},
+	}
+	got := hist.Buckets()
+	if len(got) != len(want) {
+		t.Fatalf("expected %d buckets, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("bucket %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+
+	hist.Reset()
+	if stats := hist.GetStats(); stats != (LatencyStats{}) {
+		t.Errorf("expected empty stats after reset, got %+v", stats)
+	}
+	for _, b := range hist.Buckets() {
+		if b.Count != 0 {
+			t.Errorf("expected empty buckets after reset, got %+v", b)
+		}
+	}
+}
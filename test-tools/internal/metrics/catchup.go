@@ -0,0 +1,135 @@
+package metrics
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CatchUpTracker measures how fast readers work through the messages that exist when they
+// start: from the first reader starting until the last one reaches the end of the topic.
+// Messages read after a reader has caught up are tail reads and are not counted here.
+// Consumers draining a backlog their subscription was moved back to are tracked the same way.
+type CatchUpTracker struct {
+	mu       sync.Mutex
+	readers  int
+	caughtUp int
+	start    time.Time // when the first reader started, or the last reset
+	end      time.Time // when the last reader caught up
+
+	messages atomic.Uint64
+	bytes    atomic.Uint64
+	waits    *BucketHistogram // milliseconds each read waited for its message
+}
+
+// CatchUpStats summarizes the catch-up of readers on existing messages
+type CatchUpStats struct {
+	Readers   int           // readers that started catching up
+	CaughtUp  int           // readers that reached the end of the topic
+	Messages  uint64        // messages read while catching up
+	Bytes     uint64        // bytes read while catching up
+	Duration  time.Duration // time spent catching up so far, or in total once done
+	Rate      float64       // messages per second while catching up
+	Bandwidth float64       // bytes per second while catching up
+	ReadWait  LatencyStats  // time each read waited for its message in milliseconds
+}
+
+// Done reports whether every reader has caught up
+func (s CatchUpStats) Done() bool {
+	return s.Readers > 0 && s.CaughtUp == s.Readers
+}
+
+// NewCatchUpTracker creates a new catch-up tracker
+func NewCatchUpTracker(histogramBuckets []float64) *CatchUpTracker {
+	return &CatchUpTracker{
+		waits: NewBucketHistogram(histogramBuckets),
+	}
+}
+
+// RecordStart records a reader starting to catch up at the given time
+func (ct *CatchUpTracker) RecordStart(at time.Time) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	ct.readers++
+	if ct.start.IsZero() || at.Before(ct.start) {
+		ct.start = at
+	}
+}
+
+// RecordRead records a message read by a reader that has not caught up yet and how long
+// the read waited for it. Reads served from the receive queue barely wait; reads that wait
+// for the broker to fetch entries from storage show up in the upper percentiles.
+func (ct *CatchUpTracker) RecordRead(bytes int, wait time.Duration) {
+	ct.messages.Add(1)
+	ct.bytes.Add(uint64(bytes))
+	ct.waits.Observe(float64(wait) / float64(time.Millisecond))
+}
+
+// RecordCaughtUp records a reader reaching the end of the topic at the given time
+func (ct *CatchUpTracker) RecordCaughtUp(at time.Time) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	ct.caughtUp++
+	if at.After(ct.end) {
+		ct.end = at
+	}
+}
+
+// GetStats returns the catch-up statistics
+func (ct *CatchUpTracker) GetStats() CatchUpStats {
+	ct.mu.Lock()
+	stats := CatchUpStats{
+		Readers:  ct.readers,
+		CaughtUp: ct.caughtUp,
+		Messages: ct.messages.Load(),
+		Bytes:    ct.bytes.Load(),
+		ReadWait: ct.waits.GetStats(),
+	}
+	start, end := ct.start, ct.end
+	ct.mu.Unlock()
+
+	if stats.Readers == 0 {
+		return stats
+	}
+	if !stats.Done() {
+		end = time.Now()
+	}
+	if end.After(start) {
+		stats.Duration = end.Sub(start)
+	}
+	if seconds := stats.Duration.Seconds(); seconds > 0 {
+		stats.Rate = float64(stats.Messages) / seconds
+		stats.Bandwidth = float64(stats.Bytes) / seconds
+	}
+	return stats
+}
+
+// Reset restarts the measurement for readers that are still catching up.
+// Once every reader has caught up the result is kept.
+func (ct *CatchUpTracker) Reset() {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	if ct.readers > 0 && ct.caughtUp == ct.readers {
+		return
+	}
+	ct.messages.Store(0)
+	ct.bytes.Store(0)
+	ct.waits.Reset()
+	if ct.readers > 0 {
+		ct.start = time.Now()
+	}
+}
+
+// Restart discards the current measurement, finished or not, and starts a new one at the
+// given time. Readers have to be recorded again.
+func (ct *CatchUpTracker) Restart(at time.Time) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	ct.readers = 0
+	ct.caughtUp = 0
+	ct.start = at
+	ct.end = time.Time{}
+	ct.messages.Store(0)
+	ct.bytes.Store(0)
+	ct.waits.Reset()
+}
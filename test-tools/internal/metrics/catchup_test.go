@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCatchUpTracker(t *testing.T) {
+	tracker := NewCatchUpTracker([]float64{1, 10, 100, 1000})
+	start := time.Now().Add(-time.Minute)
+
+	if stats := tracker.GetStats(); stats.Readers != 0 || stats.Done() {
+		t.Fatalf("expected no readers before start, got %+v", stats)
+	}
+
+	tracker.RecordStart(start)
+	tracker.RecordStart(start.Add(time.Second))
+	for i := 0; i < 1000; i++ {
+		tracker.RecordRead(100, time.Duration(i)*time.Microsecond)
+	}
+	tracker.RecordCaughtUp(start.Add(5 * time.Second))
+
+	stats := tracker.GetStats()
+	if stats.Readers != 2 || stats.CaughtUp != 1 || stats.Done() {
+		t.Fatalf("expected 1 of 2 readers caught up, got %+v", stats)
+	}
+	if stats.Duration < time.Minute {
+		t.Errorf("Duration = %v, want the time since start while catching up", stats.Duration)
+	}
+
+	tracker.RecordCaughtUp(start.Add(10 * time.Second))
+	stats = tracker.GetStats()
+	if !stats.Done() || stats.Duration != 10*time.Second {
+		t.Fatalf("expected done after 10s, got %+v", stats)
+	}
+	if stats.Messages != 1000 || stats.Bytes != 100000 || stats.Rate != 100 || stats.Bandwidth != 10000 {
+		t.Errorf("expected 1000 msgs at 100 msg/s, got %+v", stats)
+	}
+	if stats.ReadWait.Count != 1000 || stats.ReadWait.Max < 0.99 || stats.ReadWait.Max > 1 {
+		t.Errorf("expected 1000 read waits up to 1ms, got %+v", stats.ReadWait)
+	}
+
+	// The result of a finished catch-up survives a reset
+	tracker.Reset()
+	if got := tracker.GetStats(); got != stats {
+		t.Errorf("expected %+v after reset, got %+v", stats, got)
+	}
+}
+
+func TestCatchUpTrackerReset(t *testing.T) {
+	tracker := NewCatchUpTracker([]float64{1, 10, 100, 1000})
+	tracker.RecordStart(time.Now().Add(-time.Minute))
+	tracker.RecordRead(100, time.Millisecond)
+
+	tracker.Reset()
+	stats := tracker.GetStats()
+	if stats.Readers != 1 || stats.Messages != 0 || stats.Bytes != 0 || stats.ReadWait.Count != 0 {
+		t.Errorf("expected counters cleared for the running reader, got %+v", stats)
+	}
+	if stats.Duration > time.Second {
+		t.Errorf("expected the measurement to restart, got duration %v", stats.Duration)
+	}
+}
+
+func TestCatchUpTrackerRestart(t *testing.T) {
+	tracker := NewCatchUpTracker([]float64{1, 10, 100, 1000})
+	start := time.Now().Add(-time.Minute)
+	tracker.RecordStart(start)
+	tracker.RecordRead(100, time.Millisecond)
+	tracker.RecordCaughtUp(start.Add(time.Second))
+
+	// A seek discards the finished measurement and starts at the seek
+	seek := time.Now().Add(-10 * time.Second)
+	tracker.Restart(seek)
+	if stats := tracker.GetStats(); stats.Readers != 0 || stats.Messages != 0 || stats.ReadWait.Count != 0 {
+		t.Fatalf("expected an empty measurement after restart, got %+v", stats)
+	}
+
+	tracker.RecordStart(time.Now())
+	tracker.RecordRead(200, time.Millisecond)
+	tracker.RecordCaughtUp(seek.Add(2 * time.Second))
+	stats := tracker.GetStats()
+	if !stats.Done() || stats.Duration != 2*time.Second || stats.Messages != 1 || stats.Bytes != 200 {
+		t.Errorf("expected 1 message drained in 2s from the seek, got %+v", stats)
+	}
+}
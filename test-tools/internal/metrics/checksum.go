@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultVerifyBuckets are the checksum verification time histogram bucket boundaries
+// in microseconds
+var DefaultVerifyBuckets = []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 5000}
+
+// MaxCorruptIDs bounds the number of corrupt message IDs kept for the report; corrupt
+// messages beyond it are still counted
+const MaxCorruptIDs = 100
+
+// ChecksumTracker tracks payload checksum verification by consumers. Verification time is
+// kept apart from the receive metrics so its cost can be judged on its own.
+type ChecksumTracker struct {
+	verified    atomic.Uint64
+	corrupt     atomic.Uint64
+	verifyTimes *BucketHistogram // microseconds
+
+	mu         sync.Mutex
+	corruptIDs []string
+}
+
+// ChecksumStats summarizes payload checksum verification
+type ChecksumStats struct {
+	Verified   uint64       // messages whose checksum was checked, including corrupt ones
+	Corrupt    uint64       // messages whose payload did not match its checksum
+	VerifyTime LatencyStats // verification time per message in microseconds
+	CorruptIDs []string     // IDs of the first MaxCorruptIDs corrupt messages
+}
+
+// NewChecksumTracker creates a new checksum tracker
+func NewChecksumTracker() *ChecksumTracker {
+	return &ChecksumTracker{
+		verifyTimes: NewBucketHistogram(DefaultVerifyBuckets),
+	}
+}
+
+// RecordVerify records a checksum verification and how long it took
+func (ct *ChecksumTracker) RecordVerify(elapsed time.Duration) {
+	ct.verified.Add(1)
+	ct.verifyTimes.Observe(float64(elapsed) / float64(time.Microsecond))
+}
+
+// RecordCorrupt records a message whose payload did not match its checksum
+func (ct *ChecksumTracker) RecordCorrupt(messageID string) {
+	ct.corrupt.Add(1)
+
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	if len(ct.corruptIDs) < MaxCorruptIDs {
+		ct.corruptIDs = append(ct.corruptIDs, messageID)
+	}
+}
+
+// GetStats returns the checksum verification statistics
+func (ct *ChecksumTracker) GetStats() ChecksumStats {
+	ct.mu.Lock()
+	ids := make([]string, len(ct.corruptIDs))
+	copy(ids, ct.corruptIDs)
+	ct.mu.Unlock()
+
+	return ChecksumStats{
+		Verified:   ct.verified.Load(),
+		Corrupt:    ct.corrupt.Load(),
+		VerifyTime: ct.verifyTimes.GetStats(),
+		CorruptIDs: ids,
+	}
+}
+
+// Reset clears all checksum statistics
+func (ct *ChecksumTracker) Reset() {
+	ct.verified.Store(0)
+	ct.corrupt.Store(0)
+	ct.verifyTimes.Reset()
+
+	ct.mu.Lock()
+	ct.corruptIDs = nil
+	ct.mu.Unlock()
+}
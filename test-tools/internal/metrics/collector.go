@@ -18,11 +18,43 @@ type Collector struct {
 	bytesReceived atomic.Uint64
 
 	// Latency tracking
-	latencies *Histogram
+	latencies    *Histogram
+	e2eLatencies *Histogram // publish-to-receive latency measured by consumers
+
+	// Payload sizes of sent messages in bytes
+	messageSizes *BucketHistogram
 
 	// Throughput tracking
 	throughput *ThroughputTracker
 
+	// Chunked message tracking (subset of the message counters above)
+	chunkedSent          atomic.Uint64
+	chunkedReceived      atomic.Uint64
+	chunkedBytesSent     atomic.Uint64
+	chunkedBytesReceived atomic.Uint64
+	chunkedLatencies     *BucketHistogram
+
+	// Per-partition tracking
+	partitions *PartitionTracker
+
+	// Per-topic tracking of multi-topic workloads
+	topics *TopicTracker
+
+	// Per property value tracking of received messages
+	groups *GroupTracker
+
+	// Payload checksum verification by consumers
+	checksums *ChecksumTracker
+
+	// Consumer lag tracking
+	lag *LagTracker
+
+	// Catch-up of readers on existing messages, or of consumers on a replayed backlog
+	catchUp *CatchUpTracker
+
+	// Latest broker-side stats, set by the admin stats poller
+	brokerStats atomic.Pointer[BrokerStats]
+
 	// Timestamps
 	startTime time.Time
 	lastReset atomic.Value // stores time.Time
@@ -32,9 +64,18 @@ type Collector struct {
 func NewCollector(histogramBuckets []float64) *Collector {
 	now := time.Now()
 	c := &Collector{
-		latencies:  NewHistogram(histogramBuckets),
-		throughput: NewThroughputTracker(),
-		startTime:  now,
+		latencies:        NewHistogram(histogramBuckets),
+		e2eLatencies:     NewHistogram(histogramBuckets),
+		messageSizes:     NewBucketHistogram(DefaultSizeBuckets),
+		throughput:       NewThroughputTracker(),
+		chunkedLatencies: NewBucketHistogram(histogramBuckets),
+		partitions:       NewPartitionTracker(histogramBuckets),
+		topics:           NewTopicTracker(histogramBuckets),
+		groups:           NewGroupTracker(histogramBuckets),
+		checksums:        NewChecksumTracker(),
+		lag:              NewLagTracker(DefaultDrainWindow),
+		catchUp:          NewCatchUpTracker(histogramBuckets),
+		startTime:        now,
 	}
 	c.lastReset.Store(now)
 	return c
@@ -45,6 +86,7 @@ func (c *Collector) RecordSend(bytes int, latency time.Duration) {
 	c.messagesSent.Add(1)
 	c.bytesSent.Add(uint64(bytes))
 	c.latencies.Observe(float64(latency.Milliseconds()))
+	c.messageSizes.Observe(float64(bytes))
 	c.throughput.RecordSend(bytes)
 }
 
@@ -55,6 +97,12 @@ func (c *Collector) RecordReceive(bytes int) {
 	c.throughput.RecordReceive(bytes)
 }
 
+// RecordE2ELatency records the end-to-end latency of a received message with sub-millisecond precision.
+// It is called in addition to RecordReceive for the same message.
+func (c *Collector) RecordE2ELatency(latency time.Duration) {
+	c.e2eLatencies.Observe(float64(latency) / float64(time.Millisecond))
+}
+
 // RecordAck records a message acknowledgment with atomic operations for thread safety
 func (c *Collector) RecordAck() {
 	c.messagesAcked.Add(1)
@@ -65,6 +113,125 @@ func (c *Collector) RecordFailure() {
 	c.messagesFailed.Add(1)
 }
 
+// RecordChunkedSend records that a sent message was split into chunks.
+// It is called in addition to RecordSend for the same message.
+func (c *Collector) RecordChunkedSend(bytes int) {
+	c.chunkedSent.Add(1)
+	c.chunkedBytesSent.Add(uint64(bytes))
+}
+
+// RecordChunkedReceive records a message reassembled from chunks along with its end-to-end
+// latency (publish to delivery of the complete message, not the reassembly time alone).
+// It is called in addition to RecordReceive for the same message.
+func (c *Collector) RecordChunkedReceive(bytes int, latency time.Duration) {
+	c.chunkedReceived.Add(1)
+	c.chunkedBytesReceived.Add(uint64(bytes))
+	c.chunkedLatencies.Observe(float64(latency.Milliseconds()))
+}
+
+// RecordPartitionSend records the partition a sent message was routed to with its send latency.
+// It is called in addition to RecordSend for the same message.
+// Negative partition indexes (non-partitioned topics) are ignored.
+func (c *Collector) RecordPartitionSend(partition int32, bytes int, latency time.Duration) {
+	if partition < 0 {
+		return
+	}
+	c.partitions.RecordSend(partition, bytes, latency)
+}
+
+// RecordPartitionReceive records the partition a received message came from with its
+// publish-to-receive latency. It is called in addition to RecordReceive for the same message.
+// Negative partition indexes (non-partitioned topics) are ignored.
+func (c *Collector) RecordPartitionReceive(partition int32, bytes int, latency time.Duration) {
+	if partition < 0 {
+		return
+	}
+	c.partitions.RecordReceive(partition, bytes, latency)
+}
+
+// RecordTopicSend records the topic a sent message was routed to with its send latency.
+// It is called in addition to RecordSend for the same message.
+func (c *Collector) RecordTopicSend(topic string, bytes int, latency time.Duration) {
+	c.topics.RecordSend(topic, bytes, latency)
+}
+
+// RecordTopicReceive records the topic a received message came from with its
+// publish-to-receive latency. It is called in addition to RecordReceive for the same message.
+func (c *Collector) RecordTopicReceive(topic string, bytes int, latency time.Duration) {
+	c.topics.RecordReceive(topic, bytes, latency)
+}
+
+// RecordGroupReceive records the value of the grouping property of a received message
+// with its publish-to-receive latency. It is called in addition to RecordReceive for the
+// same message.
+func (c *Collector) RecordGroupReceive(value string, bytes int, latency time.Duration) {
+	c.groups.RecordReceive(value, bytes, latency)
+}
+
+// RecordChecksumVerify records the verification of a received message's payload checksum
+// and how long it took. It is called in addition to RecordReceive for the same message.
+func (c *Collector) RecordChecksumVerify(elapsed time.Duration) {
+	c.checksums.RecordVerify(elapsed)
+}
+
+// RecordCorrupt records a received message whose payload did not match its checksum.
+// Corrupt messages are counted separately from failures.
+func (c *Collector) RecordCorrupt(messageID string) {
+	c.checksums.RecordCorrupt(messageID)
+}
+
+// RecordLag records the publish-to-receive delay of a received message.
+// It is called in addition to RecordReceive for the same message.
+func (c *Collector) RecordLag(lag time.Duration) {
+	c.lag.RecordLag(lag)
+}
+
+// RecordCatchUpStart records a reader starting to read the messages that already exist
+func (c *Collector) RecordCatchUpStart() {
+	c.catchUp.RecordStart(time.Now())
+}
+
+// RecordCatchUpRead records a message read by a reader that has not caught up yet along
+// with how long the read waited for it.
+// It is called in addition to RecordReceive for the same message.
+func (c *Collector) RecordCatchUpRead(bytes int, wait time.Duration) {
+	c.catchUp.RecordRead(bytes, wait)
+}
+
+// RestartCatchUp starts a new catch-up measurement at the given time, e.g. when consumers
+// seek their subscription back to replay a backlog
+func (c *Collector) RestartCatchUp(at time.Time) {
+	c.catchUp.Restart(at)
+}
+
+// RecordCaughtUp records a reader reaching the end of the topic at the given time
+func (c *Collector) RecordCaughtUp(at time.Time) {
+	c.catchUp.RecordCaughtUp(at)
+}
+
+// MessagesSent returns the number of messages sent so far without taking a snapshot
+func (c *Collector) MessagesSent() uint64 {
+	return c.messagesSent.Load()
+}
+
+// RecordBacklog records the number of unacknowledged messages in the subscription as
+// polled at the given time
+func (c *Collector) RecordBacklog(backlog int64, at time.Time) {
+	c.lag.RecordBacklog(backlog, at)
+}
+
+// BacklogDrainedSince reports whether a backlog poll started at or after t found the
+// subscription empty
+func (c *Collector) BacklogDrainedSince(t time.Time) bool {
+	return c.lag.BacklogDrainedSince(t)
+}
+
+// SetBrokerStats stores the latest broker-side topic stats so they are included in snapshots.
+// Broker stats are not cleared by Reset since they reflect the broker, not this client.
+func (c *Collector) SetBrokerStats(stats BrokerStats) {
+	c.brokerStats.Store(&stats)
+}
+
 // GetSnapshot returns a snapshot of current metrics using atomic loads for thread safety
 func (c *Collector) GetSnapshot() Snapshot {
 	elapsed := time.Since(c.startTime)
@@ -79,9 +246,25 @@ func (c *Collector) GetSnapshot() Snapshot {
 		BytesSent:        c.bytesSent.Load(),
 		BytesReceived:    c.bytesReceived.Load(),
 		LatencyStats:     c.latencies.GetStats(),
+		E2ELatency:       c.e2eLatencies.GetStats(),
+		MessageSizes:     newSizeStats(c.messageSizes),
 		Throughput:       c.throughput.GetStats(),
-		Elapsed:          elapsed,
-		SinceReset:       sinceReset,
+		Chunked: ChunkedStats{
+			MessagesSent:     c.chunkedSent.Load(),
+			MessagesReceived: c.chunkedReceived.Load(),
+			BytesSent:        c.chunkedBytesSent.Load(),
+			BytesReceived:    c.chunkedBytesReceived.Load(),
+			E2ELatency:       c.chunkedLatencies.GetStats(),
+		},
+		Partitions: c.partitions.GetStats(),
+		Topics:     c.topics.GetStats(),
+		Groups:     c.groups.GetStats(),
+		Checksums:  c.checksums.GetStats(),
+		Lag:        c.lag.GetStats(),
+		CatchUp:    c.catchUp.GetStats(),
+		Broker:     c.brokerStats.Load(),
+		Elapsed:    elapsed,
+		SinceReset: sinceReset,
 	}
 }
 
@@ -94,7 +277,20 @@ func (c *Collector) Reset() {
 	c.bytesSent.Store(0)
 	c.bytesReceived.Store(0)
 	c.latencies.Reset()
+	c.e2eLatencies.Reset()
+	c.messageSizes.Reset()
 	c.throughput.Reset()
+	c.chunkedSent.Store(0)
+	c.chunkedReceived.Store(0)
+	c.chunkedBytesSent.Store(0)
+	c.chunkedBytesReceived.Store(0)
+	c.chunkedLatencies.Reset()
+	c.partitions.Reset()
+	c.topics.Reset()
+	c.groups.Reset()
+	c.checksums.Reset()
+	c.lag.Reset()
+	c.catchUp.Reset()
 	c.lastReset.Store(time.Now())
 }
 
@@ -106,12 +302,32 @@ type Snapshot struct {
 	MessagesFailed   uint64
 	BytesSent        uint64
 	BytesReceived    uint64
-	LatencyStats     LatencyStats
+	LatencyStats     LatencyStats // send latency (producers)
+	E2ELatency       LatencyStats // publish-to-receive latency (consumers)
+	MessageSizes     SizeStats    // payload sizes of sent messages (producers)
 	Throughput       ThroughputStats
+	Chunked          ChunkedStats
+	Partitions       []PartitionStats
+	Topics           []TopicStats  // per-topic metrics of multi-topic workloads
+	Groups           []GroupStats  // receive metrics per grouping property value (consumers)
+	Checksums        ChecksumStats // payload checksum verification (consumers)
+	Lag              LagStats
+	CatchUp          CatchUpStats // catch-up of readers (reader mode) or of consumers after a seek
+	Broker           *BrokerStats // nil until broker stats have been polled
 	Elapsed          time.Duration
 	SinceReset       time.Duration
 }
 
+// ChunkedStats summarizes messages that were split into chunks by the producer
+// and reassembled by the consumer
+type ChunkedStats struct {
+	MessagesSent     uint64
+	MessagesReceived uint64
+	BytesSent        uint64
+	BytesReceived    uint64
+	E2ELatency       LatencyStats
+}
+
 // MessageRate returns messages per second since start
 func (s Snapshot) MessageRate() float64 {
 	seconds := s.Elapsed.Seconds()
@@ -121,6 +337,37 @@ func (s Snapshot) MessageRate() float64 {
 	return float64(s.MessagesSent) / seconds
 }
 
+// SendSkew returns the skew of sent messages across partitions (see PartitionSkew)
+func (s Snapshot) SendSkew() float64 {
+	counts := make([]uint64, len(s.Partitions))
+	for i, p := range s.Partitions {
+		counts[i] = p.MessagesSent
+	}
+	return PartitionSkew(counts)
+}
+
+// ReceiveSkew returns the skew of received messages across partitions (see PartitionSkew)
+func (s Snapshot) ReceiveSkew() float64 {
+	counts := make([]uint64, len(s.Partitions))
+	for i, p := range s.Partitions {
+		counts[i] = p.MessagesReceived
+	}
+	return PartitionSkew(counts)
+}
+
+// WireCompressionRatio returns the size of sent messages as received by the broker divided
+// by their payload size, or 0 until broker stats report incoming traffic. The broker's
+// size includes compression and message metadata, so this is the achieved ratio on the
+// wire (e.g., 0.3 when payloads compress to 30%).
+func (s Snapshot) WireCompressionRatio() float64 {
+	if s.Broker == nil || s.Broker.MsgRateIn <= 0 || s.BytesSent == 0 {
+		return 0
+	}
+	wireSize := s.Broker.MsgThroughputIn / s.Broker.MsgRateIn
+	payloadSize := float64(s.BytesSent) / float64(s.MessagesSent)
+	return wireSize / payloadSize
+}
+
 // ThroughputMBps returns throughput in MB/s since start
 func (s Snapshot) ThroughputMBps() float64 {
 	seconds := s.Elapsed.Seconds()
@@ -200,7 +200,7 @@ func BenchmarkThroughputTrackerRecordSend(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		tracker.RecordSend()
+		tracker.RecordSend(1024)
 	}
 }
 
@@ -210,7 +210,7 @@ func BenchmarkThroughputTrackerRecordSendParallel(b *testing.B) {
 	b.ResetTimer()
 	b.RunParallel(func(pb *testing.PB) {
 		for pb.Next() {
-			tracker.RecordSend()
+			tracker.RecordSend(1024)
 		}
 	})
 }
@@ -220,8 +220,8 @@ func BenchmarkThroughputTrackerGetStats(b *testing.B) {
 
 	// Pre-populate with data
 	for i := 0; i < 1000; i++ {
-		tracker.RecordSend()
-		tracker.RecordReceive()
+		tracker.RecordSend(1024)
+		tracker.RecordReceive(1024)
 	}
 
 	b.ResetTimer()
@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"fmt"
+	"math"
 	"sync"
 	"testing"
 	"time"
@@ -52,6 +54,43 @@ func TestCollectorRecordSend(t *testing.T) {
 	}
 }
 
+func TestCollectorMessageSizes(t *testing.T) {
+	collector := NewCollector([]float64{1, 10, 100, 1000})
+
+	for i := 0; i < 10; i++ {
+		collector.RecordSend(1024, time.Millisecond)
+	}
+	if sizes := collector.GetSnapshot().MessageSizes; sizes.Varies() {
+		t.Errorf("fixed-size messages should not vary: %+v", sizes)
+	}
+
+	collector.RecordSend(100, time.Millisecond)
+	collector.RecordSend(70000, time.Millisecond)
+
+	sizes := collector.GetSnapshot().MessageSizes
+	if !sizes.Varies() {
+		t.Error("mixed sizes should vary")
+	}
+	if sizes.Count != 12 || sizes.Min != 100 || sizes.Max != 70000 {
+		t.Errorf("expected 12 sizes in [100, 70000], got %d in [%.0f, %.0f]", sizes.Count, sizes.Min, sizes.Max)
+	}
+
+	want := []BucketCount{{128, 1}, {1024, 10}, {131072, 1}}
+	if len(sizes.Buckets) != len(want) {
+		t.Fatalf("expected %d non-empty buckets, got %+v", len(want), sizes.Buckets)
+	}
+	for i := range want {
+		if sizes.Buckets[i] != want[i] {
+			t.Errorf("bucket %d: expected %+v, got %+v", i, want[i], sizes.Buckets[i])
+		}
+	}
+
+	collector.Reset()
+	if sizes := collector.GetSnapshot().MessageSizes; sizes.Count != 0 {
+		t.Errorf("expected no sizes after reset, got %d", sizes.Count)
+	}
+}
+
 func TestCollectorRecordReceive(t *testing.T) {
 	collector := NewCollector([]float64{1, 10, 100, 1000})
 
@@ -70,6 +109,31 @@ func TestCollectorRecordReceive(t *testing.T) {
 	}
 }
 
+func TestCollectorRecordE2ELatency(t *testing.T) {
+	collector := NewCollector([]float64{1, 10, 100, 1000})
+
+	collector.RecordSend(100, 50*time.Millisecond)
+	collector.RecordE2ELatency(1500 * time.Microsecond)
+	collector.RecordE2ELatency(2500 * time.Microsecond)
+
+	snapshot := collector.GetSnapshot()
+	if snapshot.E2ELatency.Count != 2 {
+		t.Fatalf("E2ELatency.Count = %d, want 2", snapshot.E2ELatency.Count)
+	}
+	if snapshot.E2ELatency.Min != 1.5 || snapshot.E2ELatency.Max != 2.5 {
+		t.Errorf("E2ELatency min/max = %.2f/%.2f, want sub-millisecond precision 1.50/2.50",
+			snapshot.E2ELatency.Min, snapshot.E2ELatency.Max)
+	}
+	if snapshot.LatencyStats.Count != 1 {
+		t.Errorf("send latencies should be tracked separately, got count %d", snapshot.LatencyStats.Count)
+	}
+
+	collector.Reset()
+	if snapshot := collector.GetSnapshot(); snapshot.E2ELatency.Count != 0 {
+		t.Errorf("E2ELatency.Count after reset = %d, want 0", snapshot.E2ELatency.Count)
+	}
+}
+
 func TestCollectorRecordAck(t *testing.T) {
 	collector := NewCollector([]float64{1, 10, 100, 1000})
 
@@ -97,6 +161,251 @@ func TestCollectorRecordFailure(t *testing.T) {
 	}
 }
 
+func TestCollectorRecordChunked(t *testing.T) {
+	collector := NewCollector([]float64{1, 10, 100, 1000})
+
+	collector.RecordChunkedSend(8 * 1024 * 1024)
+	collector.RecordChunkedReceive(8*1024*1024, 40*time.Millisecond)
+	collector.RecordChunkedReceive(8*1024*1024, 60*time.Millisecond)
+
+	snapshot := collector.GetSnapshot()
+	if snapshot.Chunked.MessagesSent != 1 {
+		t.Errorf("Expected 1 chunked message sent, got %d", snapshot.Chunked.MessagesSent)
+	}
+	if snapshot.Chunked.MessagesReceived != 2 {
+		t.Errorf("Expected 2 chunked messages received, got %d", snapshot.Chunked.MessagesReceived)
+	}
+	if snapshot.Chunked.BytesReceived != 16*1024*1024 {
+		t.Errorf("Expected %d chunked bytes received, got %d", 16*1024*1024, snapshot.Chunked.BytesReceived)
+	}
+	if snapshot.Chunked.E2ELatency.Count != 2 {
+		t.Errorf("Expected 2 chunked end-to-end latency samples, got %d", snapshot.Chunked.E2ELatency.Count)
+	}
+	if snapshot.Chunked.E2ELatency.Max != 60 {
+		t.Errorf("Expected max chunked end-to-end latency 60ms, got %.2f", snapshot.Chunked.E2ELatency.Max)
+	}
+
+	// Chunked counters must not leak into the aggregate counters
+	if snapshot.MessagesSent != 0 || snapshot.MessagesReceived != 0 {
+		t.Error("RecordChunked* should not change aggregate message counters")
+	}
+
+	collector.Reset()
+	snapshot = collector.GetSnapshot()
+	if snapshot.Chunked.MessagesSent != 0 || snapshot.Chunked.MessagesReceived != 0 {
+		t.Error("Chunked counters should be reset")
+	}
+}
+
+func TestCollectorRecordPartition(t *testing.T) {
+	collector := NewCollector([]float64{1, 10, 100, 1000})
+
+	collector.RecordPartitionSend(2, 100, 5*time.Millisecond)
+	collector.RecordPartitionSend(0, 100, 15*time.Millisecond)
+	collector.RecordPartitionSend(2, 100, 5*time.Millisecond)
+	collector.RecordPartitionSend(-1, 100, time.Millisecond) // non-partitioned topic
+	collector.RecordPartitionReceive(0, 100, 20*time.Millisecond)
+
+	snapshot := collector.GetSnapshot()
+	if len(snapshot.Partitions) != 2 {
+		t.Fatalf("Expected 2 partitions, got %d", len(snapshot.Partitions))
+	}
+
+	p0, p2 := snapshot.Partitions[0], snapshot.Partitions[1]
+	if p0.Partition != 0 || p0.MessagesSent != 1 || p0.MessagesReceived != 1 {
+		t.Errorf("Expected partition 0 with 1 sent and 1 received, got %+v", p0)
+	}
+	if p2.Partition != 2 || p2.MessagesSent != 2 || p2.BytesSent != 200 {
+		t.Errorf("Expected partition 2 with 2 sent and 200 bytes, got %+v", p2)
+	}
+	if p0.SendLatency.Max != 15 || p0.ReceiveLatency.Max != 20 {
+		t.Errorf("Expected partition 0 max send latency 15ms and receive latency 20ms, got %.2f and %.2f",
+			p0.SendLatency.Max, p0.ReceiveLatency.Max)
+	}
+	if p2.SendRate <= 0 {
+		t.Errorf("Expected positive send rate for partition 2, got %.2f", p2.SendRate)
+	}
+
+	// Partition 2 has 2 of 3 sends: max/mean = 2 / 1.5
+	if skew := snapshot.SendSkew(); math.Abs(skew-4.0/3.0) > 0.001 {
+		t.Errorf("SendSkew() = %.3f, want %.3f", skew, 4.0/3.0)
+	}
+
+	// Partition tracking must not change aggregate counters
+	if snapshot.MessagesSent != 0 || snapshot.MessagesReceived != 0 {
+		t.Error("RecordPartition* should not change aggregate message counters")
+	}
+
+	collector.Reset()
+	if snapshot = collector.GetSnapshot(); len(snapshot.Partitions) != 0 {
+		t.Errorf("Expected no partitions after reset, got %d", len(snapshot.Partitions))
+	}
+}
+
+func TestCollectorRecordGroup(t *testing.T) {
+	collector := NewCollector([]float64{1, 10, 100, 1000})
+
+	collector.RecordGroupReceive("eu", 100, 5*time.Millisecond)
+	collector.RecordGroupReceive("us", 100, 15*time.Millisecond)
+	collector.RecordGroupReceive("us", 100, 25*time.Millisecond)
+	collector.RecordGroupReceive(GroupNone, 50, time.Millisecond)
+
+	snapshot := collector.GetSnapshot()
+	if len(snapshot.Groups) != 3 {
+		t.Fatalf("Expected 3 groups, got %d", len(snapshot.Groups))
+	}
+	// Busiest group first, ties by value
+	us := snapshot.Groups[0]
+	if us.Value != "us" || us.MessagesReceived != 2 || us.BytesReceived != 200 || us.Latency.Max != 25 {
+		t.Errorf("Expected group us with 2 messages, 200 bytes and 25ms max latency, got %+v", us)
+	}
+	if snapshot.Groups[1].Value != GroupNone || snapshot.Groups[2].Value != "eu" {
+		t.Errorf("Expected groups ordered us, %s, eu, got %+v", GroupNone, snapshot.Groups)
+	}
+
+	collector.Reset()
+	if snapshot = collector.GetSnapshot(); len(snapshot.Groups) != 0 {
+		t.Errorf("Expected no groups after reset, got %d", len(snapshot.Groups))
+	}
+}
+
+func TestCollectorRecordTopics(t *testing.T) {
+	collector := NewCollector([]float64{1, 10, 100, 1000})
+
+	collector.RecordTopicSend("orders", 100, 2*time.Millisecond)
+	collector.RecordTopicSend("orders", 100, 4*time.Millisecond)
+	collector.RecordTopicSend("payments", 50, 8*time.Millisecond)
+	collector.RecordTopicReceive("payments", 50, 12*time.Millisecond)
+
+	snapshot := collector.GetSnapshot()
+	if len(snapshot.Topics) != 2 {
+		t.Fatalf("Expected 2 topics, got %d", len(snapshot.Topics))
+	}
+	// Ties by message count are ordered by name
+	orders, payments := snapshot.Topics[0], snapshot.Topics[1]
+	if orders.Topic != "orders" || orders.MessagesSent != 2 || orders.BytesSent != 200 || orders.SendLatency.Max != 4 {
+		t.Errorf("Expected orders with 2 messages, 200 bytes and 4ms max latency, got %+v", orders)
+	}
+	if payments.Topic != "payments" || payments.MessagesSent != 1 || payments.MessagesReceived != 1 || payments.BytesReceived != 50 {
+		t.Errorf("Expected payments with 1 sent and 1 received message, got %+v", payments)
+	}
+	if payments.SendLatency.Max != 8 || payments.ReceiveLatency.Max != 12 {
+		t.Errorf("Expected payments send latency 8ms and receive latency 12ms, got %.2f and %.2f",
+			payments.SendLatency.Max, payments.ReceiveLatency.Max)
+	}
+
+	collector.Reset()
+	if snapshot = collector.GetSnapshot(); len(snapshot.Topics) != 0 {
+		t.Errorf("Expected no topics after reset, got %d", len(snapshot.Topics))
+	}
+}
+
+func TestCollectorRecordChecksums(t *testing.T) {
+	collector := NewCollector([]float64{1, 10, 100, 1000})
+
+	collector.RecordReceive(100)
+	collector.RecordChecksumVerify(2 * time.Microsecond)
+	collector.RecordReceive(100)
+	collector.RecordChecksumVerify(8 * time.Microsecond)
+	collector.RecordCorrupt("1:2:-1:0")
+	for i := 0; i < MaxCorruptIDs+5; i++ {
+		collector.RecordCorrupt(fmt.Sprintf("1:%d:-1:0", i))
+	}
+
+	snapshot := collector.GetSnapshot()
+	checksums := snapshot.Checksums
+	if checksums.Verified != 2 || checksums.Corrupt != MaxCorruptIDs+6 {
+		t.Errorf("Expected 2 verified and %d corrupt messages, got %+v", MaxCorruptIDs+6, checksums)
+	}
+	if checksums.VerifyTime.Min != 2 || checksums.VerifyTime.Max != 8 {
+		t.Errorf("Expected verify times between 2 and 8 microseconds, got %+v", checksums.VerifyTime)
+	}
+	if len(checksums.CorruptIDs) != MaxCorruptIDs || checksums.CorruptIDs[0] != "1:2:-1:0" {
+		t.Errorf("Expected the first %d corrupt IDs, got %d starting with %v", MaxCorruptIDs, len(checksums.CorruptIDs), checksums.CorruptIDs[:1])
+	}
+	// Corrupt messages are not failures
+	if snapshot.MessagesFailed != 0 {
+		t.Errorf("Expected no failed messages, got %d", snapshot.MessagesFailed)
+	}
+
+	collector.Reset()
+	if checksums = collector.GetSnapshot().Checksums; checksums.Corrupt != 0 || len(checksums.CorruptIDs) != 0 {
+		t.Errorf("Expected no corrupt messages after reset, got %+v", checksums)
+	}
+}
+
+func TestGroupTrackerMaxGroups(t *testing.T) {
+	tracker := NewGroupTracker([]float64{1, 10})
+	for i := 0; i < MaxGroups+50; i++ {
+		tracker.RecordReceive(fmt.Sprintf("seq-%d", i), 10, time.Millisecond)
+	}
+
+	stats := tracker.GetStats()
+	if len(stats) != MaxGroups+1 {
+		t.Fatalf("Expected %d groups and %s, got %d", MaxGroups, GroupOther, len(stats))
+	}
+	if stats[0].Value != GroupOther || stats[0].MessagesReceived != 50 {
+		t.Errorf("Expected the values beyond the limit in %s, got %+v", GroupOther, stats[0])
+	}
+}
+
+func TestPartitionSkew(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts []uint64
+		want   float64
+	}{
+		{"empty", nil, 0},
+		{"all zero", []uint64{0, 0}, 0},
+		{"even", []uint64{10, 10, 10, 10}, 1},
+		{"hot partition", []uint64{70, 10, 10, 10}, 2.8},
+		{"single partition", []uint64{5}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PartitionSkew(tt.counts); math.Abs(got-tt.want) > 0.001 {
+				t.Errorf("PartitionSkew(%v) = %.3f, want %.3f", tt.counts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectorBrokerStats(t *testing.T) {
+	collector := NewCollector([]float64{1, 10, 100})
+
+	if snapshot := collector.GetSnapshot(); snapshot.Broker != nil {
+		t.Fatalf("Broker = %+v before any poll, want nil", snapshot.Broker)
+	}
+
+	collector.SetBrokerStats(BrokerStats{
+		Topic:     "persistent://public/default/perf",
+		MsgRateIn: 100,
+		Subscriptions: []SubscriptionStats{
+			{Name: "sub-a", MsgBacklog: 40, UnackedMessages: 5},
+			{Name: "sub-b", MsgBacklog: 2},
+		},
+	})
+	collector.Reset()
+
+	broker := collector.GetSnapshot().Broker
+	if broker == nil {
+		t.Fatal("Broker stats should survive Reset")
+	}
+	if broker.MsgRateIn != 100 {
+		t.Errorf("MsgRateIn = %.0f, want 100", broker.MsgRateIn)
+	}
+	if got := broker.TotalBacklog(); got != 42 {
+		t.Errorf("TotalBacklog() = %d, want 42", got)
+	}
+	if sub := broker.Subscription("sub-a"); sub == nil || sub.UnackedMessages != 5 {
+		t.Errorf("Subscription(sub-a) = %+v, want UnackedMessages 5", sub)
+	}
+	if sub := broker.Subscription("missing"); sub != nil {
+		t.Errorf("Subscription(missing) = %+v, want nil", sub)
+	}
+}
+
 func TestCollectorReset(t *testing.T) {
 	collector := NewCollector([]float64{1, 10, 100, 1000})
 
@@ -242,4 +551,44 @@ func TestSnapshotThroughputMBps(t *testing.T) {
 	if throughput != 0 {
 		t.Errorf("Expected throughput 0 for zero elapsed time, got %f", throughput)
 	}
-}
\ No newline at end of file
+}
+func TestSnapshotWireCompressionRatio(t *testing.T) {
+	tests := []struct {
+		name     string
+		snapshot Snapshot
+		want     float64
+	}{
+		{
+			name:     "no broker stats",
+			snapshot: Snapshot{MessagesSent: 100, BytesSent: 102400},
+			want:     0,
+		},
+		{
+			name:     "no traffic at broker",
+			snapshot: Snapshot{MessagesSent: 100, BytesSent: 102400, Broker: &BrokerStats{}},
+			want:     0,
+		},
+		{
+			name:     "nothing sent",
+			snapshot: Snapshot{Broker: &BrokerStats{MsgRateIn: 100, MsgThroughputIn: 30720}},
+			want:     0,
+		},
+		{
+			name: "compressed to 30%",
+			snapshot: Snapshot{
+				MessagesSent: 100,
+				BytesSent:    102400,
+				Broker:       &BrokerStats{MsgRateIn: 1000, MsgThroughputIn: 1000 * 307.2},
+			},
+			want: 0.3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.snapshot.WireCompressionRatio(); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("expected ratio %.3f, got %.3f", tt.want, got)
+			}
+		})
+	}
+}
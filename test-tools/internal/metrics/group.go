@@ -0,0 +1,128 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Group names for messages that do not fit a property value group
+const (
+	// GroupNone collects messages without the grouping property
+	GroupNone = "(none)"
+
+	// GroupOther collects messages whose value arrived after MaxGroups distinct values
+	GroupOther = "(other)"
+)
+
+// MaxGroups bounds the number of distinct property values tracked, so grouping by a
+// high-cardinality property (such as a sequence number) cannot exhaust memory. Further
+// values are counted in GroupOther.
+const MaxGroups = 100
+
+// GroupTracker tracks received messages, rates and latency per value of a message
+// property. Groups are created lazily the first time a value is seen.
+type GroupTracker struct {
+	mu      sync.RWMutex
+	buckets []float64
+	groups  map[string]*groupCounters
+}
+
+// groupCounters holds the metrics for a single property value
+type groupCounters struct {
+	received      atomic.Uint64
+	bytesReceived atomic.Uint64
+	latencies     *BucketHistogram
+	throughput    *ThroughputTracker
+}
+
+// GroupStats represents the receive metrics of one property value
+type GroupStats struct {
+	Value            string
+	MessagesReceived uint64
+	BytesReceived    uint64
+	ReceiveRate      float64 // messages per second over the throughput window
+	Latency          LatencyStats
+}
+
+// NewGroupTracker creates a new group tracker using the given latency histogram buckets
+func NewGroupTracker(histogramBuckets []float64) *GroupTracker {
+	return &GroupTracker{
+		buckets: histogramBuckets,
+		groups:  make(map[string]*groupCounters),
+	}
+}
+
+// counters returns the counters for a value, creating them if needed
+func (gt *GroupTracker) counters(value string) *groupCounters {
+	gt.mu.RLock()
+	gc, ok := gt.groups[value]
+	gt.mu.RUnlock()
+	if ok {
+		return gc
+	}
+
+	gt.mu.Lock()
+	defer gt.mu.Unlock()
+	if gc, ok = gt.groups[value]; ok {
+		return gc
+	}
+	if len(gt.groups) >= MaxGroups && value != GroupOther {
+		value = GroupOther
+		if gc, ok = gt.groups[value]; ok {
+			return gc
+		}
+	}
+	gc = &groupCounters{
+		latencies:  NewBucketHistogram(gt.buckets),
+		throughput: NewThroughputTracker(),
+	}
+	gt.groups[value] = gc
+	return gc
+}
+
+// RecordReceive records a received message with the given property value along with its
+// end-to-end latency
+func (gt *GroupTracker) RecordReceive(value string, bytes int, latency time.Duration) {
+	gc := gt.counters(value)
+	gc.received.Add(1)
+	gc.bytesReceived.Add(uint64(bytes))
+	gc.latencies.Observe(float64(latency) / float64(time.Millisecond))
+	gc.throughput.RecordReceive(bytes)
+}
+
+// GetStats returns per-value statistics, busiest first
+func (gt *GroupTracker) GetStats() []GroupStats {
+	gt.mu.RLock()
+	defer gt.mu.RUnlock()
+
+	if len(gt.groups) == 0 {
+		return nil
+	}
+
+	stats := make([]GroupStats, 0, len(gt.groups))
+	for value, gc := range gt.groups {
+		stats = append(stats, GroupStats{
+			Value:            value,
+			MessagesReceived: gc.received.Load(),
+			BytesReceived:    gc.bytesReceived.Load(),
+			ReceiveRate:      gc.throughput.GetStats().ReceiveRate,
+			Latency:          gc.latencies.GetStats(),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].MessagesReceived != stats[j].MessagesReceived {
+			return stats[i].MessagesReceived > stats[j].MessagesReceived
+		}
+		return stats[i].Value < stats[j].Value
+	})
+	return stats
+}
+
+// Reset clears all group counters
+func (gt *GroupTracker) Reset() {
+	gt.mu.Lock()
+	defer gt.mu.Unlock()
+	gt.groups = make(map[string]*groupCounters)
+}
@@ -128,4 +128,27 @@ func percentile(sorted []float64, p float64) float64 {
 	// Linear interpolation
 	weight := index - float64(lower)
 	return sorted[lower]*(1-weight) + sorted[upper]*weight
-}
\ No newline at end of file
+}
+
+// BucketCount is the number of observations in a histogram bucket, which holds values
+// above the previous bucket's upper bound and at or below its own
+type BucketCount struct {
+	UpperBound float64 // +Inf for the overflow bucket
+	Count      uint64
+}
+
+// Buckets returns the observation count of every bucket, ending with the overflow bucket
+func (h *Histogram) Buckets() []BucketCount {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	buckets := make([]BucketCount, len(h.counts))
+	for i, count := range h.counts {
+		upper := math.Inf(1)
+		if i < len(h.buckets) {
+			upper = h.buckets[i]
+		}
+		buckets[i] = BucketCount{UpperBound: upper, Count: count}
+	}
+	return buckets
+}
@@ -234,4 +234,26 @@ func TestPercentileFunction(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestHistogramBuckets(t *testing.T) {
+	hist := NewHistogram([]float64{10, 50, 100})
+	for _, v := range []float64{1, 10, 11, 50, 99, 150, 1000} {
+		hist.Observe(v)
+	}
+
+	want := []BucketCount{
+		{UpperBound: 10, Count: 2},
+		{UpperBound: 50, Count: 2},
+		{UpperBound: 100, Count: 1},
+		{UpperBound: math.Inf(1), Count: 2},
+	}
+	got := hist.Buckets()
+	if len(got) != len(want) {
+		t.Fatalf("expected %d buckets, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("bucket %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+}
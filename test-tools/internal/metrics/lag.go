@@ -0,0 +1,146 @@
+package metrics
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultDrainWindow is how far back backlog samples are kept to estimate the drain rate
+const DefaultDrainWindow = 30 * time.Second
+
+// LagTracker tracks how far consumers are behind producers: the subscription backlog
+// (messages not yet acknowledged, sampled from broker stats) and the lag in time
+// (publish-to-receive delay of the most recently received message).
+type LagTracker struct {
+	mu           sync.Mutex
+	window       time.Duration
+	samples      []backlogSample // backlog samples within the drain window, oldest first
+	hasBacklog   bool
+	backlog      int64
+	maxBacklog   int64
+	maxBacklogAt time.Time
+
+	lag    atomic.Int64 // nanoseconds
+	maxLag atomic.Int64 // nanoseconds
+}
+
+// backlogSample is a backlog reading at a point in time
+type backlogSample struct {
+	at      time.Time
+	backlog int64
+}
+
+// LagStats summarizes consumer lag at a point in time
+type LagStats struct {
+	HasBacklog   bool // false until a backlog sample has been recorded
+	Backlog      int64
+	MaxBacklog   int64
+	MaxBacklogAt time.Time
+	DrainRate    float64 // messages per second the backlog shrinks by (negative while growing)
+	Lag          time.Duration
+	MaxLag       time.Duration
+}
+
+// NewLagTracker creates a lag tracker that estimates the drain rate over the given window
+func NewLagTracker(window time.Duration) *LagTracker {
+	if window <= 0 {
+		window = DefaultDrainWindow
+	}
+	return &LagTracker{window: window}
+}
+
+// RecordBacklog records the subscription backlog observed at the given time
+func (l *LagTracker) RecordBacklog(backlog int64, at time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.hasBacklog = true
+	l.backlog = backlog
+	if backlog > l.maxBacklog || l.maxBacklogAt.IsZero() {
+		l.maxBacklog = backlog
+		l.maxBacklogAt = at
+	}
+
+	l.samples = append(l.samples, backlogSample{at: at, backlog: backlog})
+	cutoff := at.Add(-l.window)
+	i := 0
+	for i < len(l.samples)-1 && l.samples[i].at.Before(cutoff) {
+		i++
+	}
+	l.samples = l.samples[i:]
+}
+
+// BacklogDrainedSince reports whether the latest backlog sample was taken at or after t and
+// found the subscription empty
+func (l *LagTracker) BacklogDrainedSince(t time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.samples) == 0 {
+		return false
+	}
+	last := l.samples[len(l.samples)-1]
+	return last.backlog == 0 && !last.at.Before(t)
+}
+
+// RecordLag records the publish-to-receive delay of a received message
+func (l *LagTracker) RecordLag(lag time.Duration) {
+	l.lag.Store(int64(lag))
+	for {
+		current := l.maxLag.Load()
+		if int64(lag) <= current || l.maxLag.CompareAndSwap(current, int64(lag)) {
+			return
+		}
+	}
+}
+
+// GetStats returns the current lag statistics
+func (l *LagTracker) GetStats() LagStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := LagStats{
+		HasBacklog:   l.hasBacklog,
+		Backlog:      l.backlog,
+		MaxBacklog:   l.maxBacklog,
+		MaxBacklogAt: l.maxBacklogAt,
+		Lag:          time.Duration(l.lag.Load()),
+		MaxLag:       time.Duration(l.maxLag.Load()),
+	}
+
+	if len(l.samples) >= 2 {
+		first, last := l.samples[0], l.samples[len(l.samples)-1]
+		if elapsed := last.at.Sub(first.at).Seconds(); elapsed > 0 {
+			stats.DrainRate = float64(first.backlog-last.backlog) / elapsed
+		}
+	}
+
+	return stats
+}
+
+// Reset clears all backlog samples and lag measurements
+func (l *LagTracker) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.samples = nil
+	l.hasBacklog = false
+	l.backlog = 0
+	l.maxBacklog = 0
+	l.maxBacklogAt = time.Time{}
+	l.lag.Store(0)
+	l.maxLag.Store(0)
+}
+
+// DrainTime estimates how long it takes to consume the current backlog at the current
+// drain rate. It returns false when the backlog is not shrinking.
+func (s LagStats) DrainTime() (time.Duration, bool) {
+	if s.Backlog <= 0 {
+		return 0, true
+	}
+	if s.DrainRate <= 0 {
+		return 0, false
+	}
+	return time.Duration(float64(s.Backlog) / s.DrainRate * float64(time.Second)), true
+}
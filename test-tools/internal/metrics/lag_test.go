@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLagTrackerBacklog(t *testing.T) {
+	tracker := NewLagTracker(30 * time.Second)
+	start := time.Now()
+
+	if stats := tracker.GetStats(); stats.HasBacklog {
+		t.Fatalf("HasBacklog should be false before any sample, got %+v", stats)
+	}
+
+	tracker.RecordBacklog(1000, start)
+	tracker.RecordBacklog(1500, start.Add(10*time.Second))
+	tracker.RecordBacklog(900, start.Add(20*time.Second))
+	tracker.RecordBacklog(500, start.Add(40*time.Second))
+
+	stats := tracker.GetStats()
+	if !stats.HasBacklog || stats.Backlog != 500 {
+		t.Errorf("Backlog = %d (has %v), want 500", stats.Backlog, stats.HasBacklog)
+	}
+	if stats.MaxBacklog != 1500 || !stats.MaxBacklogAt.Equal(start.Add(10*time.Second)) {
+		t.Errorf("MaxBacklog = %d at %v, want 1500 at +10s", stats.MaxBacklog, stats.MaxBacklogAt.Sub(start))
+	}
+
+	// Samples older than the window are dropped: rate is (1500-500)/30s
+	if want := 1000.0 / 30; stats.DrainRate < want-0.01 || stats.DrainRate > want+0.01 {
+		t.Errorf("DrainRate = %.2f, want %.2f", stats.DrainRate, want)
+	}
+}
+
+func TestLagTrackerBacklogDrainedSince(t *testing.T) {
+	seek := time.Now()
+
+	tests := []struct {
+		name    string
+		samples []backlogSample
+		want    bool
+	}{
+		{"no samples", nil, false},
+		{"empty before seek", []backlogSample{{seek.Add(-time.Second), 0}}, false},
+		{"backlog after seek", []backlogSample{{seek.Add(time.Second), 10}}, false},
+		{"empty at seek", []backlogSample{{seek, 0}}, true},
+		{"empty after seek", []backlogSample{{seek.Add(time.Second), 10}, {seek.Add(2 * time.Second), 0}}, true},
+		{"refilled after empty", []backlogSample{{seek.Add(time.Second), 0}, {seek.Add(2 * time.Second), 5}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := NewLagTracker(time.Minute)
+			for _, s := range tt.samples {
+				tracker.RecordBacklog(s.backlog, s.at)
+			}
+			if got := tracker.BacklogDrainedSince(seek); got != tt.want {
+				t.Errorf("BacklogDrainedSince() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLagStatsDrainTime(t *testing.T) {
+	tests := []struct {
+		name     string
+		stats    LagStats
+		want     time.Duration
+		draining bool
+	}{
+		{"caught up", LagStats{Backlog: 0, DrainRate: -5}, 0, true},
+		{"draining", LagStats{Backlog: 1000, DrainRate: 100}, 10 * time.Second, true},
+		{"steady", LagStats{Backlog: 1000, DrainRate: 0}, 0, false},
+		{"growing", LagStats{Backlog: 1000, DrainRate: -50}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.stats.DrainTime()
+			if got != tt.want || ok != tt.draining {
+				t.Errorf("DrainTime() = %v, %v, want %v, %v", got, ok, tt.want, tt.draining)
+			}
+		})
+	}
+}
+
+func TestLagTrackerLag(t *testing.T) {
+	tracker := NewLagTracker(0)
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 100; i++ {
+		wg.Add(1)
+		go func(ms int) {
+			defer wg.Done()
+			tracker.RecordLag(time.Duration(ms) * time.Millisecond)
+		}(i)
+	}
+	wg.Wait()
+	tracker.RecordLag(5 * time.Millisecond)
+
+	stats := tracker.GetStats()
+	if stats.Lag != 5*time.Millisecond {
+		t.Errorf("Lag = %v, want latest value 5ms", stats.Lag)
+	}
+	if stats.MaxLag != 100*time.Millisecond {
+		t.Errorf("MaxLag = %v, want 100ms", stats.MaxLag)
+	}
+
+	tracker.RecordBacklog(10, time.Now())
+	tracker.Reset()
+	if stats := tracker.GetStats(); stats.HasBacklog || stats.MaxLag != 0 || stats.MaxBacklog != 0 {
+		t.Errorf("stats after Reset = %+v, want zero values", stats)
+	}
+}
@@ -0,0 +1,147 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// PartitionTracker tracks message counts, rates and latency per topic partition.
+// Counters are created lazily the first time a partition is seen.
+type PartitionTracker struct {
+	mu         sync.RWMutex
+	buckets    []float64
+	partitions map[int32]*partitionCounters
+}
+
+// partitionCounters holds the metrics for a single partition
+type partitionCounters struct {
+	sent             atomic.Uint64
+	received         atomic.Uint64
+	bytesSent        atomic.Uint64
+	bytesReceived    atomic.Uint64
+	sendLatencies    *BucketHistogram
+	receiveLatencies *BucketHistogram
+	throughput       *ThroughputTracker
+}
+
+// PartitionStats represents the metrics of a single partition
+type PartitionStats struct {
+	Partition        int32
+	MessagesSent     uint64
+	MessagesReceived uint64
+	BytesSent        uint64
+	BytesReceived    uint64
+	SendRate         float64 // messages per second over the throughput window
+	ReceiveRate      float64 // messages per second over the throughput window
+	SendLatency      LatencyStats
+	ReceiveLatency   LatencyStats // publish-to-receive latency
+}
+
+// NewPartitionTracker creates a new partition tracker using the given latency histogram buckets
+func NewPartitionTracker(histogramBuckets []float64) *PartitionTracker {
+	return &PartitionTracker{
+		buckets:    histogramBuckets,
+		partitions: make(map[int32]*partitionCounters),
+	}
+}
+
+// counters returns the counters for a partition, creating them if needed
+func (pt *PartitionTracker) counters(partition int32) *partitionCounters {
+	pt.mu.RLock()
+	pc, ok := pt.partitions[partition]
+	pt.mu.RUnlock()
+	if ok {
+		return pc
+	}
+
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	if pc, ok = pt.partitions[partition]; !ok {
+		pc = &partitionCounters{
+			sendLatencies:    NewBucketHistogram(pt.buckets),
+			receiveLatencies: NewBucketHistogram(pt.buckets),
+			throughput:       NewThroughputTracker(),
+		}
+		pt.partitions[partition] = pc
+	}
+	return pc
+}
+
+// RecordSend records a message sent to a partition along with its send latency
+func (pt *PartitionTracker) RecordSend(partition int32, bytes int, latency time.Duration) {
+	pc := pt.counters(partition)
+	pc.sent.Add(1)
+	pc.bytesSent.Add(uint64(bytes))
+	pc.sendLatencies.Observe(float64(latency.Milliseconds()))
+	pc.throughput.RecordSend(bytes)
+}
+
+// RecordReceive records a message received from a partition along with its end-to-end latency
+func (pt *PartitionTracker) RecordReceive(partition int32, bytes int, latency time.Duration) {
+	pc := pt.counters(partition)
+	pc.received.Add(1)
+	pc.bytesReceived.Add(uint64(bytes))
+	pc.receiveLatencies.Observe(float64(latency.Milliseconds()))
+	pc.throughput.RecordReceive(bytes)
+}
+
+// GetStats returns per-partition statistics ordered by partition index
+func (pt *PartitionTracker) GetStats() []PartitionStats {
+	pt.mu.RLock()
+	defer pt.mu.RUnlock()
+
+	if len(pt.partitions) == 0 {
+		return nil
+	}
+
+	stats := make([]PartitionStats, 0, len(pt.partitions))
+	for partition, pc := range pt.partitions {
+		throughput := pc.throughput.GetStats()
+		stats = append(stats, PartitionStats{
+			Partition:        partition,
+			MessagesSent:     pc.sent.Load(),
+			MessagesReceived: pc.received.Load(),
+			BytesSent:        pc.bytesSent.Load(),
+			BytesReceived:    pc.bytesReceived.Load(),
+			SendRate:         throughput.SendRate,
+			ReceiveRate:      throughput.ReceiveRate,
+			SendLatency:      pc.sendLatencies.GetStats(),
+			ReceiveLatency:   pc.receiveLatencies.GetStats(),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Partition < stats[j].Partition
+	})
+	return stats
+}
+
+// Reset clears all partition counters
+func (pt *PartitionTracker) Reset() {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+	pt.partitions = make(map[int32]*partitionCounters)
+}
+
+// PartitionSkew returns the ratio of the busiest partition's count to the mean count.
+// A value of 1.0 means messages are spread evenly; 0 is returned when there is no data.
+func PartitionSkew(counts []uint64) float64 {
+	if len(counts) == 0 {
+		return 0
+	}
+
+	var total, maxCount uint64
+	for _, c := range counts {
+		total += c
+		if c > maxCount {
+			maxCount = c
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+
+	mean := float64(total) / float64(len(counts))
+	return float64(maxCount) / mean
+}
@@ -0,0 +1,471 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ReportOptions selects what a report covers and carries the settings it records
+type ReportOptions struct {
+	Tool    string // producer, consumer or e2e; also prefixes the file name
+	Send    bool   // report the producer side
+	Receive bool   // report the consumer side
+
+	RunID                   string
+	Topic                   string
+	Mode                    string // consumer mode, recorded for reader and backlog runs
+	ReaderStart             string
+	SeekTo                  string
+	GroupByProperty         string
+	CompressionType         string
+	PayloadCompressionRatio float64
+	Prefill                 *PrefillReport
+}
+
+// Report is the final metrics report exported by the tools. Sections that do not apply to a
+// run are left out.
+type Report struct {
+	Timestamp       string             `json:"timestamp"`
+	Tool            string             `json:"tool"`
+	RunID           string             `json:"run_id,omitempty"`
+	Topic           string             `json:"topic,omitempty"`
+	Duration        string             `json:"duration"`
+	Mode            string             `json:"mode,omitempty"`
+	ReaderStart     string             `json:"reader_start,omitempty"`
+	SeekTo          string             `json:"seek_to,omitempty"`
+	Prefill         *PrefillReport     `json:"prefill,omitempty"`
+	Send            *TrafficReport     `json:"send,omitempty"`
+	Receive         *TrafficReport     `json:"receive,omitempty"`
+	MessagesMissing *uint64            `json:"messages_missing,omitempty"` // sent but not received (e2e)
+	Compression     *CompressionReport `json:"compression,omitempty"`
+	Chunked         *ChunkedReport     `json:"chunked,omitempty"`
+	Lag             *LagReport         `json:"lag,omitempty"`
+	CatchUp         *CatchUpReport     `json:"catch_up,omitempty"`
+	PartitionSkew   float64            `json:"partition_skew,omitempty"`
+	Partitions      []PartitionReport  `json:"partitions,omitempty"`
+	Topics          []TopicReport      `json:"topics,omitempty"`
+	GroupByProperty string             `json:"group_by_property,omitempty"`
+	Groups          []GroupReport      `json:"groups,omitempty"`
+	Checksums       *ChecksumReport    `json:"checksums,omitempty"`
+	Broker          *BrokerReport      `json:"broker,omitempty"`
+	MessageSizes    *SizeReport        `json:"message_sizes,omitempty"`
+	Errors          uint64             `json:"errors"`
+}
+
+// TrafficReport summarizes one direction of traffic. Latency is send latency for the send
+// side and publish-to-receive latency for the receive side.
+type TrafficReport struct {
+	Messages       uint64         `json:"messages"`
+	Acked          uint64         `json:"acked,omitempty"`
+	Bytes          uint64         `json:"bytes"`
+	Rate           float64        `json:"rate"`
+	ThroughputMbps float64        `json:"throughput_mbps,omitempty"`
+	Latency        *LatencyReport `json:"latency,omitempty"`
+}
+
+// LatencyReport holds latency percentiles in milliseconds
+type LatencyReport struct {
+	P50 float64 `json:"p50"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+	Max float64 `json:"max"`
+}
+
+// PrefillReport summarizes the backlog produced before a backlog run
+type PrefillReport struct {
+	Messages uint64  `json:"messages"`
+	Bytes    uint64  `json:"bytes"`
+	Duration string  `json:"duration"`
+	Rate     float64 `json:"rate"`
+}
+
+// CompressionReport records the compression type and the achieved ratios
+type CompressionReport struct {
+	Type         string  `json:"type"`
+	PayloadRatio float64 `json:"payload_ratio,omitempty"`
+	WireRatio    float64 `json:"wire_ratio"`
+}
+
+// ChunkedReport summarizes chunked messages; receive latency is chunked end-to-end latency
+type ChunkedReport struct {
+	Send    *TrafficReport `json:"send,omitempty"`
+	Receive *TrafficReport `json:"receive,omitempty"`
+}
+
+// LagReport records the subscription backlog and consumer lag
+type LagReport struct {
+	Backlog      *int64  `json:"backlog,omitempty"`
+	MaxBacklog   *int64  `json:"max_backlog,omitempty"`
+	MaxBacklogAt string  `json:"max_backlog_at,omitempty"`
+	DrainRate    float64 `json:"drain_rate,omitempty"`
+	LagMs        int64   `json:"lag_ms,omitempty"`
+	MaxLagMs     int64   `json:"max_lag_ms,omitempty"`
+}
+
+// CatchUpReport summarizes the catch-up of readers, or of consumers after a seek
+type CatchUpReport struct {
+	Readers       int           `json:"readers"`
+	CaughtUp      int           `json:"caught_up"`
+	Messages      uint64        `json:"messages"`
+	Bytes         uint64        `json:"bytes"`
+	Duration      string        `json:"duration"`
+	Rate          float64       `json:"rate"`
+	BandwidthMbps float64       `json:"bandwidth_mbps"`
+	ReadWait      LatencyReport `json:"read_wait"`
+}
+
+// PartitionReport holds the traffic of one partition
+type PartitionReport struct {
+	Partition int32          `json:"partition"`
+	Send      *TrafficReport `json:"send,omitempty"`
+	Receive   *TrafficReport `json:"receive,omitempty"`
+}
+
+// TopicReport holds the traffic of one topic of a multi-topic workload
+type TopicReport struct {
+	Topic   string         `json:"topic"`
+	Send    *TrafficReport `json:"send,omitempty"`
+	Receive *TrafficReport `json:"receive,omitempty"`
+}
+
+// GroupReport holds the received traffic of one grouping property value
+type GroupReport struct {
+	Value   string        `json:"value"`
+	Receive TrafficReport `json:"receive"`
+}
+
+// ChecksumReport summarizes payload checksum verification; times are in microseconds
+type ChecksumReport struct {
+	Verified          uint64   `json:"verified"`
+	Corrupt           uint64   `json:"corrupt"`
+	VerifyUsMean      float64  `json:"verify_us_mean"`
+	VerifyUsP99       float64  `json:"verify_us_p99"`
+	VerifyUsMax       float64  `json:"verify_us_max"`
+	CorruptMessageIDs []string `json:"corrupt_message_ids"`
+}
+
+// BrokerReport holds the last broker stats poll
+type BrokerReport struct {
+	UpdatedAt     string               `json:"updated_at"`
+	Topics        int                  `json:"topics"`
+	MsgRateIn     float64              `json:"msg_rate_in"`
+	MsgRateOut    float64              `json:"msg_rate_out"`
+	ThroughputIn  float64              `json:"throughput_in"`
+	ThroughputOut float64              `json:"throughput_out"`
+	StorageSize   int64                `json:"storage_size"`
+	BacklogSize   int64                `json:"backlog_size"`
+	Publishers    int                  `json:"publishers"`
+	Subscriptions []SubscriptionReport `json:"subscriptions"`
+}
+
+// SubscriptionReport holds the broker stats of one subscription
+type SubscriptionReport struct {
+	Name      string  `json:"name"`
+	Type      string  `json:"type"`
+	Backlog   int64   `json:"backlog"`
+	Unacked   int64   `json:"unacked"`
+	RateOut   float64 `json:"rate_out"`
+	Consumers int     `json:"consumers"`
+}
+
+// SizeReport summarizes the payload sizes of sent messages in bytes
+type SizeReport struct {
+	Min     float64        `json:"min"`
+	Mean    float64        `json:"mean"`
+	P50     float64        `json:"p50"`
+	P95     float64        `json:"p95"`
+	P99     float64        `json:"p99"`
+	Max     float64        `json:"max"`
+	Buckets []BucketReport `json:"buckets"`
+}
+
+// BucketReport holds the count of one histogram bucket
+type BucketReport struct {
+	LE    UpperBound `json:"le"`
+	Count uint64     `json:"count"`
+}
+
+// UpperBound is a bucket bound that is written as "+Inf" for the overflow bucket
+type UpperBound float64
+
+// MarshalJSON writes infinite bounds as the string "+Inf", which JSON numbers cannot hold
+func (b UpperBound) MarshalJSON() ([]byte, error) {
+	if math.IsInf(float64(b), 1) {
+		return []byte(`"+Inf"`), nil
+	}
+	return json.Marshal(float64(b))
+}
+
+// UnmarshalJSON reads bounds written by MarshalJSON
+func (b *UpperBound) UnmarshalJSON(data []byte) error {
+	if string(data) == `"+Inf"` {
+		*b = UpperBound(math.Inf(1))
+		return nil
+	}
+	return json.Unmarshal(data, (*float64)(b))
+}
+
+// NewReport builds the report of a snapshot
+func NewReport(s Snapshot, opts ReportOptions) Report {
+	r := Report{
+		Timestamp:   time.Now().Format(time.RFC3339),
+		Tool:        opts.Tool,
+		RunID:       opts.RunID,
+		Duration:    s.Elapsed.String(),
+		Mode:        opts.Mode,
+		ReaderStart: opts.ReaderStart,
+		SeekTo:      opts.SeekTo,
+		Prefill:     opts.Prefill,
+		Errors:      s.MessagesFailed,
+	}
+	if opts.RunID != "" {
+		r.Topic = opts.Topic
+	}
+
+	if opts.Send {
+		r.Send = &TrafficReport{
+			Messages:       s.MessagesSent,
+			Bytes:          s.BytesSent,
+			Rate:           s.Throughput.SendRate,
+			ThroughputMbps: mbps(float64(s.BytesSent), s.Elapsed),
+			Latency:        newLatencyReport(s.LatencyStats),
+		}
+		r.Compression = &CompressionReport{
+			Type:         opts.CompressionType,
+			PayloadRatio: opts.PayloadCompressionRatio,
+			WireRatio:    s.WireCompressionRatio(),
+		}
+		if sizes := s.MessageSizes; sizes.Count > 0 {
+			r.MessageSizes = newSizeReport(sizes)
+		}
+	}
+	if opts.Receive {
+		r.Receive = &TrafficReport{
+			Messages:       s.MessagesReceived,
+			Acked:          s.MessagesAcked,
+			Bytes:          s.BytesReceived,
+			Rate:           s.Throughput.ReceiveRate,
+			ThroughputMbps: mbps(float64(s.BytesReceived), s.Elapsed),
+			Latency:        newLatencyReport(s.E2ELatency),
+		}
+		r.Lag = newLagReport(s.Lag)
+		if catchUp := s.CatchUp; catchUp.Readers > 0 {
+			r.CatchUp = &CatchUpReport{
+				Readers:       catchUp.Readers,
+				CaughtUp:      catchUp.CaughtUp,
+				Messages:      catchUp.Messages,
+				Bytes:         catchUp.Bytes,
+				Duration:      catchUp.Duration.String(),
+				Rate:          catchUp.Rate,
+				BandwidthMbps: catchUp.Bandwidth / 1024 / 1024 * 8,
+				ReadWait:      *newLatencyReport(catchUp.ReadWait),
+			}
+		}
+		if checksums := s.Checksums; checksums.Verified > 0 {
+			r.Checksums = &ChecksumReport{
+				Verified:          checksums.Verified,
+				Corrupt:           checksums.Corrupt,
+				VerifyUsMean:      checksums.VerifyTime.Mean,
+				VerifyUsP99:       checksums.VerifyTime.P99,
+				VerifyUsMax:       checksums.VerifyTime.Max,
+				CorruptMessageIDs: append([]string{}, checksums.CorruptIDs...),
+			}
+		}
+		if len(s.Groups) > 0 {
+			r.GroupByProperty = opts.GroupByProperty
+			for _, g := range s.Groups {
+				r.Groups = append(r.Groups, GroupReport{
+					Value: g.Value,
+					Receive: TrafficReport{
+						Messages: g.MessagesReceived,
+						Bytes:    g.BytesReceived,
+						Rate:     g.ReceiveRate,
+						Latency:  newLatencyReport(g.Latency),
+					},
+				})
+			}
+		}
+	}
+	if opts.Send && opts.Receive {
+		var missing uint64
+		if s.MessagesSent > s.MessagesReceived {
+			missing = s.MessagesSent - s.MessagesReceived
+		}
+		r.MessagesMissing = &missing
+	}
+
+	r.Chunked = newChunkedReport(s, opts)
+	if len(s.Partitions) > 0 {
+		r.PartitionSkew = s.ReceiveSkew()
+		if opts.Send {
+			r.PartitionSkew = s.SendSkew()
+		}
+		for _, p := range s.Partitions {
+			row := PartitionReport{Partition: p.Partition}
+			row.Send, row.Receive = directions(opts,
+				TrafficReport{Messages: p.MessagesSent, Bytes: p.BytesSent, Rate: p.SendRate, Latency: newLatencyReport(p.SendLatency)},
+				TrafficReport{Messages: p.MessagesReceived, Bytes: p.BytesReceived, Rate: p.ReceiveRate, Latency: newLatencyReport(p.ReceiveLatency)})
+			r.Partitions = append(r.Partitions, row)
+		}
+	}
+	for _, t := range s.Topics {
+		row := TopicReport{Topic: t.Topic}
+		row.Send, row.Receive = directions(opts,
+			TrafficReport{Messages: t.MessagesSent, Bytes: t.BytesSent, Rate: t.SendRate, Latency: newLatencyReport(t.SendLatency)},
+			TrafficReport{Messages: t.MessagesReceived, Bytes: t.BytesReceived, Rate: t.ReceiveRate, Latency: newLatencyReport(t.ReceiveLatency)})
+		r.Topics = append(r.Topics, row)
+	}
+	if broker := s.Broker; broker != nil && !broker.UpdatedAt.IsZero() {
+		r.Broker = newBrokerReport(broker)
+	}
+
+	return r
+}
+
+// WriteReport writes the report as JSON to <tool>-metrics-<timestamp>.json in exportPath and
+// returns the file name
+func WriteReport(exportPath string, report Report) (string, error) {
+	if err := os.MkdirAll(exportPath, 0755); err != nil {
+		return "", fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal metrics report: %w", err)
+	}
+
+	timestamp := time.Now().Format("20060102-150405")
+	filename := filepath.Join(exportPath, fmt.Sprintf("%s-metrics-%s.json", report.Tool, timestamp))
+	if err := os.WriteFile(filename, append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("failed to write metrics file: %w", err)
+	}
+
+	return filename, nil
+}
+
+// directions returns the send and receive traffic selected by the options
+func directions(opts ReportOptions, send, receive TrafficReport) (*TrafficReport, *TrafficReport) {
+	var s, r *TrafficReport
+	if opts.Send {
+		s = &send
+	}
+	if opts.Receive {
+		r = &receive
+	}
+	return s, r
+}
+
+// newLatencyReport converts latency stats, returning nil when nothing was observed
+func newLatencyReport(stats LatencyStats) *LatencyReport {
+	if stats.Count == 0 {
+		return nil
+	}
+	return &LatencyReport{P50: stats.P50, P95: stats.P95, P99: stats.P99, Max: stats.Max}
+}
+
+// newChunkedReport summarizes chunked traffic, or returns nil when no message was chunked
+func newChunkedReport(s Snapshot, opts ReportOptions) *ChunkedReport {
+	var report ChunkedReport
+	if opts.Send && s.Chunked.MessagesSent > 0 {
+		report.Send = &TrafficReport{
+			Messages: s.Chunked.MessagesSent,
+			Bytes:    s.Chunked.BytesSent,
+			Rate:     rate(s.Chunked.MessagesSent, s.Elapsed),
+		}
+	}
+	if opts.Receive && s.Chunked.MessagesReceived > 0 {
+		report.Receive = &TrafficReport{
+			Messages: s.Chunked.MessagesReceived,
+			Bytes:    s.Chunked.BytesReceived,
+			Rate:     rate(s.Chunked.MessagesReceived, s.Elapsed),
+			Latency:  newLatencyReport(s.Chunked.E2ELatency),
+		}
+	}
+	if report.Send == nil && report.Receive == nil {
+		return nil
+	}
+	return &report
+}
+
+// newLagReport converts lag stats, returning nil when neither backlog nor lag was recorded
+func newLagReport(lag LagStats) *LagReport {
+	if !lag.HasBacklog && lag.MaxLag <= 0 {
+		return nil
+	}
+	report := &LagReport{
+		LagMs:    lag.Lag.Milliseconds(),
+		MaxLagMs: lag.MaxLag.Milliseconds(),
+	}
+	if lag.HasBacklog {
+		backlog, maxBacklog := lag.Backlog, lag.MaxBacklog
+		report.Backlog = &backlog
+		report.MaxBacklog = &maxBacklog
+		report.MaxBacklogAt = lag.MaxBacklogAt.Format(time.RFC3339)
+		report.DrainRate = lag.DrainRate
+	}
+	return report
+}
+
+// newBrokerReport converts the last broker stats poll
+func newBrokerReport(broker *BrokerStats) *BrokerReport {
+	report := &BrokerReport{
+		UpdatedAt:     broker.UpdatedAt.Format(time.RFC3339),
+		Topics:        broker.Topics,
+		MsgRateIn:     broker.MsgRateIn,
+		MsgRateOut:    broker.MsgRateOut,
+		ThroughputIn:  broker.MsgThroughputIn,
+		ThroughputOut: broker.MsgThroughputOut,
+		StorageSize:   broker.StorageSize,
+		BacklogSize:   broker.BacklogSize,
+		Publishers:    broker.Publishers,
+		Subscriptions: make([]SubscriptionReport, 0, len(broker.Subscriptions)),
+	}
+	for _, sub := range broker.Subscriptions {
+		report.Subscriptions = append(report.Subscriptions, SubscriptionReport{
+			Name:      sub.Name,
+			Type:      sub.Type,
+			Backlog:   sub.MsgBacklog,
+			Unacked:   sub.UnackedMessages,
+			RateOut:   sub.MsgRateOut,
+			Consumers: sub.Consumers,
+		})
+	}
+	return report
+}
+
+// newSizeReport converts message size stats
+func newSizeReport(sizes SizeStats) *SizeReport {
+	report := &SizeReport{
+		Min:     sizes.Min,
+		Mean:    sizes.Mean,
+		P50:     sizes.P50,
+		P95:     sizes.P95,
+		P99:     sizes.P99,
+		Max:     sizes.Max,
+		Buckets: make([]BucketReport, 0, len(sizes.Buckets)),
+	}
+	for _, b := range sizes.Buckets {
+		report.Buckets = append(report.Buckets, BucketReport{LE: UpperBound(b.UpperBound), Count: b.Count})
+	}
+	return report
+}
+
+// rate returns messages per second over the elapsed time
+func rate(messages uint64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(messages) / elapsed.Seconds()
+}
+
+// mbps returns the bandwidth in megabits per second of bytes over the elapsed time
+func mbps(bytes float64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return bytes / elapsed.Seconds() / 1024 / 1024 * 8
+}
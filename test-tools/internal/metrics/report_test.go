@@ -0,0 +1,135 @@
+package metrics
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func reportSnapshot() Snapshot {
+	latency := LatencyStats{Count: 10, P50: 2, P95: 4, P99: 5, Max: 6}
+	return Snapshot{
+		MessagesSent:     100,
+		MessagesReceived: 90,
+		BytesSent:        102400,
+		BytesReceived:    92160,
+		MessagesFailed:   3,
+		LatencyStats:     latency,
+		E2ELatency:       latency,
+		MessageSizes: SizeStats{
+			Count:   100,
+			Min:     1024,
+			Max:     1024,
+			Buckets: []BucketCount{{UpperBound: 1024, Count: 100}, {UpperBound: math.Inf(1), Count: 0}},
+		},
+		Partitions: []PartitionStats{{Partition: 0, MessagesSent: 100, MessagesReceived: 90}},
+		Lag:        LagStats{HasBacklog: true, Backlog: 10, MaxBacklog: 20},
+		Elapsed:    10 * time.Second,
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    ReportOptions
+		present []string
+		absent  []string
+	}{
+		{
+			name:    "producer",
+			opts:    ReportOptions{Tool: "producer", Send: true, CompressionType: "LZ4"},
+			present: []string{"send", "compression", "message_sizes", "partitions"},
+			absent:  []string{"receive", "lag", "messages_missing", "run_id"},
+		},
+		{
+			name:    "consumer",
+			opts:    ReportOptions{Tool: "consumer", Receive: true, RunID: "run-1", Topic: "perf-run-1"},
+			present: []string{"receive", "lag", "partitions", "run_id", "topic"},
+			absent:  []string{"send", "compression", "message_sizes", "messages_missing"},
+		},
+		{
+			name:    "e2e",
+			opts:    ReportOptions{Tool: "e2e", Send: true, Receive: true},
+			present: []string{"send", "receive", "lag", "messages_missing", "compression"},
+			absent:  []string{"catch_up", "checksums", "broker"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(NewReport(reportSnapshot(), tt.opts))
+			if err != nil {
+				t.Fatalf("failed to marshal report: %v", err)
+			}
+			var fields map[string]json.RawMessage
+			if err := json.Unmarshal(data, &fields); err != nil {
+				t.Fatalf("failed to unmarshal report: %v", err)
+			}
+
+			for _, key := range append([]string{"timestamp", "tool", "duration", "errors"}, tt.present...) {
+				if _, ok := fields[key]; !ok {
+					t.Errorf("expected key %q in %s", key, data)
+				}
+			}
+			for _, key := range tt.absent {
+				if _, ok := fields[key]; ok {
+					t.Errorf("unexpected key %q in %s", key, data)
+				}
+			}
+		})
+	}
+}
+
+func TestNewReportValues(t *testing.T) {
+	report := NewReport(reportSnapshot(), ReportOptions{Tool: "e2e", Send: true, Receive: true})
+
+	if report.MessagesMissing == nil || *report.MessagesMissing != 10 {
+		t.Errorf("expected 10 missing messages, got %v", report.MessagesMissing)
+	}
+	if report.Errors != 3 {
+		t.Errorf("expected 3 errors, got %d", report.Errors)
+	}
+	if got := report.Send.ThroughputMbps; math.Abs(got-0.078125) > 1e-9 {
+		t.Errorf("expected send throughput 0.078125 Mbps, got %f", got)
+	}
+	if p := report.Partitions[0]; p.Send == nil || p.Receive == nil || p.Receive.Messages != 90 {
+		t.Errorf("expected both directions for partition 0, got %+v", p)
+	}
+}
+
+func TestBucketBoundJSON(t *testing.T) {
+	data, err := json.Marshal([]BucketReport{{LE: 1024, Count: 1}, {LE: UpperBound(math.Inf(1)), Count: 2}})
+	if err != nil {
+		t.Fatalf("failed to marshal buckets: %v", err)
+	}
+	if want := `[{"le":1024,"count":1},{"le":"+Inf","count":2}]`; string(data) != want {
+		t.Errorf("expected %s, got %s", want, data)
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	dir := t.TempDir()
+
+	filename, err := WriteReport(dir, NewReport(reportSnapshot(), ReportOptions{Tool: "producer", Send: true}))
+	if err != nil {
+		t.Fatalf("failed to write report: %v", err)
+	}
+	if !strings.Contains(filename, "producer-metrics-") {
+		t.Errorf("expected producer-metrics file, got %s", filename)
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("failed to read report: %v", err)
+	}
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("report is not valid JSON: %v", err)
+	}
+	if report.Send == nil || report.Send.Messages != 100 {
+		t.Errorf("expected 100 sent messages, got %+v", report.Send)
+	}
+}
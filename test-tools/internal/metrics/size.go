@@ -0,0 +1,54 @@
+package metrics
+
+// DefaultSizeBuckets are the message size histogram bucket boundaries in bytes:
+// powers of two from 64 B to 64 MB
+var DefaultSizeBuckets = func() []float64 {
+	buckets := make([]float64, 0, 21)
+	for size := 64; size <= 64<<20; size *= 2 {
+		buckets = append(buckets, float64(size))
+	}
+	return buckets
+}()
+
+// SizeStats summarizes the payload sizes of sent messages in bytes
+type SizeStats struct {
+	Min     float64
+	Max     float64
+	Mean    float64
+	P50     float64
+	P95     float64
+	P99     float64
+	Count   uint64
+	Buckets []BucketCount // non-empty buckets only
+}
+
+// newSizeStats summarizes a histogram of message sizes
+func newSizeStats(h *BucketHistogram) SizeStats {
+	stats := h.GetStats()
+	if stats.Count == 0 {
+		return SizeStats{}
+	}
+
+	var buckets []BucketCount
+	for _, b := range h.Buckets() {
+		if b.Count > 0 {
+			buckets = append(buckets, b)
+		}
+	}
+
+	return SizeStats{
+		Min:     stats.Min,
+		Max:     stats.Max,
+		Mean:    stats.Mean,
+		P50:     stats.P50,
+		P95:     stats.P95,
+		P99:     stats.P99,
+		Count:   stats.Count,
+		Buckets: buckets,
+	}
+}
+
+// Varies reports whether the sent messages had different sizes
+func (s SizeStats) Varies() bool {
+	return s.Count > 0 && s.Min != s.Max
+}
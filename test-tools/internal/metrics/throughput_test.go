@@ -21,9 +21,9 @@ func TestNewThroughputTracker(t *testing.T) {
 func TestThroughputTrackerRecordSend(t *testing.T) {
 	tracker := NewThroughputTracker()
 
-	tracker.RecordSend()
-	tracker.RecordSend()
-	tracker.RecordSend()
+	tracker.RecordSend(1024)
+	tracker.RecordSend(1024)
+	tracker.RecordSend(1024)
 
 	stats := tracker.GetStats()
 
@@ -35,8 +35,8 @@ func TestThroughputTrackerRecordSend(t *testing.T) {
 func TestThroughputTrackerRecordReceive(t *testing.T) {
 	tracker := NewThroughputTracker()
 
-	tracker.RecordReceive()
-	tracker.RecordReceive()
+	tracker.RecordReceive(1024)
+	tracker.RecordReceive(1024)
 
 	stats := tracker.GetStats()
 
@@ -50,12 +50,12 @@ func TestThroughputTrackerStats(t *testing.T) {
 
 	// Record some sends
 	for i := 0; i < 100; i++ {
-		tracker.RecordSend()
+		tracker.RecordSend(1024)
 	}
 
 	// Record some receives
 	for i := 0; i < 50; i++ {
-		tracker.RecordReceive()
+		tracker.RecordReceive(1024)
 	}
 
 	stats := tracker.GetStats()
@@ -83,8 +83,8 @@ func TestThroughputTrackerReset(t *testing.T) {
 
 	// Record some data
 	for i := 0; i < 10; i++ {
-		tracker.RecordSend()
-		tracker.RecordReceive()
+		tracker.RecordSend(1024)
+		tracker.RecordReceive(1024)
 	}
 
 	// Verify data exists
@@ -111,7 +111,7 @@ func TestThroughputTrackerWindow(t *testing.T) {
 
 	// Record events
 	for i := 0; i < 50; i++ {
-		tracker.RecordSend()
+		tracker.RecordSend(1024)
 	}
 
 	// Wait briefly
@@ -119,7 +119,7 @@ func TestThroughputTrackerWindow(t *testing.T) {
 
 	// Record more events
 	for i := 0; i < 50; i++ {
-		tracker.RecordSend()
+		tracker.RecordSend(1024)
 	}
 
 	stats := tracker.GetStats()
@@ -138,7 +138,7 @@ func TestThroughputTrackerOldEventsExcluded(t *testing.T) {
 
 	// Record old events
 	for i := 0; i < 100; i++ {
-		tracker.RecordSend()
+		tracker.RecordSend(1024)
 	}
 
 	// Wait for window to expire
@@ -166,7 +166,7 @@ func TestThroughputTrackerConcurrentAccess(t *testing.T) {
 		go func() {
 			defer wg.Done()
 			for j := 0; j < operationsPerGoroutine; j++ {
-				tracker.RecordSend()
+				tracker.RecordSend(1024)
 			}
 		}()
 	}
@@ -176,7 +176,7 @@ func TestThroughputTrackerConcurrentAccess(t *testing.T) {
 		go func() {
 			defer wg.Done()
 			for j := 0; j < operationsPerGoroutine; j++ {
-				tracker.RecordReceive()
+				tracker.RecordReceive(1024)
 			}
 		}()
 	}
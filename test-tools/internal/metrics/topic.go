@@ -0,0 +1,131 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TopicTracker tracks message counts, rates and latency per topic of multi-topic workloads.
+// Counters are created lazily the first time a topic is seen, so topics discovered by a
+// consumer topic pattern show up as they start delivering messages.
+type TopicTracker struct {
+	mu      sync.RWMutex
+	buckets []float64
+	topics  map[string]*topicCounters
+}
+
+// topicCounters holds the metrics for a single topic
+type topicCounters struct {
+	sent             atomic.Uint64
+	received         atomic.Uint64
+	bytesSent        atomic.Uint64
+	bytesReceived    atomic.Uint64
+	sendLatencies    *BucketHistogram
+	receiveLatencies *BucketHistogram
+	throughput       *ThroughputTracker
+}
+
+// TopicStats represents the metrics of a single topic
+type TopicStats struct {
+	Topic            string
+	MessagesSent     uint64
+	MessagesReceived uint64
+	BytesSent        uint64
+	BytesReceived    uint64
+	SendRate         float64 // messages per second over the throughput window
+	ReceiveRate      float64 // messages per second over the throughput window
+	SendLatency      LatencyStats
+	ReceiveLatency   LatencyStats // publish-to-receive latency
+}
+
+// NewTopicTracker creates a new topic tracker using the given latency histogram buckets
+func NewTopicTracker(histogramBuckets []float64) *TopicTracker {
+	return &TopicTracker{
+		buckets: histogramBuckets,
+		topics:  make(map[string]*topicCounters),
+	}
+}
+
+// counters returns the counters for a topic, creating them if needed
+func (tt *TopicTracker) counters(topic string) *topicCounters {
+	tt.mu.RLock()
+	tc, ok := tt.topics[topic]
+	tt.mu.RUnlock()
+	if ok {
+		return tc
+	}
+
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	if tc, ok = tt.topics[topic]; !ok {
+		tc = &topicCounters{
+			sendLatencies:    NewBucketHistogram(tt.buckets),
+			receiveLatencies: NewBucketHistogram(tt.buckets),
+			throughput:       NewThroughputTracker(),
+		}
+		tt.topics[topic] = tc
+	}
+	return tc
+}
+
+// RecordSend records a message sent to a topic along with its send latency
+func (tt *TopicTracker) RecordSend(topic string, bytes int, latency time.Duration) {
+	tc := tt.counters(topic)
+	tc.sent.Add(1)
+	tc.bytesSent.Add(uint64(bytes))
+	tc.sendLatencies.Observe(float64(latency.Milliseconds()))
+	tc.throughput.RecordSend(bytes)
+}
+
+// RecordReceive records a message received from a topic along with its end-to-end latency
+func (tt *TopicTracker) RecordReceive(topic string, bytes int, latency time.Duration) {
+	tc := tt.counters(topic)
+	tc.received.Add(1)
+	tc.bytesReceived.Add(uint64(bytes))
+	tc.receiveLatencies.Observe(float64(latency) / float64(time.Millisecond))
+	tc.throughput.RecordReceive(bytes)
+}
+
+// GetStats returns per-topic statistics, busiest first
+func (tt *TopicTracker) GetStats() []TopicStats {
+	tt.mu.RLock()
+	defer tt.mu.RUnlock()
+
+	if len(tt.topics) == 0 {
+		return nil
+	}
+
+	stats := make([]TopicStats, 0, len(tt.topics))
+	for topic, tc := range tt.topics {
+		throughput := tc.throughput.GetStats()
+		stats = append(stats, TopicStats{
+			Topic:            topic,
+			MessagesSent:     tc.sent.Load(),
+			MessagesReceived: tc.received.Load(),
+			BytesSent:        tc.bytesSent.Load(),
+			BytesReceived:    tc.bytesReceived.Load(),
+			SendRate:         throughput.SendRate,
+			ReceiveRate:      throughput.ReceiveRate,
+			SendLatency:      tc.sendLatencies.GetStats(),
+			ReceiveLatency:   tc.receiveLatencies.GetStats(),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		ci := stats[i].MessagesSent + stats[i].MessagesReceived
+		cj := stats[j].MessagesSent + stats[j].MessagesReceived
+		if ci != cj {
+			return ci > cj
+		}
+		return stats[i].Topic < stats[j].Topic
+	})
+	return stats
+}
+
+// Reset clears all topic counters
+func (tt *TopicTracker) Reset() {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	tt.topics = make(map[string]*topicCounters)
+}
@@ -1,88 +1,394 @@
 package pulsar
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/pulsar-local-lab/perf-test/internal/config"
 	pulsaradmin "github.com/streamnative/pulsar-admin-go"
+	"github.com/streamnative/pulsar-admin-go/pkg/rest"
 	"github.com/streamnative/pulsar-admin-go/pkg/utils"
 )
 
-// EnsureTopic ensures that the specified topic exists with the correct partition configuration.
-// If the topic doesn't exist, it creates it with the specified number of partitions.
-// If the topic exists, it verifies the partition count matches the configuration.
-func EnsureTopic(cfg *config.Config) error {
-	// Create admin client
-	adminCfg := &pulsaradmin.Config{
-		WebServiceURL: cfg.Pulsar.AdminURL,
+// AdminClient wraps the Pulsar admin REST client with the topic lifecycle operations
+// used by the performance tools (existence checks, provisioning, resizing, deletion).
+type AdminClient struct {
+	admin pulsaradmin.Client
+}
+
+// NewAdminClient creates an admin client for the given web service URL (e.g. http://localhost:8080).
+func NewAdminClient(adminURL string) (*AdminClient, error) {
+	if adminURL == "" {
+		return nil, fmt.Errorf("admin URL cannot be empty")
 	}
 
-	admin, err := pulsaradmin.NewClient(adminCfg)
+	admin, err := pulsaradmin.NewClient(&pulsaradmin.Config{
+		WebServiceURL: adminURL,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to create admin client: %w", err)
+		return nil, fmt.Errorf("failed to create admin client: %w", err)
 	}
 
-	// Parse topic name
-	topicName, err := utils.GetTopicName(cfg.Pulsar.Topic)
+	return &AdminClient{admin: admin}, nil
+}
+
+// IsNotFound reports whether an admin API error means the requested resource does not exist.
+// Any other error (auth failure, connection refused, server error) returns false.
+func IsNotFound(err error) bool {
+	return hasStatusCode(err, http.StatusNotFound)
+}
+
+// isConflict reports whether an admin API error means the resource already exists
+func isConflict(err error) bool {
+	return hasStatusCode(err, http.StatusConflict)
+}
+
+// hasStatusCode reports whether err is an admin API error with the given HTTP status code
+func hasStatusCode(err error, code int) bool {
+	var adminErr rest.Error
+	return errors.As(err, &adminErr) && adminErr.Code == code
+}
+
+// TopicInfo describes the state of a topic on the broker
+type TopicInfo struct {
+	Exists     bool
+	Partitions int // 0 for non-partitioned topics
+}
+
+// DescribeTopic looks up whether a topic exists and how many partitions it has.
+// A missing topic (or missing tenant/namespace) is reported as Exists=false with a nil error;
+// every other admin failure is returned as an error.
+func (ac *AdminClient) DescribeTopic(topicName *utils.TopicName) (TopicInfo, error) {
+	// The partitions endpoint reports 0 for both non-partitioned and missing topics
+	metadata, err := ac.admin.Topics().GetMetadata(*topicName)
 	if err != nil {
-		return fmt.Errorf("invalid topic name %s: %w", cfg.Pulsar.Topic, err)
+		if IsNotFound(err) {
+			return TopicInfo{}, nil
+		}
+		return TopicInfo{}, fmt.Errorf("failed to get topic metadata: %w", err)
+	}
+	if metadata.Partitions > 0 {
+		return TopicInfo{Exists: true, Partitions: metadata.Partitions}, nil
 	}
 
-	// Check if topic exists
-	exists, err := topicExists(admin, topicName)
+	// Stats are only served for topics that exist, which tells the two cases apart
+	if _, err := ac.admin.Topics().GetStats(*topicName); err != nil {
+		if IsNotFound(err) {
+			return TopicInfo{}, nil
+		}
+		return TopicInfo{}, fmt.Errorf("failed to get topic stats: %w", err)
+	}
+	return TopicInfo{Exists: true}, nil
+}
+
+// EnsureNamespace creates the topic's tenant and namespace if they do not exist.
+// New tenants are allowed on every cluster known to the broker.
+func (ac *AdminClient) EnsureNamespace(topicName *utils.TopicName) error {
+	tenant := topicName.GetTenant()
+	namespace := fmt.Sprintf("%s/%s", tenant, topicName.GetNamespace())
+
+	if _, err := ac.admin.Tenants().Get(tenant); err != nil {
+		if !IsNotFound(err) {
+			return fmt.Errorf("failed to get tenant %s: %w", tenant, err)
+		}
+
+		clusters, err := ac.admin.Clusters().List()
+		if err != nil {
+			return fmt.Errorf("failed to list clusters: %w", err)
+		}
+
+		log.Printf("Creating tenant %s", tenant)
+		err = ac.admin.Tenants().Create(utils.TenantData{
+			Name:            tenant,
+			AdminRoles:      []string{},
+			AllowedClusters: clusters,
+		})
+		if err != nil && !isConflict(err) {
+			return fmt.Errorf("failed to create tenant %s: %w", tenant, err)
+		}
+	}
+
+	namespaces, err := ac.admin.Namespaces().GetNamespaces(tenant)
 	if err != nil {
-		return fmt.Errorf("failed to check topic existence: %w", err)
+		return fmt.Errorf("failed to list namespaces of tenant %s: %w", tenant, err)
+	}
+	for _, ns := range namespaces {
+		if ns == namespace {
+			return nil
+		}
 	}
 
-	if exists {
-		// Topic exists - verify partition count
-		if cfg.Pulsar.TopicPartitions > 0 {
-			// Check if it's a partitioned topic
-			metadata, err := admin.Topics().GetMetadata(*topicName)
-			if err != nil {
-				// If we can't get metadata, assume it's compatible
-				log.Printf("Warning: could not verify topic partition count: %v", err)
-				return nil
-			}
+	log.Printf("Creating namespace %s", namespace)
+	if err := ac.admin.Namespaces().CreateNamespace(namespace); err != nil && !isConflict(err) {
+		return fmt.Errorf("failed to create namespace %s: %w", namespace, err)
+	}
+	return nil
+}
+
+// CreateTopic creates a topic with the given number of partitions.
+// Zero partitions creates a non-partitioned topic explicitly instead of relying on auto-creation.
+func (ac *AdminClient) CreateTopic(topicName *utils.TopicName, partitions int) error {
+	if err := ac.admin.Topics().Create(*topicName, partitions); err != nil {
+		if partitions > 0 {
+			return fmt.Errorf("failed to create partitioned topic: %w", err)
+		}
+		return fmt.Errorf("failed to create non-partitioned topic: %w", err)
+	}
+	return nil
+}
+
+// UpdatePartitions increases the partition count of a partitioned topic.
+// Pulsar does not support reducing the number of partitions.
+func (ac *AdminClient) UpdatePartitions(topicName *utils.TopicName, partitions int) error {
+	if err := ac.admin.Topics().Update(*topicName, partitions); err != nil {
+		return fmt.Errorf("failed to update topic partitions: %w", err)
+	}
+	return nil
+}
+
+// DeleteTopic force-deletes a topic along with its subscriptions and backlog.
+func (ac *AdminClient) DeleteTopic(topicName *utils.TopicName, partitioned bool) error {
+	if err := ac.admin.Topics().Delete(*topicName, true, !partitioned); err != nil {
+		return fmt.Errorf("failed to delete topic: %w", err)
+	}
+	return nil
+}
+
+// DeleteSubscription force-deletes a subscription along with its backlog.
+// A missing topic or subscription is not treated as an error.
+func (ac *AdminClient) DeleteSubscription(topicName *utils.TopicName, subscription string) error {
+	if err := ac.admin.Subscriptions().ForceDelete(*topicName, subscription); err != nil && !IsNotFound(err) {
+		return fmt.Errorf("failed to delete subscription %s: %w", subscription, err)
+	}
+	return nil
+}
+
+// ListTopics returns the partitioned and non-partitioned topics of a namespace (e.g. public/default).
+// The individual partitions of partitioned topics are omitted from the non-partitioned list.
+func (ac *AdminClient) ListTopics(namespace string) ([]string, []string, error) {
+	ns, err := utils.GetNamespaceName(namespace)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid namespace %s: %w", namespace, err)
+	}
+
+	partitioned, all, err := ac.admin.Topics().List(*ns)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list topics of namespace %s: %w", namespace, err)
+	}
+
+	nonPartitioned := make([]string, 0, len(all))
+	for _, topic := range all {
+		if TopicPartition(topic) < 0 {
+			nonPartitioned = append(nonPartitioned, topic)
+		}
+	}
+	return partitioned, nonPartitioned, nil
+}
+
+// RunTopic describes a topic created for an isolated run (see config.ApplyRunID)
+type RunTopic struct {
+	Topic       string
+	Partitioned bool
+	Started     time.Time
+}
+
+// FindRunTopics returns the run topics in a namespace that were started more than olderThan ago.
+// Only topics with a generated run ID suffix are considered; everything else is left alone.
+func (ac *AdminClient) FindRunTopics(namespace string, olderThan time.Duration) ([]RunTopic, error) {
+	partitioned, nonPartitioned, err := ac.ListTopics(namespace)
+	if err != nil {
+		return nil, err
+	}
 
-			if metadata.Partitions != cfg.Pulsar.TopicPartitions {
-				return fmt.Errorf("topic exists with %d partitions, but config specifies %d partitions. Delete the topic or change the partition count",
-					metadata.Partitions, cfg.Pulsar.TopicPartitions)
+	cutoff := time.Now().Add(-olderThan)
+	var runs []RunTopic
+	collect := func(topics []string, isPartitioned bool) {
+		for _, topic := range topics {
+			started, ok := config.ParseRunTime(topic)
+			if !ok || started.After(cutoff) {
+				continue
 			}
-			log.Printf("Topic %s exists with %d partitions", cfg.Pulsar.Topic, cfg.Pulsar.TopicPartitions)
-		} else {
-			log.Printf("Topic %s exists (non-partitioned)", cfg.Pulsar.Topic)
+			runs = append(runs, RunTopic{Topic: topic, Partitioned: isPartitioned, Started: started})
 		}
+	}
+	collect(partitioned, true)
+	collect(nonPartitioned, false)
+
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].Started.Before(runs[j].Started)
+	})
+	return runs, nil
+}
+
+// CleanupRun removes the resources of an isolated run. With deleteTopic the run topics are
+// force-deleted together with all subscriptions and backlog; otherwise only the configured
+// subscription is deleted. Runs without a run ID are rejected so shared topics are never removed.
+func CleanupRun(cfg *config.Config, deleteTopic bool) error {
+	if cfg.Pulsar.RunID == "" {
+		return fmt.Errorf("refusing to clean up topic %s: no run ID set", cfg.Pulsar.Topic)
+	}
+
+	admin, err := NewAdminClient(cfg.Pulsar.AdminURL)
+	if err != nil {
+		return err
+	}
+
+	topicNames, err := parseTopicNames(cfg.Pulsar.TopicNames())
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, topicName := range topicNames {
+		errs = append(errs, admin.cleanupTopic(topicName, cfg.Consumer.SubscriptionName, deleteTopic))
+	}
+	return errors.Join(errs...)
+}
+
+// cleanupTopic deletes a run topic, or only the subscription on it
+func (ac *AdminClient) cleanupTopic(topicName *utils.TopicName, subscription string, deleteTopic bool) error {
+	if !deleteTopic {
+		log.Printf("Deleting subscription %s on %s", subscription, topicName)
+		return ac.DeleteSubscription(topicName, subscription)
+	}
+
+	info, err := ac.DescribeTopic(topicName)
+	if err != nil {
+		return err
+	}
+	if !info.Exists {
 		return nil
 	}
+	log.Printf("Deleting run topic %s", topicName)
+	return ac.DeleteTopic(topicName, info.Partitions > 0)
+}
 
-	// Topic doesn't exist - create it
-	if cfg.Pulsar.TopicPartitions > 0 {
-		// Create partitioned topic
-		log.Printf("Creating partitioned topic %s with %d partitions", cfg.Pulsar.Topic, cfg.Pulsar.TopicPartitions)
-		err = admin.Topics().Create(*topicName, cfg.Pulsar.TopicPartitions)
+// parseTopicNames parses topic names, failing on the first invalid one
+func parseTopicNames(topics []string) ([]*utils.TopicName, error) {
+	topicNames := make([]*utils.TopicName, len(topics))
+	for i, topic := range topics {
+		topicName, err := utils.GetTopicName(topic)
 		if err != nil {
-			return fmt.Errorf("failed to create partitioned topic: %w", err)
+			return nil, fmt.Errorf("invalid topic name %s: %w", topic, err)
 		}
-		log.Printf("Successfully created partitioned topic %s", cfg.Pulsar.Topic)
-	} else {
-		// Create non-partitioned topic (will be auto-created by producer/consumer)
-		log.Printf("Topic %s will be auto-created as non-partitioned", cfg.Pulsar.Topic)
+		topicNames[i] = topicName
 	}
+	return topicNames, nil
+}
 
-	return nil
+// maxParallelTopics bounds how many topics EnsureTopic provisions at once
+const maxParallelTopics = 16
+
+// EnsureTopic ensures that the configured topics exist with the correct partition configuration.
+// If a topic doesn't exist, it creates the tenant and namespace if needed and then the topic.
+// If a topic exists with fewer partitions than configured, the partition count is increased.
+// Other mismatches are reported as errors unless Pulsar.RecreateTopic is set, in which case
+// the existing topic is deleted and created again. Multiple topics are provisioned in parallel.
+func EnsureTopic(cfg *config.Config) error {
+	admin, err := NewAdminClient(cfg.Pulsar.AdminURL)
+	if err != nil {
+		return err
+	}
+
+	topicNames, err := parseTopicNames(cfg.Pulsar.TopicNames())
+	if err != nil {
+		return err
+	}
+	if len(topicNames) == 1 {
+		return admin.ensureTopic(topicNames[0], cfg.Pulsar.TopicPartitions, cfg.Pulsar.RecreateTopic)
+	}
+	return admin.ensureTopics(topicNames, cfg.Pulsar.TopicPartitions, cfg.Pulsar.RecreateTopic)
+}
+
+// ensureTopics reconciles several topics in parallel and reports the errors of all topics
+// that failed
+func (ac *AdminClient) ensureTopics(topicNames []*utils.TopicName, partitions int, recreate bool) error {
+	// Create each namespace once up front so parallel topic creation does not race on it
+	namespaces := make(map[string]bool)
+	for _, topicName := range topicNames {
+		namespace := topicName.GetTenant() + "/" + topicName.GetNamespace()
+		if namespaces[namespace] {
+			continue
+		}
+		namespaces[namespace] = true
+		if err := ac.EnsureNamespace(topicName); err != nil {
+			return err
+		}
+	}
+
+	errs := make([]error, len(topicNames))
+	sem := make(chan struct{}, maxParallelTopics)
+	var wg sync.WaitGroup
+	for i, topicName := range topicNames {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[i] = ac.ensureTopic(topicName, partitions, recreate)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
 }
 
-// topicExists checks if a topic exists (either partitioned or non-partitioned)
-func topicExists(admin pulsaradmin.Client, topicName *utils.TopicName) (bool, error) {
-	// Try to get topic metadata - if it succeeds, topic exists
-	_, err := admin.Topics().GetMetadata(*topicName)
+// ensureTopic reconciles a single topic with the requested partition count
+func (ac *AdminClient) ensureTopic(topicName *utils.TopicName, partitions int, recreate bool) error {
+	topic := topicName.String()
+
+	info, err := ac.DescribeTopic(topicName)
 	if err != nil {
-		// Check if error indicates topic doesn't exist
-		// The admin API returns an error for non-existent topics
-		// We'll assume any error means the topic doesn't exist
-		return false, nil
+		return fmt.Errorf("failed to check topic existence: %w", err)
+	}
+
+	if info.Exists && recreate {
+		log.Printf("Deleting topic %s for recreation", topic)
+		if err := ac.DeleteTopic(topicName, info.Partitions > 0); err != nil {
+			return err
+		}
+		info = TopicInfo{}
+	}
+
+	if !info.Exists {
+		if err := ac.EnsureNamespace(topicName); err != nil {
+			return err
+		}
+		if partitions > 0 {
+			log.Printf("Creating partitioned topic %s with %d partitions", topic, partitions)
+		} else {
+			log.Printf("Creating non-partitioned topic %s", topic)
+		}
+		if err := ac.CreateTopic(topicName, partitions); err != nil {
+			return err
+		}
+		log.Printf("Successfully created topic %s", topic)
+		return nil
+	}
+
+	switch {
+	case info.Partitions == partitions:
+		if partitions > 0 {
+			log.Printf("Topic %s exists with %d partitions", topic, partitions)
+		} else {
+			log.Printf("Topic %s exists (non-partitioned)", topic)
+		}
+		return nil
+	case info.Partitions == 0:
+		return fmt.Errorf("topic %s exists as non-partitioned, but config specifies %d partitions. Use --recreate-topic to delete and recreate it",
+			topic, partitions)
+	case partitions == 0:
+		return fmt.Errorf("topic %s exists with %d partitions, but config specifies a non-partitioned topic. Use --recreate-topic to delete and recreate it",
+			topic, info.Partitions)
+	case partitions > info.Partitions:
+		log.Printf("Increasing partitions of topic %s from %d to %d", topic, info.Partitions, partitions)
+		return ac.UpdatePartitions(topicName, partitions)
+	default:
+		return fmt.Errorf("topic %s exists with %d partitions, but config specifies %d partitions. Partitions cannot be reduced; use --recreate-topic to delete and recreate it",
+			topic, info.Partitions, partitions)
 	}
-	return true, nil
 }
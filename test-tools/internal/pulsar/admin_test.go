@@ -0,0 +1,464 @@
+package pulsar
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pulsar-local-lab/perf-test/internal/config"
+	"github.com/streamnative/pulsar-admin-go/pkg/rest"
+	"github.com/streamnative/pulsar-admin-go/pkg/utils"
+)
+
+// fakeAdminServer emulates the subset of the Pulsar admin REST API used by AdminClient
+type fakeAdminServer struct {
+	mu         sync.Mutex
+	tenants    map[string]bool
+	namespaces map[string]bool
+	topics     map[string]int         // topic rest path -> partitions (0 = non-partitioned)
+	stats      map[string]interface{} // topic rest path -> stats response
+	failWith   int                    // when non-zero every request fails with this status
+	requests   []string
+}
+
+func newFakeAdminServer() *fakeAdminServer {
+	return &fakeAdminServer{
+		tenants:    map[string]bool{"public": true},
+		namespaces: map[string]bool{"public/default": true},
+		topics:     make(map[string]int),
+		stats:      make(map[string]interface{}),
+	}
+}
+
+func (f *fakeAdminServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/admin/v2/")
+	f.requests = append(f.requests, r.Method+" "+path)
+
+	if f.failWith != 0 {
+		http.Error(w, `{"reason":"injected failure"}`, f.failWith)
+		return
+	}
+
+	writeJSON := func(v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	notFound := func() {
+		http.Error(w, `{"reason":"not found"}`, http.StatusNotFound)
+	}
+
+	switch {
+	case path == "clusters":
+		writeJSON([]string{"standalone"})
+
+	case strings.HasPrefix(path, "tenants/"):
+		tenant := strings.TrimPrefix(path, "tenants/")
+		if r.Method == http.MethodPut {
+			f.tenants[tenant] = true
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if !f.tenants[tenant] {
+			notFound()
+			return
+		}
+		writeJSON(map[string]interface{}{"adminRoles": []string{}, "allowedClusters": []string{"standalone"}})
+
+	case strings.HasPrefix(path, "namespaces/"):
+		name := strings.TrimPrefix(path, "namespaces/")
+		if r.Method == http.MethodPut {
+			f.namespaces[name] = true
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if !f.tenants[name] {
+			notFound()
+			return
+		}
+		var list []string
+		for ns := range f.namespaces {
+			if strings.HasPrefix(ns, name+"/") {
+				list = append(list, ns)
+			}
+		}
+		writeJSON(list)
+
+	case r.Method == http.MethodGet && (len(strings.Split(path, "/")) == 3 ||
+		strings.HasSuffix(path, "/partitioned") && len(strings.Split(path, "/")) == 4):
+		// Namespace topic listing: <domain>/<tenant>/<namespace>[/partitioned]
+		namespace := strings.TrimSuffix(path, "/partitioned")
+		wantPartitioned := strings.HasSuffix(path, "/partitioned")
+		list := []string{}
+		for topic, partitions := range f.topics {
+			if !strings.HasPrefix(topic, namespace+"/") {
+				continue
+			}
+			name := strings.Replace(topic, "/", "://", 1)
+			if wantPartitioned && partitions > 0 {
+				list = append(list, name)
+			}
+			if !wantPartitioned {
+				if partitions == 0 {
+					list = append(list, name)
+				}
+				for i := 0; i < partitions; i++ {
+					list = append(list, fmt.Sprintf("%s-partition-%d", name, i))
+				}
+			}
+		}
+		writeJSON(list)
+
+	case strings.HasSuffix(path, "/partitions"):
+		topic := strings.TrimSuffix(path, "/partitions")
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(map[string]int{"partitions": f.topics[topic]})
+		case http.MethodPut, http.MethodPost:
+			var partitions int
+			_ = json.NewDecoder(r.Body).Decode(&partitions)
+			f.topics[topic] = partitions
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodDelete:
+			delete(f.topics, topic)
+			w.WriteHeader(http.StatusNoContent)
+		}
+
+	case strings.HasSuffix(path, "/stats"), strings.HasSuffix(path, "/partitioned-stats"):
+		topic := strings.TrimSuffix(strings.TrimSuffix(path, "/stats"), "/partitioned-stats")
+		if _, ok := f.topics[topic]; !ok {
+			notFound()
+			return
+		}
+		if stats, ok := f.stats[topic]; ok {
+			writeJSON(stats)
+			return
+		}
+		writeJSON(map[string]interface{}{})
+
+	default:
+		switch r.Method {
+		case http.MethodPut:
+			f.topics[path] = 0
+		case http.MethodDelete:
+			delete(f.topics, path)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// newTestAdminClient starts a fake admin server and returns a client pointed at it
+func newTestAdminClient(t *testing.T) (*AdminClient, *fakeAdminServer) {
+	t.Helper()
+
+	fake := newFakeAdminServer()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client, err := NewAdminClient(server.URL)
+	if err != nil {
+		t.Fatalf("NewAdminClient() error = %v", err)
+	}
+	return client, fake
+}
+
+func mustTopicName(t *testing.T, topic string) *utils.TopicName {
+	t.Helper()
+	name, err := utils.GetTopicName(topic)
+	if err != nil {
+		t.Fatalf("GetTopicName(%s) error = %v", topic, err)
+	}
+	return name
+}
+
+func TestIsNotFound(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"not found", rest.Error{Code: http.StatusNotFound}, true},
+		{"unauthorized", rest.Error{Code: http.StatusUnauthorized}, false},
+		{"server error", rest.Error{Code: http.StatusInternalServerError}, false},
+		{"plain error", http.ErrServerClosed, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsNotFound(tt.err); got != tt.want {
+				t.Errorf("IsNotFound(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdminClient_DescribeTopic(t *testing.T) {
+	client, fake := newTestAdminClient(t)
+	fake.topics["persistent/public/default/partitioned"] = 4
+	fake.topics["persistent/public/default/plain"] = 0
+
+	tests := []struct {
+		topic string
+		want  TopicInfo
+	}{
+		{"persistent://public/default/partitioned", TopicInfo{Exists: true, Partitions: 4}},
+		{"persistent://public/default/plain", TopicInfo{Exists: true}},
+		{"persistent://public/default/missing", TopicInfo{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.topic, func(t *testing.T) {
+			got, err := client.DescribeTopic(mustTopicName(t, tt.topic))
+			if err != nil {
+				t.Fatalf("DescribeTopic() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("DescribeTopic() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdminClient_DescribeTopicPropagatesErrors(t *testing.T) {
+	client, fake := newTestAdminClient(t)
+	fake.failWith = http.StatusUnauthorized
+
+	_, err := client.DescribeTopic(mustTopicName(t, "persistent://public/default/topic"))
+	if err == nil {
+		t.Fatal("DescribeTopic() should return an error when the admin API rejects the request")
+	}
+	if IsNotFound(err) {
+		t.Error("an authorization failure must not be reported as not found")
+	}
+}
+
+func TestAdminClient_EnsureTopic(t *testing.T) {
+	tests := []struct {
+		name           string
+		existing       map[string]int
+		partitions     int
+		recreate       bool
+		wantErr        string
+		wantPartitions int
+	}{
+		{
+			name:           "create partitioned topic",
+			partitions:     4,
+			wantPartitions: 4,
+		},
+		{
+			name:           "create non-partitioned topic",
+			partitions:     0,
+			wantPartitions: 0,
+		},
+		{
+			name:           "matching partitions",
+			existing:       map[string]int{"persistent/perf/load/topic": 4},
+			partitions:     4,
+			wantPartitions: 4,
+		},
+		{
+			name:           "increase partitions",
+			existing:       map[string]int{"persistent/perf/load/topic": 4},
+			partitions:     8,
+			wantPartitions: 8,
+		},
+		{
+			name:           "reduce partitions without recreate",
+			existing:       map[string]int{"persistent/perf/load/topic": 8},
+			partitions:     4,
+			wantErr:        "cannot be reduced",
+			wantPartitions: 8,
+		},
+		{
+			name:           "reduce partitions with recreate",
+			existing:       map[string]int{"persistent/perf/load/topic": 8},
+			partitions:     4,
+			recreate:       true,
+			wantPartitions: 4,
+		},
+		{
+			name:           "non-partitioned exists but partitions requested",
+			existing:       map[string]int{"persistent/perf/load/topic": 0},
+			partitions:     4,
+			wantErr:        "exists as non-partitioned",
+			wantPartitions: 0,
+		},
+		{
+			name:           "partitioned exists but non-partitioned requested",
+			existing:       map[string]int{"persistent/perf/load/topic": 4},
+			partitions:     0,
+			wantErr:        "specifies a non-partitioned topic",
+			wantPartitions: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, fake := newTestAdminClient(t)
+			for topic, partitions := range tt.existing {
+				fake.tenants["perf"] = true
+				fake.namespaces["perf/load"] = true
+				fake.topics[topic] = partitions
+			}
+
+			err := client.ensureTopic(mustTopicName(t, "persistent://perf/load/topic"), tt.partitions, tt.recreate)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ensureTopic() error = %v, want error containing %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("ensureTopic() error = %v", err)
+			}
+
+			partitions, ok := fake.topics["persistent/perf/load/topic"]
+			if !ok {
+				t.Fatal("topic should exist after ensureTopic()")
+			}
+			if partitions != tt.wantPartitions {
+				t.Errorf("topic has %d partitions, want %d", partitions, tt.wantPartitions)
+			}
+			if !fake.tenants["perf"] || !fake.namespaces["perf/load"] {
+				t.Error("tenant and namespace should have been created")
+			}
+		})
+	}
+}
+
+func TestEnsureTopicMultipleTopics(t *testing.T) {
+	fake := newFakeAdminServer()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	cfg := config.DefaultConfig("")
+	cfg.Pulsar.AdminURL = server.URL
+	cfg.Pulsar.Topic = "persistent://perf/load/orders"
+	cfg.Pulsar.TopicCount = 20
+	cfg.Pulsar.TopicPartitions = 2
+	fake.tenants["perf"] = true
+	fake.namespaces["perf/load"] = true
+	fake.topics["persistent/perf/load/orders-7"] = 8
+
+	err := EnsureTopic(cfg)
+	if err == nil || !strings.Contains(err.Error(), "orders-7 exists with 8 partitions") {
+		t.Fatalf("EnsureTopic() error = %v, want the error of orders-7", err)
+	}
+	for i := 0; i < cfg.Pulsar.TopicCount; i++ {
+		if i == 7 {
+			continue
+		}
+		if partitions, ok := fake.topics[fmt.Sprintf("persistent/perf/load/orders-%d", i)]; !ok || partitions != 2 {
+			t.Errorf("topic orders-%d should have been created with 2 partitions, got %d (exists: %v)", i, partitions, ok)
+		}
+	}
+}
+
+func TestAdminClient_EnsureTopicFailsOnAdminError(t *testing.T) {
+	client, fake := newTestAdminClient(t)
+	fake.failWith = http.StatusInternalServerError
+
+	err := client.ensureTopic(mustTopicName(t, "persistent://public/default/topic"), 4, false)
+	if err == nil {
+		t.Fatal("ensureTopic() should fail when the admin API returns a server error")
+	}
+	for _, req := range fake.requests {
+		if strings.HasPrefix(req, http.MethodPut) {
+			t.Errorf("no resources should be created after a failed lookup, got %s", req)
+		}
+	}
+}
+
+func TestAdminClient_FindRunTopics(t *testing.T) {
+	client, fake := newTestAdminClient(t)
+
+	old := time.Now().Add(-48 * time.Hour).UTC().Format("20060102150405")
+	recent := time.Now().Add(-time.Minute).UTC().Format("20060102150405")
+	fake.topics["persistent/public/default/perf-test-run-"+old+"-aaaa"] = 4
+	fake.topics["persistent/public/default/perf-test-run-"+old+"-bbbb"] = 0
+	fake.topics["persistent/public/default/perf-test-run-"+recent+"-cccc"] = 0
+	fake.topics["persistent/public/default/perf-test"] = 0
+	fake.topics["persistent/public/default/orders-run-custom"] = 0
+
+	runs, err := client.FindRunTopics("public/default", 24*time.Hour)
+	if err != nil {
+		t.Fatalf("FindRunTopics() error = %v", err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("FindRunTopics() returned %d topics, want 2: %+v", len(runs), runs)
+	}
+
+	found := make(map[string]bool)
+	for _, run := range runs {
+		found[run.Topic] = run.Partitioned
+	}
+	if partitioned, ok := found["persistent://public/default/perf-test-run-"+old+"-aaaa"]; !ok || !partitioned {
+		t.Error("expected the old partitioned run topic to be found")
+	}
+	if partitioned, ok := found["persistent://public/default/perf-test-run-"+old+"-bbbb"]; !ok || partitioned {
+		t.Error("expected the old non-partitioned run topic to be found")
+	}
+}
+
+func TestCleanupRun(t *testing.T) {
+	fake := newFakeAdminServer()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	cfg := config.DefaultConfig("")
+	cfg.Pulsar.AdminURL = server.URL
+
+	// Without a run ID nothing may be deleted
+	if err := CleanupRun(cfg, true); err == nil {
+		t.Fatal("CleanupRun() without a run ID should fail")
+	}
+
+	cfg.Pulsar.RunID = "20250101120000-abcd"
+	if err := cfg.ApplyRunID(); err != nil {
+		t.Fatalf("ApplyRunID() error = %v", err)
+	}
+	fake.topics["persistent/public/default/perf-test-run-20250101120000-abcd"] = 2
+
+	if err := CleanupRun(cfg, true); err != nil {
+		t.Fatalf("CleanupRun() error = %v", err)
+	}
+	if _, ok := fake.topics["persistent/public/default/perf-test-run-20250101120000-abcd"]; ok {
+		t.Error("run topic should have been deleted")
+	}
+
+	// Cleaning up again is a no-op
+	if err := CleanupRun(cfg, true); err != nil {
+		t.Errorf("CleanupRun() on a deleted topic error = %v", err)
+	}
+}
+
+func TestCleanupRunMultipleTopics(t *testing.T) {
+	fake := newFakeAdminServer()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	cfg := config.DefaultConfig("")
+	cfg.Pulsar.AdminURL = server.URL
+	cfg.Pulsar.TopicCount = 3
+	cfg.Pulsar.RunID = "20250101120000-abcd"
+	if err := cfg.ApplyRunID(); err != nil {
+		t.Fatalf("ApplyRunID() error = %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		fake.topics[fmt.Sprintf("persistent/public/default/perf-test-%d-run-20250101120000-abcd", i)] = 0
+	}
+
+	if err := CleanupRun(cfg, true); err != nil {
+		t.Fatalf("CleanupRun() error = %v", err)
+	}
+	if len(fake.topics) != 0 {
+		t.Errorf("every run topic should have been deleted, left %v", fake.topics)
+	}
+}
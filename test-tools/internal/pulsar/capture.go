@@ -0,0 +1,222 @@
+package pulsar
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/pulsar-local-lab/perf-test/internal/config"
+	"github.com/pulsar-local-lab/perf-test/internal/generator"
+)
+
+// Capture writes received messages to a traffic file that producers can replay (see
+// generator.Record). NDJSON captures also record the message ID, topic, publish, event
+// and receive times and the payload size. Offsets are publish times relative to the
+// first message of each file, so every rotated file replays on its own.
+//
+// A Capture is shared by all consumer workers of a pool and is safe for concurrent use.
+type Capture struct {
+	cfg    *config.ConsumerConfig
+	format string
+
+	mu        sync.Mutex
+	file      *os.File
+	writer    *generator.RecordWriter
+	files     int
+	fileSize  int64
+	totalSize int64
+	base      time.Time // publish time of the first message in the current file
+	stats     CaptureStats
+	closed    bool
+}
+
+// CaptureStats summarizes a capture
+type CaptureStats struct {
+	Captured uint64 // messages written
+	Skipped  uint64 // messages left out by sampling or the size cap
+	Files    int    // files written, including the current one
+	Bytes    int64  // bytes written across all files
+}
+
+// NewCapture creates the first capture file of the consumer configuration, or returns
+// nil when no capture file is set
+func NewCapture(cfg *config.ConsumerConfig) (*Capture, error) {
+	if cfg.CaptureFile == "" {
+		return nil, nil
+	}
+
+	format := cfg.CaptureFormat
+	if format == "" {
+		var err error
+		if format, err = generator.RecordFormatForPath(cfg.CaptureFile); err != nil {
+			return nil, err
+		}
+	}
+	if format != generator.RecordFormatNDJSON && cfg.CapturePayload == config.CapturePayloadHash {
+		return nil, fmt.Errorf("payload hashes can only be captured in the ndjson format")
+	}
+
+	if dir := filepath.Dir(cfg.CaptureFile); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create capture directory: %w", err)
+		}
+	}
+
+	c := &Capture{cfg: cfg, format: format}
+	if err := c.openFile(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// CapturePath returns the path of the index-th capture file: the configured path for the
+// first file, with -1, -2, ... inserted before the extension for rotated files
+func CapturePath(path string, index int) string {
+	if index == 0 {
+		return path
+	}
+	ext := filepath.Ext(path)
+	return fmt.Sprintf("%s-%d%s", strings.TrimSuffix(path, ext), index, ext)
+}
+
+// openFile creates the next capture file
+func (c *Capture) openFile() error {
+	path := CapturePath(c.cfg.CaptureFile, c.files)
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create capture file: %w", err)
+	}
+	writer, err := generator.NewRecordWriter(file, c.format)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	c.file, c.writer = file, writer
+	c.files++
+	c.fileSize = 0
+	c.base = time.Time{}
+	return nil
+}
+
+// closeFile flushes and closes the current capture file
+func (c *Capture) closeFile() error {
+	err := c.writer.Flush()
+	if closeErr := c.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Write records a received message, subject to sampling and the size cap, and rotates
+// the file once it reaches the rotation size. After an error the capture stops and
+// later messages are skipped.
+func (c *Capture) Write(msg pulsar.Message, receivedAt time.Time) error {
+	rate := c.cfg.CaptureSampleRate
+	sampled := rate <= 0 || rate >= 1 || rand.Float64() < rate
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed || !sampled || (c.cfg.CaptureMaxSize > 0 && c.totalSize >= int64(c.cfg.CaptureMaxSize)) {
+		c.stats.Skipped++
+		return nil
+	}
+
+	publishTime := msg.PublishTime()
+	if c.base.IsZero() {
+		c.base = publishTime
+	}
+	record := generator.Record{
+		Key:        msg.Key(),
+		Properties: msg.Properties(),
+		Offset:     publishTime.Sub(c.base),
+	}
+
+	var n int
+	var err error
+	if c.format == generator.RecordFormatNDJSON {
+		payload := msg.Payload()
+		extra := map[string]any{
+			"message_id":   msg.ID().String(),
+			"topic":        msg.Topic(),
+			"publish_time": publishTime.Format(time.RFC3339Nano),
+			"receive_time": receivedAt.Format(time.RFC3339Nano),
+			"payload_size": len(payload),
+		}
+		if eventTime := msg.EventTime(); !eventTime.IsZero() {
+			extra["event_time"] = eventTime.Format(time.RFC3339Nano)
+		}
+		record.Payload = c.capturedPayload(payload, extra)
+		n, err = c.writer.WriteJSON(record, extra)
+	} else {
+		record.Payload = c.capturedPayload(msg.Payload(), nil)
+		n, err = c.writer.Write(record)
+	}
+	if err != nil {
+		c.closed = true
+		c.closeFile()
+		return fmt.Errorf("failed to write capture record: %w", err)
+	}
+
+	c.stats.Captured++
+	c.fileSize += int64(n)
+	c.totalSize += int64(n)
+	if c.cfg.CaptureRotateSize > 0 && c.fileSize >= int64(c.cfg.CaptureRotateSize) {
+		c.closed = true
+		if err := c.closeFile(); err != nil {
+			return fmt.Errorf("failed to rotate capture file: %w", err)
+		}
+		if err := c.openFile(); err != nil {
+			return err
+		}
+		c.closed = false
+		log.Printf("Capture rotated to %s", c.file.Name())
+	}
+	return nil
+}
+
+// capturedPayload returns the payload to record: nothing when only its hash is captured
+// (stored in extra), otherwise the payload cut to the payload size cap
+func (c *Capture) capturedPayload(payload []byte, extra map[string]any) []byte {
+	if c.cfg.CapturePayload == config.CapturePayloadHash {
+		sum := sha256.Sum256(payload)
+		extra["payload_sha256"] = hex.EncodeToString(sum[:])
+		return nil
+	}
+	if max := c.cfg.CaptureMaxPayloadSize; max > 0 && len(payload) > max {
+		if extra != nil {
+			extra["payload_truncated"] = true
+		}
+		return payload[:max]
+	}
+	return payload
+}
+
+// Stats returns the capture's progress
+func (c *Capture) Stats() CaptureStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Files = c.files
+	stats.Bytes = c.totalSize
+	return stats
+}
+
+// Close flushes and closes the current capture file
+func (c *Capture) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	return c.closeFile()
+}
@@ -0,0 +1,246 @@
+package pulsar
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/pulsar-local-lab/perf-test/internal/config"
+	"github.com/pulsar-local-lab/perf-test/internal/generator"
+)
+
+// fakeMessage implements the parts of pulsar.Message that Capture reads
+type fakeMessage struct {
+	pulsar.Message
+	payload     []byte
+	key         string
+	properties  map[string]string
+	publishTime time.Time
+}
+
+func (m fakeMessage) Payload() []byte               { return m.payload }
+func (m fakeMessage) Key() string                   { return m.key }
+func (m fakeMessage) Properties() map[string]string { return m.properties }
+func (m fakeMessage) PublishTime() time.Time        { return m.publishTime }
+func (m fakeMessage) EventTime() time.Time          { return time.Time{} }
+func (m fakeMessage) Topic() string                 { return "persistent://public/default/perf-test" }
+func (m fakeMessage) ID() pulsar.MessageID          { return pulsar.EarliestMessageID() }
+
+// captureMessages writes count messages published 10ms apart
+func captureMessages(t *testing.T, c *Capture, count int) {
+	t.Helper()
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i := 0; i < count; i++ {
+		msg := fakeMessage{
+			payload:     []byte(strings.Repeat("x", 100)),
+			key:         "key",
+			properties:  map[string]string{"type": "order"},
+			publishTime: start.Add(time.Duration(i) * 10 * time.Millisecond),
+		}
+		if err := c.Write(msg, time.Now()); err != nil {
+			t.Fatalf("failed to capture message %d: %v", i, err)
+		}
+	}
+}
+
+// readCaptureLines decodes the NDJSON lines of a capture file
+func readCaptureLines(t *testing.T, path string) []map[string]any {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open capture file: %v", err)
+	}
+	defer file.Close()
+
+	var lines []map[string]any
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var line map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("invalid capture line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestCaptureReplayable(t *testing.T) {
+	for _, name := range []string{"capture.ndjson", "capture.bin"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			c, err := NewCapture(&config.ConsumerConfig{CaptureFile: path, CapturePayload: config.CapturePayloadFull})
+			if err != nil {
+				t.Fatalf("failed to create capture: %v", err)
+			}
+			captureMessages(t, c, 3)
+			if err := c.Close(); err != nil {
+				t.Fatalf("failed to close capture: %v", err)
+			}
+
+			r, err := generator.NewReplayer(path, generator.ReplayOptions{})
+			if err != nil {
+				t.Fatalf("failed to replay capture: %v", err)
+			}
+			defer r.Close()
+			for i := 0; i < 3; i++ {
+				record, _, err := r.Next()
+				if err != nil {
+					t.Fatalf("record %d: %v", i, err)
+				}
+				if len(record.Payload) != 100 || record.Key != "key" || record.Properties["type"] != "order" {
+					t.Errorf("record %d: unexpected %+v", i, record)
+				}
+				if want := time.Duration(i) * 10 * time.Millisecond; record.Offset != want {
+					t.Errorf("record %d: expected offset %v, got %v", i, want, record.Offset)
+				}
+			}
+			if _, _, err := r.Next(); err != io.EOF {
+				t.Errorf("expected the capture to end after 3 records, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCaptureMetadata(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capture.ndjson")
+	c, err := NewCapture(&config.ConsumerConfig{
+		CaptureFile:           path,
+		CapturePayload:        config.CapturePayloadFull,
+		CaptureMaxPayloadSize: 10,
+	})
+	if err != nil {
+		t.Fatalf("failed to create capture: %v", err)
+	}
+	captureMessages(t, c, 1)
+	c.Close()
+
+	line := readCaptureLines(t, path)[0]
+	for _, field := range []string{"message_id", "topic", "publish_time", "receive_time"} {
+		if _, ok := line[field]; !ok {
+			t.Errorf("expected field %s in %v", field, line)
+		}
+	}
+	if line["payload"] != strings.Repeat("x", 10) || line["payload_truncated"] != true || line["payload_size"] != 100.0 {
+		t.Errorf("expected the payload truncated to 10 of 100 bytes, got %v", line)
+	}
+}
+
+func TestCaptureHash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capture.ndjson")
+	c, err := NewCapture(&config.ConsumerConfig{CaptureFile: path, CapturePayload: config.CapturePayloadHash})
+	if err != nil {
+		t.Fatalf("failed to create capture: %v", err)
+	}
+	captureMessages(t, c, 1)
+	c.Close()
+
+	line := readCaptureLines(t, path)[0]
+	if _, ok := line["payload"]; ok {
+		t.Errorf("expected no payload when capturing hashes, got %v", line)
+	}
+	if hash, _ := line["payload_sha256"].(string); len(hash) != 64 {
+		t.Errorf("expected a SHA-256 payload hash, got %v", line["payload_sha256"])
+	}
+
+	if _, err := NewCapture(&config.ConsumerConfig{
+		CaptureFile:    filepath.Join(t.TempDir(), "capture.bin"),
+		CapturePayload: config.CapturePayloadHash,
+	}); err == nil {
+		t.Error("expected an error for hashes in a binary capture")
+	}
+}
+
+func TestCaptureRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "captures", "capture.bin")
+	c, err := NewCapture(&config.ConsumerConfig{
+		CaptureFile:       path,
+		CapturePayload:    config.CapturePayloadFull,
+		CaptureRotateSize: 1000,
+	})
+	if err != nil {
+		t.Fatalf("failed to create capture: %v", err)
+	}
+	// Binary records of these messages take 140 bytes, so files rotate after 8
+	captureMessages(t, c, 20)
+	c.Close()
+
+	stats := c.Stats()
+	if stats.Captured != 20 || stats.Files != 3 {
+		t.Fatalf("expected 20 messages in 3 files, got %+v", stats)
+	}
+	for i, want := range []int{8, 8, 4} {
+		r, err := generator.NewReplayer(CapturePath(path, i), generator.ReplayOptions{})
+		if err != nil {
+			t.Fatalf("file %d: %v", i, err)
+		}
+		count := 0
+		for {
+			record, _, err := r.Next()
+			if err != nil {
+				break
+			}
+			if count == 0 && record.Offset != 0 {
+				t.Errorf("file %d: expected offsets relative to the file, first is %v", i, record.Offset)
+			}
+			count++
+		}
+		if count != want {
+			t.Errorf("file %d: expected %d records, got %d", i, want, count)
+		}
+	}
+}
+
+func TestCaptureLimits(t *testing.T) {
+	dir := t.TempDir()
+	c, err := NewCapture(&config.ConsumerConfig{
+		CaptureFile:    filepath.Join(dir, "capture.bin"),
+		CapturePayload: config.CapturePayloadFull,
+		CaptureMaxSize: 300,
+	})
+	if err != nil {
+		t.Fatalf("failed to create capture: %v", err)
+	}
+	captureMessages(t, c, 5)
+	c.Close()
+	if stats := c.Stats(); stats.Captured != 3 || stats.Skipped != 2 {
+		t.Errorf("expected the size cap to stop the capture after 3 messages, got %+v", stats)
+	}
+
+	c, err = NewCapture(&config.ConsumerConfig{
+		CaptureFile:       filepath.Join(dir, "sampled.bin"),
+		CapturePayload:    config.CapturePayloadFull,
+		CaptureSampleRate: 0.25,
+	})
+	if err != nil {
+		t.Fatalf("failed to create capture: %v", err)
+	}
+	captureMessages(t, c, 2000)
+	c.Close()
+	if stats := c.Stats(); stats.Captured < 350 || stats.Captured > 650 || stats.Captured+stats.Skipped != 2000 {
+		t.Errorf("expected about a quarter of 2000 messages captured, got %+v", stats)
+	}
+}
+
+func TestCapturePath(t *testing.T) {
+	tests := []struct {
+		path  string
+		index int
+		want  string
+	}{
+		{"capture.ndjson", 0, "capture.ndjson"},
+		{"capture.ndjson", 2, "capture-2.ndjson"},
+		{"out/capture", 1, "out/capture-1"},
+	}
+
+	for _, tt := range tests {
+		if got := CapturePath(tt.path, tt.index); got != tt.want {
+			t.Errorf("CapturePath(%q, %d) = %q, want %q", tt.path, tt.index, got, tt.want)
+		}
+	}
+}
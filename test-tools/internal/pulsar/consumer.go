@@ -3,6 +3,8 @@ package pulsar
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -10,6 +12,7 @@ import (
 	"github.com/apache/pulsar-client-go/pulsar"
 	pulsarlog "github.com/apache/pulsar-client-go/pulsar/log"
 	"github.com/pulsar-local-lab/perf-test/internal/config"
+	"github.com/streamnative/pulsar-admin-go/pkg/utils"
 )
 
 // ConsumerClient wraps a Pulsar consumer with additional functionality for production use.
@@ -142,14 +145,7 @@ func (cc *ConsumerClient) connect(ctx context.Context) error {
 	}
 
 	// Create consumer with configured options
-	consumer, err := client.Subscribe(pulsar.ConsumerOptions{
-		Topic:               cc.pulsarCfg.Topic,
-		SubscriptionName:    cc.consumerCfg.SubscriptionName,
-		Type:                getSubscriptionType(cc.consumerCfg.SubscriptionType),
-		ReceiverQueueSize:   cc.consumerCfg.ReceiverQueueSize,
-		NackRedeliveryDelay: 5 * time.Second,
-		Name:                cc.consumerID,
-	})
+	consumer, err := client.Subscribe(cc.consumerOptions())
 	if err != nil {
 		client.Close()
 		cc.lastError = err
@@ -165,6 +161,32 @@ func (cc *ConsumerClient) connect(ctx context.Context) error {
 	return nil
 }
 
+// consumerOptions returns the configured consumer options. Consumers subscribe to the
+// topics matching TopicsPattern if set, otherwise to every topic of the topic list.
+func (cc *ConsumerClient) consumerOptions() pulsar.ConsumerOptions {
+	options := pulsar.ConsumerOptions{
+		SubscriptionName:            cc.consumerCfg.SubscriptionName,
+		Type:                        getSubscriptionType(cc.consumerCfg.SubscriptionType),
+		ReceiverQueueSize:           cc.consumerCfg.ReceiverQueueSize,
+		NackRedeliveryDelay:         5 * time.Second,
+		Name:                        cc.consumerID,
+		MaxPendingChunkedMessage:    cc.consumerCfg.MaxPendingChunkedMessages,
+		ExpireTimeOfIncompleteChunk: time.Duration(cc.consumerCfg.ChunkExpiry),
+	}
+
+	topics := cc.pulsarCfg.TopicNames()
+	switch {
+	case cc.consumerCfg.TopicsPattern != "":
+		options.TopicsPattern = cc.consumerCfg.TopicsPattern
+		options.AutoDiscoveryPeriod = time.Duration(cc.consumerCfg.PatternDiscoveryInterval)
+	case len(topics) > 1:
+		options.Topics = topics
+	default:
+		options.Topic = cc.pulsarCfg.Topic
+	}
+	return options
+}
+
 // Receive receives a message from the Pulsar topic.
 // This method blocks until a message is available or the context is cancelled.
 //
@@ -346,6 +368,23 @@ func (cc *ConsumerClient) SeekByTime(timestamp time.Time) error {
 	return nil
 }
 
+// SeekTo moves the subscription cursor to a position parsed by config.ParseSeekPosition.
+// The earliest position seeks by time to the epoch, which unlike seeking to the earliest
+// message ID also works on partitioned topics. Message IDs without a partition refer to a
+// non-partitioned topic.
+func (cc *ConsumerClient) SeekTo(start config.ReaderStart) error {
+	switch start.Position {
+	case config.ReaderStartEarliest:
+		return cc.SeekByTime(time.Unix(0, 0))
+	case config.ReaderStartTime:
+		return cc.SeekByTime(start.Time)
+	case config.ReaderStartMessage:
+		return cc.Seek(pulsar.NewMessageID(start.LedgerID, start.EntryID, -1, max(start.Partition, 0)))
+	default:
+		return fmt.Errorf("cannot seek a subscription to the %s message", start.Position)
+	}
+}
+
 // Chan returns a channel for receiving messages asynchronously.
 // This provides an alternative to the blocking Receive() method.
 //
@@ -533,6 +572,54 @@ func (cc *ConsumerClient) Unsubscribe() error {
 	return nil
 }
 
+// IsChunkedMessageID reports whether a received message was reassembled from multiple chunks.
+// The client does not export its chunk message ID type; its string form lists the
+// first and last chunk IDs separated by a semicolon, which is what we detect here.
+func IsChunkedMessageID(msgID pulsar.MessageID) bool {
+	if msgID == nil {
+		return false
+	}
+	return strings.Contains(msgID.String(), ";")
+}
+
+// partitionSuffix is appended to the topic name of each partition of a partitioned topic
+const partitionSuffix = "-partition-"
+
+// TopicPartition returns the partition index encoded in a topic name such as
+// "persistent://public/default/perf-test-partition-3", or -1 for a non-partitioned topic.
+func TopicPartition(topic string) int32 {
+	idx := strings.LastIndex(topic, partitionSuffix)
+	if idx < 0 {
+		return -1
+	}
+	partition, err := strconv.ParseInt(topic[idx+len(partitionSuffix):], 10, 32)
+	if err != nil || partition < 0 {
+		return -1
+	}
+	return int32(partition)
+}
+
+// TopicBase returns the name of the topic a partition belongs to, such as
+// "persistent://public/default/perf-test" for "persistent://public/default/perf-test-partition-3".
+// Names of non-partitioned topics are returned unchanged.
+func TopicBase(topic string) string {
+	if TopicPartition(topic) < 0 {
+		return topic
+	}
+	return topic[:strings.LastIndex(topic, partitionSuffix)]
+}
+
+// FullTopicName returns the fully qualified name of a topic, such as
+// "persistent://public/default/orders" for "orders", the form consumers see on messages.
+// Invalid names are returned unchanged.
+func FullTopicName(topic string) string {
+	name, err := utils.GetTopicName(topic)
+	if err != nil {
+		return topic
+	}
+	return name.String()
+}
+
 // getSubscriptionType converts string subscription type to Pulsar SubscriptionType enum.
 // Supported subscription types: Exclusive, Shared, Failover, KeyShared
 func getSubscriptionType(subType string) pulsar.SubscriptionType {
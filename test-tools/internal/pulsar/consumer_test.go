@@ -600,6 +600,60 @@ func TestConsumerClient_Seek(t *testing.T) {
 	})
 }
 
+func TestConsumerClient_SeekTo(t *testing.T) {
+	seekTime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		start    config.ReaderStart
+		wantTime time.Time
+		wantID   pulsar.MessageID
+		wantErr  bool
+	}{
+		{"earliest", config.ReaderStart{Position: config.ReaderStartEarliest}, time.Unix(0, 0), nil, false},
+		{"time", config.ReaderStart{Position: config.ReaderStartTime, Time: seekTime}, seekTime, nil, false},
+		{"message", config.ReaderStart{Position: config.ReaderStartMessage, LedgerID: 12, EntryID: 34, Partition: -1},
+			time.Time{}, pulsar.NewMessageID(12, 34, -1, 0), false},
+		{"latest", config.ReaderStart{Position: config.ReaderStartLatest}, time.Time{}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotTime time.Time
+			var gotID pulsar.MessageID
+			mock := &mockConsumer{
+				seekFunc: func(msgID pulsar.MessageID) error {
+					gotID = msgID
+					return nil
+				},
+				seekByTimeFunc: func(t time.Time) error {
+					gotTime = t
+					return nil
+				},
+			}
+			cc := &ConsumerClient{consumer: mock, connected: true}
+
+			err := cc.SeekTo(tt.start)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("SeekTo() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SeekTo() error = %v", err)
+			}
+			if !gotTime.Equal(tt.wantTime) {
+				t.Errorf("SeekByTime() called with %v, want %v", gotTime, tt.wantTime)
+			}
+			if tt.wantID != nil && (gotID == nil || gotID.LedgerID() != tt.wantID.LedgerID() ||
+				gotID.EntryID() != tt.wantID.EntryID() || gotID.PartitionIdx() != tt.wantID.PartitionIdx()) {
+				t.Errorf("Seek() called with %v, want %v", gotID, tt.wantID)
+			}
+		})
+	}
+}
+
 func TestConsumerClient_SeekByTime(t *testing.T) {
 	t.Run("SeekByTime success", func(t *testing.T) {
 		cc := &ConsumerClient{
@@ -1007,4 +1061,150 @@ func TestNewConsumerClient_LegacyWrapper(t *testing.T) {
 	if err == nil {
 		t.Error("NewConsumerClient() with invalid host error = nil, want error")
 	}
-}
\ No newline at end of file
+}
+
+// chunkedMessageID mimics the string form of the client's chunk message ID
+type chunkedMessageID struct {
+	mockMessageID
+}
+
+func (c *chunkedMessageID) String() string { return "10:1:-1;10:4:-1" }
+
+func TestIsChunkedMessageID(t *testing.T) {
+	tests := []struct {
+		name  string
+		msgID pulsar.MessageID
+		want  bool
+	}{
+		{"nil ID", nil, false},
+		{"regular ID", &mockMessageID{id: 1}, false},
+		{"chunked ID", &chunkedMessageID{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsChunkedMessageID(tt.msgID); got != tt.want {
+				t.Errorf("IsChunkedMessageID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTopicPartition(t *testing.T) {
+	tests := []struct {
+		topic string
+		want  int32
+	}{
+		{"persistent://public/default/perf-test-partition-3", 3},
+		{"persistent://public/default/perf-test-partition-12", 12},
+		{"persistent://public/default/perf-test", -1},
+		{"persistent://public/default/perf-test-partition-", -1},
+		{"persistent://public/default/perf-test-partition-x", -1},
+		{"", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.topic, func(t *testing.T) {
+			if got := TopicPartition(tt.topic); got != tt.want {
+				t.Errorf("TopicPartition(%q) = %d, want %d", tt.topic, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTopicBase(t *testing.T) {
+	tests := []struct {
+		topic string
+		want  string
+	}{
+		{"persistent://public/default/perf-test-partition-3", "persistent://public/default/perf-test"},
+		{"persistent://public/default/perf-test-0-partition-1", "persistent://public/default/perf-test-0"},
+		{"persistent://public/default/perf-test", "persistent://public/default/perf-test"},
+		{"persistent://public/default/perf-test-partition-x", "persistent://public/default/perf-test-partition-x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.topic, func(t *testing.T) {
+			if got := TopicBase(tt.topic); got != tt.want {
+				t.Errorf("TopicBase(%q) = %q, want %q", tt.topic, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFullTopicName(t *testing.T) {
+	tests := []struct {
+		topic string
+		want  string
+	}{
+		{"orders", "persistent://public/default/orders"},
+		{"perf/load/orders", "persistent://perf/load/orders"},
+		{"non-persistent://public/default/orders", "non-persistent://public/default/orders"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.topic, func(t *testing.T) {
+			if got := FullTopicName(tt.topic); got != tt.want {
+				t.Errorf("FullTopicName(%q) = %q, want %q", tt.topic, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsumerClient_ConsumerOptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(*config.Config)
+		wantTopic   string
+		wantTopics  []string
+		wantPattern string
+	}{
+		{
+			name:      "single topic",
+			modify:    func(c *config.Config) {},
+			wantTopic: "persistent://public/default/perf-test",
+		},
+		{
+			name:       "topic list",
+			modify:     func(c *config.Config) { c.Pulsar.Topics = "orders,payments" },
+			wantTopics: []string{"orders", "payments"},
+		},
+		{
+			name:       "generated topics",
+			modify:     func(c *config.Config) { c.Pulsar.TopicCount = 2 },
+			wantTopics: []string{"persistent://public/default/perf-test-0", "persistent://public/default/perf-test-1"},
+		},
+		{
+			name: "pattern wins",
+			modify: func(c *config.Config) {
+				c.Pulsar.TopicCount = 2
+				c.Consumer.TopicsPattern = "persistent://public/default/perf-test-.*"
+			},
+			wantPattern: "persistent://public/default/perf-test-.*",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig("")
+			tt.modify(cfg)
+			cc := &ConsumerClient{pulsarCfg: &cfg.Pulsar, consumerCfg: &cfg.Consumer, consumerID: "consumer-0"}
+
+			options := cc.consumerOptions()
+			if options.Topic != tt.wantTopic || options.TopicsPattern != tt.wantPattern {
+				t.Errorf("expected topic %q and pattern %q, got %q and %q", tt.wantTopic, tt.wantPattern, options.Topic, options.TopicsPattern)
+			}
+			if len(options.Topics) != len(tt.wantTopics) {
+				t.Fatalf("expected topics %v, got %v", tt.wantTopics, options.Topics)
+			}
+			for i := range tt.wantTopics {
+				if options.Topics[i] != tt.wantTopics[i] {
+					t.Errorf("expected topics %v, got %v", tt.wantTopics, options.Topics)
+				}
+			}
+			if options.SubscriptionName != cfg.Consumer.SubscriptionName {
+				t.Errorf("expected subscription %s, got %s", cfg.Consumer.SubscriptionName, options.SubscriptionName)
+			}
+		})
+	}
+}
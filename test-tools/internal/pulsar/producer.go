@@ -29,8 +29,9 @@ import (
 //	    log.Printf("send failed: %v", err)
 //	}
 type ProducerClient struct {
-	client   pulsar.Client
-	producer pulsar.Producer
+	client    pulsar.Client
+	producer  pulsar.Producer   // producer of the first topic
+	producers []pulsar.Producer // one per topic of PulsarConfig.TopicNames
 
 	pulsarCfg   *config.PulsarConfig
 	producerCfg *config.ProducerConfig
@@ -127,23 +128,25 @@ func (pc *ProducerClient) connect(ctx context.Context) error {
 		return fmt.Errorf("failed to create pulsar client: %w", err)
 	}
 
-	// Create producer with configured options
-	producer, err := client.CreateProducer(pulsar.ProducerOptions{
-		Topic:               pc.pulsarCfg.Topic,
-		DisableBatching:     !pc.producerCfg.BatchingEnabled,
-		BatchingMaxMessages: uint(pc.producerCfg.BatchingMaxSize),
-		CompressionType:     getCompressionType(pc.producerCfg.CompressionType),
-		SendTimeout:         pc.producerCfg.SendTimeout,
-		MaxPendingMessages:  pc.producerCfg.MaxPendingMsg,
-	})
-	if err != nil {
-		client.Close()
-		pc.lastError = err
-		return fmt.Errorf("failed to create producer: %w", err)
+	// Create a producer with the configured options for every topic
+	topics := pc.pulsarCfg.TopicNames()
+	producers := make([]pulsar.Producer, 0, len(topics))
+	for _, topic := range topics {
+		producer, err := client.CreateProducer(pc.producerOptions(topic))
+		if err != nil {
+			for _, p := range producers {
+				p.Close()
+			}
+			client.Close()
+			pc.lastError = err
+			return fmt.Errorf("failed to create producer for topic %s: %w", topic, err)
+		}
+		producers = append(producers, producer)
 	}
 
 	pc.client = client
-	pc.producer = producer
+	pc.producer = producers[0]
+	pc.producers = producers
 	pc.connected = true
 	pc.lastError = nil
 
@@ -151,6 +154,38 @@ func (pc *ProducerClient) connect(ctx context.Context) error {
 	return nil
 }
 
+// producerOptions returns the configured producer options for a topic
+func (pc *ProducerClient) producerOptions(topic string) pulsar.ProducerOptions {
+	return pulsar.ProducerOptions{
+		Topic:               topic,
+		DisableBatching:     !pc.producerCfg.BatchingEnabled,
+		BatchingMaxMessages: uint(pc.producerCfg.BatchingMaxSize),
+		CompressionType:     getCompressionType(pc.producerCfg.CompressionType),
+		SendTimeout:         time.Duration(pc.producerCfg.SendTimeout),
+		MaxPendingMessages:  pc.producerCfg.MaxPendingMsg,
+		EnableChunking:      pc.producerCfg.EnableChunking,
+		ChunkMaxMessageSize: uint(pc.producerCfg.ChunkMaxMessageSize),
+
+		// Routing and batching policy
+		MessageRouter:           getMessageRouter(pc.producerCfg.RoutingMode, pc.producerCfg.CustomPartition),
+		HashingScheme:           getHashingScheme(pc.producerCfg.HashingScheme),
+		BatcherBuilderType:      getBatcherBuilderType(pc.producerCfg.BatcherType),
+		BatchingMaxPublishDelay: time.Duration(pc.producerCfg.BatchingMaxPublishDelay),
+		BatchingMaxSize:         uint(pc.producerCfg.BatchingMaxBytes),
+	}
+}
+
+// allProducers returns the producers of every topic
+func (pc *ProducerClient) allProducers() []pulsar.Producer {
+	if len(pc.producers) > 0 {
+		return pc.producers
+	}
+	if pc.producer != nil {
+		return []pulsar.Producer{pc.producer}
+	}
+	return nil
+}
+
 // Send sends a single message synchronously to the configured Pulsar topic.
 // This method blocks until the message is acknowledged by the broker or the context times out.
 //
@@ -204,29 +239,51 @@ func (pc *ProducerClient) Send(ctx context.Context, payload []byte) (pulsar.Mess
 //   - pulsar.MessageID: Unique identifier for the sent message
 //   - error: Send error or nil on success
 func (pc *ProducerClient) SendWithProperties(ctx context.Context, payload []byte, properties map[string]string) (pulsar.MessageID, error) {
+	return pc.SendMessage(ctx, &pulsar.ProducerMessage{
+		Payload:    payload,
+		Properties: properties,
+	})
+}
+
+// SendMessage sends a fully populated producer message synchronously.
+// Use this when the message needs a key, properties or event time in addition to the payload.
+//
+// Parameters:
+//   - ctx: Context for timeout and cancellation control
+//   - msg: Message to send
+//
+// Returns:
+//   - pulsar.MessageID: Unique identifier for the sent message
+//   - error: Send error or nil on success
+func (pc *ProducerClient) SendMessage(ctx context.Context, msg *pulsar.ProducerMessage) (pulsar.MessageID, error) {
+	return pc.SendMessageTo(ctx, 0, msg)
+}
+
+// SendMessageTo sends a fully populated producer message synchronously to one of the
+// configured topics, identified by its index in PulsarConfig.TopicNames.
+func (pc *ProducerClient) SendMessageTo(ctx context.Context, topic int, msg *pulsar.ProducerMessage) (pulsar.MessageID, error) {
 	pc.mu.RLock()
 	if !pc.connected || pc.closed {
 		pc.mu.RUnlock()
 		return nil, fmt.Errorf("producer not connected")
 	}
-	producer := pc.producer
+	producers := pc.allProducers()
 	pc.mu.RUnlock()
-
-	msg := &pulsar.ProducerMessage{
-		Payload:    payload,
-		Properties: properties,
+	if topic < 0 || topic >= len(producers) {
+		return nil, fmt.Errorf("topic index %d out of range (%d topics)", topic, len(producers))
 	}
+	producer := producers[topic]
 
 	msgID, err := producer.Send(ctx, msg)
 	if err != nil {
 		atomic.AddUint64(&pc.stats.MessageFailures, 1)
 		pc.lastError = err
-		return nil, fmt.Errorf("failed to send message with properties: %w", err)
+		return nil, fmt.Errorf("failed to send message: %w", err)
 	}
 
 	// Update statistics
 	atomic.AddUint64(&pc.stats.MessagesSent, 1)
-	atomic.AddUint64(&pc.stats.BytesSent, uint64(len(payload)))
+	atomic.AddUint64(&pc.stats.BytesSent, uint64(len(msg.Payload)))
 	pc.stats.LastMessageTime = time.Now()
 
 	return msgID, nil
@@ -294,15 +351,17 @@ func (pc *ProducerClient) Flush() error {
 		pc.mu.RUnlock()
 		return fmt.Errorf("producer not connected")
 	}
-	producer := pc.producer
+	producers := pc.allProducers()
 	pc.mu.RUnlock()
 
 	// Use FlushWithCtx with timeout to prevent indefinite blocking
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	if err := producer.FlushWithCtx(ctx); err != nil {
-		return fmt.Errorf("failed to flush producer: %w", err)
+	for _, producer := range producers {
+		if err := producer.FlushWithCtx(ctx); err != nil {
+			return fmt.Errorf("failed to flush producer: %w", err)
+		}
 	}
 
 	return nil
@@ -326,14 +385,14 @@ func (pc *ProducerClient) Close() error {
 	pc.connected = false
 
 	// Flush pending messages before closing with timeout
-	if pc.producer != nil {
+	for _, producer := range pc.allProducers() {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		if err := pc.producer.FlushWithCtx(ctx); err != nil {
+		if err := producer.FlushWithCtx(ctx); err != nil {
 			// Suppressed: log.Printf("Warning: failed to flush producer during close: %v", err)
 			_ = err
 		}
 		cancel()
-		pc.producer.Close()
+		producer.Close()
 	}
 
 	if pc.client != nil {
@@ -404,10 +463,11 @@ func (pc *ProducerClient) Reconnect(ctx context.Context, maxRetries int) error {
 	}
 
 	// Close existing connections
-	if pc.producer != nil {
-		pc.producer.Close()
-		pc.producer = nil
+	for _, producer := range pc.allProducers() {
+		producer.Close()
 	}
+	pc.producer = nil
+	pc.producers = nil
 	if pc.client != nil {
 		pc.client.Close()
 		pc.client = nil
@@ -464,6 +524,47 @@ func getCompressionType(compressionType string) pulsar.CompressionType {
 	}
 }
 
+// getHashingScheme converts a string hashing scheme to the Pulsar HashingScheme enum.
+// Supported schemes: JavaStringHash (default), Murmur3_32Hash
+func getHashingScheme(scheme string) pulsar.HashingScheme {
+	switch scheme {
+	case config.HashingMurmur3:
+		return pulsar.Murmur3_32Hash
+	default:
+		return pulsar.JavaStringHash
+	}
+}
+
+// getBatcherBuilderType converts a string batcher type to the Pulsar BatcherBuilderType enum.
+// Supported types: Default, KeyBased
+func getBatcherBuilderType(batcherType string) pulsar.BatcherBuilderType {
+	switch batcherType {
+	case config.BatcherKeyBased:
+		return pulsar.KeyBasedBatchBuilder
+	default:
+		return pulsar.DefaultBatchBuilder
+	}
+}
+
+// getMessageRouter returns the message router for a routing mode.
+// A nil router selects the client's default round-robin routing (keyed messages are hashed).
+func getMessageRouter(routingMode string, customPartition int) func(*pulsar.ProducerMessage, pulsar.TopicMetadata) int {
+	switch routingMode {
+	case config.RoutingSinglePartition:
+		return pulsar.NewSinglePartitionRouter()
+	case config.RoutingCustom:
+		return func(_ *pulsar.ProducerMessage, md pulsar.TopicMetadata) int {
+			partitions := int(md.NumPartitions())
+			if partitions <= 1 {
+				return 0
+			}
+			return customPartition % partitions
+		}
+	default:
+		return nil
+	}
+}
+
 // Legacy wrapper for backward compatibility
 // Deprecated: Use NewProducer instead
 func NewProducerClient(cfg *config.Config) (*ProducerClient, error) {
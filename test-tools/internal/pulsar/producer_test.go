@@ -86,6 +86,79 @@ func TestGetCompressionType(t *testing.T) {
 	}
 }
 
+func TestGetHashingScheme(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected pulsar.HashingScheme
+	}{
+		{"JavaStringHash", "JavaStringHash", pulsar.JavaStringHash},
+		{"Murmur3", "Murmur3_32Hash", pulsar.Murmur3_32Hash},
+		{"Empty", "", pulsar.JavaStringHash},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := getHashingScheme(tt.input)
+			if result != tt.expected {
+				t.Errorf("getHashingScheme(%s) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetBatcherBuilderType(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected pulsar.BatcherBuilderType
+	}{
+		{"Default", "Default", pulsar.DefaultBatchBuilder},
+		{"KeyBased", "KeyBased", pulsar.KeyBasedBatchBuilder},
+		{"Empty", "", pulsar.DefaultBatchBuilder},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := getBatcherBuilderType(tt.input)
+			if result != tt.expected {
+				t.Errorf("getBatcherBuilderType(%s) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+// mockTopicMetadata implements pulsar.TopicMetadata for router tests
+type mockTopicMetadata struct {
+	partitions uint32
+}
+
+func (m *mockTopicMetadata) NumPartitions() uint32 { return m.partitions }
+
+func TestGetMessageRouter(t *testing.T) {
+	if router := getMessageRouter("RoundRobin", 0); router != nil {
+		t.Error("getMessageRouter(RoundRobin) should return nil to use the default router")
+	}
+	if router := getMessageRouter("SinglePartition", 0); router == nil {
+		t.Error("getMessageRouter(SinglePartition) should return a router")
+	}
+
+	router := getMessageRouter("Custom", 5)
+	if router == nil {
+		t.Fatal("getMessageRouter(Custom) should return a router")
+	}
+	msg := &pulsar.ProducerMessage{Payload: []byte("test")}
+	if got := router(msg, &mockTopicMetadata{partitions: 8}); got != 5 {
+		t.Errorf("custom router with 8 partitions = %d, want 5", got)
+	}
+	if got := router(msg, &mockTopicMetadata{partitions: 4}); got != 1 {
+		t.Errorf("custom router with 4 partitions = %d, want 1", got)
+	}
+	if got := router(msg, &mockTopicMetadata{partitions: 1}); got != 0 {
+		t.Errorf("custom router with 1 partition = %d, want 0", got)
+	}
+}
+
 func TestNewProducer_ValidationErrors(t *testing.T) {
 	ctx := context.Background()
 
@@ -300,6 +373,45 @@ func TestProducerClient_SendAsyncErrorHandling(t *testing.T) {
 	}
 }
 
+func TestProducerClient_SendMessageTo(t *testing.T) {
+	orders, payments := &mockProducer{}, &mockProducer{}
+	closed := 0
+	orders.closeFunc = func() { closed++ }
+	payments.closeFunc = func() { closed++ }
+
+	pc := &ProducerClient{
+		pulsarCfg: &config.PulsarConfig{
+			ServiceURL: "pulsar://localhost:6650",
+			Topics:     "orders,payments",
+		},
+		producerCfg: &config.ProducerConfig{},
+		producer:    orders,
+		producers:   []pulsar.Producer{orders, payments},
+		connected:   true,
+	}
+
+	ctx := context.Background()
+	msg := &pulsar.ProducerMessage{Payload: []byte("test")}
+	for _, topic := range []int{1, 1, 0} {
+		if _, err := pc.SendMessageTo(ctx, topic, msg); err != nil {
+			t.Fatalf("SendMessageTo(%d) error = %v", topic, err)
+		}
+	}
+	if orders.sendCount != 1 || payments.sendCount != 2 {
+		t.Errorf("expected 1 message to orders and 2 to payments, got %d and %d", orders.sendCount, payments.sendCount)
+	}
+	if _, err := pc.SendMessageTo(ctx, 2, msg); err == nil {
+		t.Error("SendMessageTo() with an out-of-range topic should fail")
+	}
+
+	if err := pc.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if closed != 2 {
+		t.Errorf("expected every topic producer to be closed, got %d", closed)
+	}
+}
+
 func TestProducerClient_Flush(t *testing.T) {
 	t.Run("Flush success", func(t *testing.T) {
 		pc := &ProducerClient{
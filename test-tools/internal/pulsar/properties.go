@@ -0,0 +1,32 @@
+package pulsar
+
+import (
+	"strconv"
+	"time"
+)
+
+// SendTimeProperty is the message property holding the producer's send time in
+// nanoseconds since the Unix epoch
+const SendTimeProperty = "perf-send-time"
+
+// ChecksumProperty is the message property holding the payload checksum
+// ("<algorithm>:<hex digest>", see generator.Checksummer)
+const ChecksumProperty = "perf-checksum"
+
+// FormatSendTime encodes a send time for the SendTimeProperty message property
+func FormatSendTime(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// ParseSendTime returns the send time stored in the message properties, if any
+func ParseSendTime(properties map[string]string) (time.Time, bool) {
+	value, ok := properties[SendTimeProperty]
+	if !ok {
+		return time.Time{}, false
+	}
+	nanos, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, nanos), true
+}
@@ -0,0 +1,37 @@
+package pulsar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSendTimeRoundTrip(t *testing.T) {
+	sent := time.Date(2025, 1, 2, 3, 4, 5, 123456789, time.UTC)
+
+	got, ok := ParseSendTime(map[string]string{SendTimeProperty: FormatSendTime(sent)})
+	if !ok {
+		t.Fatal("ParseSendTime() ok = false, want true")
+	}
+	if !got.Equal(sent) {
+		t.Errorf("ParseSendTime() = %v, want %v", got, sent)
+	}
+}
+
+func TestParseSendTimeInvalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties map[string]string
+	}{
+		{"nil properties", nil},
+		{"missing property", map[string]string{"other": "1"}},
+		{"not a number", map[string]string{SendTimeProperty: "yesterday"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := ParseSendTime(tt.properties); ok {
+				t.Errorf("ParseSendTime(%v) ok = true, want false", tt.properties)
+			}
+		})
+	}
+}
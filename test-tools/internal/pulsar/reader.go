@@ -0,0 +1,267 @@
+package pulsar
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	pulsarlog "github.com/apache/pulsar-client-go/pulsar/log"
+	"github.com/pulsar-local-lab/perf-test/internal/config"
+)
+
+// ReaderClient wraps a Pulsar reader, which reads a topic from a chosen position without
+// a subscription. Readers do not acknowledge messages and leave no backlog behind, so
+// several readers each read the whole topic.
+//
+// Example usage:
+//
+//	cfg := config.DefaultConfig("")
+//	reader, err := NewReader(ctx, &cfg.Pulsar, &cfg.Consumer, cfg.Pulsar.Topic, "reader-1")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer reader.Close()
+//
+//	for reader.HasNext() {
+//	    msg, err := reader.Next(ctx)
+//	    if err != nil {
+//	        break
+//	    }
+//	    // Process message
+//	}
+type ReaderClient struct {
+	client pulsar.Client
+	reader pulsar.Reader
+
+	pulsarCfg   *config.PulsarConfig
+	consumerCfg *config.ConsumerConfig
+	topic       string
+	readerID    string
+
+	// Connection state management
+	mu        sync.RWMutex
+	connected bool
+	closed    bool
+
+	// Statistics tracking
+	stats ReaderStats
+}
+
+// ReaderStats holds reader statistics.
+type ReaderStats struct {
+	// MessagesRead is the total number of messages read
+	MessagesRead uint64
+
+	// BytesRead is the total number of payload bytes read
+	BytesRead uint64
+
+	// ReadErrors is the total number of read errors, including timeouts
+	ReadErrors uint64
+}
+
+// NewReader creates a reader on the given topic starting at consumerCfg.ReaderStart.
+// Readers starting at a time are created at the earliest message and then seek to
+// the first message published at or after that time. ctx bounds connecting and seeking.
+func NewReader(ctx context.Context, pulsarCfg *config.PulsarConfig, consumerCfg *config.ConsumerConfig, topic, readerID string) (*ReaderClient, error) {
+	if pulsarCfg == nil {
+		return nil, fmt.Errorf("pulsar config cannot be nil")
+	}
+	if consumerCfg == nil {
+		return nil, fmt.Errorf("consumer config cannot be nil")
+	}
+	if topic == "" {
+		return nil, fmt.Errorf("topic cannot be empty")
+	}
+	if readerID == "" {
+		return nil, fmt.Errorf("reader ID cannot be empty")
+	}
+
+	start, err := config.ParseReaderStart(consumerCfg.ReaderStart, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("invalid reader start position: %w", err)
+	}
+
+	rc := &ReaderClient{
+		pulsarCfg:   pulsarCfg,
+		consumerCfg: consumerCfg,
+		topic:       topic,
+		readerID:    readerID,
+	}
+
+	if err := rc.connect(ctx, start); err != nil {
+		return nil, err
+	}
+
+	return rc, nil
+}
+
+// connect creates the Pulsar client and the reader at the start position. The client calls
+// cannot be interrupted, so ctx is checked between steps and its deadline caps the client
+// timeouts.
+func (rc *ReaderClient) connect(ctx context.Context, start config.ReaderStart) error {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if rc.closed {
+		return fmt.Errorf("reader is closed")
+	}
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("reader connection cancelled: %w", err)
+	}
+	timeout := 30 * time.Second
+	if deadline, ok := ctx.Deadline(); ok {
+		if timeout = min(timeout, time.Until(deadline)); timeout <= 0 {
+			return fmt.Errorf("reader connection cancelled: %w", context.DeadlineExceeded)
+		}
+	}
+
+	client, err := pulsar.NewClient(pulsar.ClientOptions{
+		URL:               rc.pulsarCfg.ServiceURL,
+		OperationTimeout:  timeout,
+		ConnectionTimeout: timeout,
+		Logger:            pulsarlog.DefaultNopLogger(), // Disable all Pulsar client logging
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create pulsar client: %w", err)
+	}
+
+	reader, err := client.CreateReader(rc.readerOptions(start))
+	if err != nil {
+		client.Close()
+		return fmt.Errorf("failed to create reader: %w", err)
+	}
+
+	if start.Position == config.ReaderStartTime && ctx.Err() == nil {
+		if err := reader.SeekByTime(start.Time); err != nil {
+			reader.Close()
+			client.Close()
+			return fmt.Errorf("failed to seek reader to %s: %w", start.Time.Format(time.RFC3339), err)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		reader.Close()
+		client.Close()
+		return fmt.Errorf("reader connection cancelled: %w", err)
+	}
+
+	rc.client = client
+	rc.reader = reader
+	rc.connected = true
+	return nil
+}
+
+// readerOptions returns the configured reader options for a start position
+func (rc *ReaderClient) readerOptions(start config.ReaderStart) pulsar.ReaderOptions {
+	return pulsar.ReaderOptions{
+		Topic:                       rc.topic,
+		Name:                        rc.readerID,
+		StartMessageID:              StartMessageID(start),
+		StartMessageIDInclusive:     start.Position == config.ReaderStartMessage,
+		ReceiverQueueSize:           rc.consumerCfg.ReceiverQueueSize,
+		MaxPendingChunkedMessage:    rc.consumerCfg.MaxPendingChunkedMessages,
+		ExpireTimeOfIncompleteChunk: time.Duration(rc.consumerCfg.ChunkExpiry),
+	}
+}
+
+// StartMessageID returns the message ID a reader starting at the given position is
+// created at. Readers starting at a time start at the earliest message and seek from there.
+func StartMessageID(start config.ReaderStart) pulsar.MessageID {
+	switch start.Position {
+	case config.ReaderStartLatest:
+		return pulsar.LatestMessageID()
+	case config.ReaderStartMessage:
+		return pulsar.NewMessageID(start.LedgerID, start.EntryID, -1, start.Partition)
+	default:
+		return pulsar.EarliestMessageID()
+	}
+}
+
+// Next reads the next message, blocking until one is available or the context is cancelled.
+func (rc *ReaderClient) Next(ctx context.Context) (pulsar.Message, error) {
+	rc.mu.RLock()
+	if !rc.connected || rc.closed {
+		rc.mu.RUnlock()
+		return nil, fmt.Errorf("reader not connected")
+	}
+	reader := rc.reader
+	rc.mu.RUnlock()
+
+	msg, err := reader.Next(ctx)
+	if err != nil {
+		atomic.AddUint64(&rc.stats.ReadErrors, 1)
+		return nil, fmt.Errorf("failed to read message: %w", err)
+	}
+
+	atomic.AddUint64(&rc.stats.MessagesRead, 1)
+	atomic.AddUint64(&rc.stats.BytesRead, uint64(len(msg.Payload())))
+	return msg, nil
+}
+
+// HasNext reports whether the topic has messages the reader has not read yet.
+// It asks the broker for the last message ID once the reader has passed the last known one.
+func (rc *ReaderClient) HasNext() bool {
+	rc.mu.RLock()
+	if !rc.connected || rc.closed {
+		rc.mu.RUnlock()
+		return false
+	}
+	reader := rc.reader
+	rc.mu.RUnlock()
+
+	return reader.HasNext()
+}
+
+// Topic returns the topic the reader reads
+func (rc *ReaderClient) Topic() string {
+	return rc.topic
+}
+
+// Close closes the reader and releases all resources.
+// This method is safe to call multiple times.
+func (rc *ReaderClient) Close() error {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if rc.closed {
+		return nil
+	}
+
+	rc.closed = true
+	rc.connected = false
+
+	if rc.reader != nil {
+		rc.reader.Close()
+	}
+	if rc.client != nil {
+		rc.client.Close()
+	}
+	return nil
+}
+
+// IsConnected returns the current connection status of the reader.
+func (rc *ReaderClient) IsConnected() bool {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	return rc.connected && !rc.closed
+}
+
+// Stats returns a snapshot of current reader statistics.
+func (rc *ReaderClient) Stats() ReaderStats {
+	return ReaderStats{
+		MessagesRead: atomic.LoadUint64(&rc.stats.MessagesRead),
+		BytesRead:    atomic.LoadUint64(&rc.stats.BytesRead),
+		ReadErrors:   atomic.LoadUint64(&rc.stats.ReadErrors),
+	}
+}
+
+// NewReaderClient creates a reader for the given worker. Workers are spread over the
+// topics in PulsarConfig.TopicNames, worker i reading topic i modulo the topic count.
+func NewReaderClient(cfg *config.Config, readerID int) (*ReaderClient, error) {
+	topics := cfg.Pulsar.TopicNames()
+	topic := topics[readerID%len(topics)]
+	return NewReader(context.Background(), &cfg.Pulsar, &cfg.Consumer, topic, fmt.Sprintf("reader-%d", readerID))
+}
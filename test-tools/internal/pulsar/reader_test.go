@@ -0,0 +1,185 @@
+package pulsar
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/pulsar-local-lab/perf-test/internal/config"
+)
+
+// mockReader implements pulsar.Reader interface for testing
+type mockReader struct {
+	messages []pulsar.Message
+	nextErr  error
+	closed   bool
+}
+
+func (m *mockReader) Topic() string { return "test-topic" }
+func (m *mockReader) Next(ctx context.Context) (pulsar.Message, error) {
+	if m.nextErr != nil {
+		return nil, m.nextErr
+	}
+	if len(m.messages) == 0 {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	msg := m.messages[0]
+	m.messages = m.messages[1:]
+	return msg, nil
+}
+func (m *mockReader) HasNext() bool                               { return len(m.messages) > 0 }
+func (m *mockReader) Close()                                      { m.closed = true }
+func (m *mockReader) Seek(msgID pulsar.MessageID) error           { return nil }
+func (m *mockReader) SeekByTime(t time.Time) error                { return nil }
+func (m *mockReader) GetLastMessageID() (pulsar.MessageID, error) { return nil, nil }
+
+func TestNewReader_ValidationErrors(t *testing.T) {
+	ctx := context.Background()
+	pulsarCfg := &config.PulsarConfig{ServiceURL: "pulsar://localhost:6650", Topic: "test"}
+
+	tests := []struct {
+		name        string
+		pulsarCfg   *config.PulsarConfig
+		consumerCfg *config.ConsumerConfig
+		topic       string
+		readerID    string
+		errContains string
+	}{
+		{"nil pulsar config", nil, &config.ConsumerConfig{}, "test", "test", "pulsar config cannot be nil"},
+		{"nil consumer config", pulsarCfg, nil, "test", "test", "consumer config cannot be nil"},
+		{"empty topic", pulsarCfg, &config.ConsumerConfig{}, "", "test", "topic cannot be empty"},
+		{"empty reader ID", pulsarCfg, &config.ConsumerConfig{}, "test", "", "reader ID cannot be empty"},
+		{"invalid start", pulsarCfg, &config.ConsumerConfig{ReaderStart: "oldest"}, "test", "test", "invalid reader start position"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewReader(ctx, tt.pulsarCfg, tt.consumerCfg, tt.topic, tt.readerID)
+			if err == nil || !contains(err.Error(), tt.errContains) {
+				t.Errorf("NewReader() error = %v, want error containing %s", err, tt.errContains)
+			}
+		})
+	}
+}
+
+func TestNewReader_Cancelled(t *testing.T) {
+	pulsarCfg := &config.PulsarConfig{ServiceURL: "pulsar://localhost:6650", Topic: "test"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := NewReader(ctx, pulsarCfg, &config.ConsumerConfig{}, "test", "test")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("NewReader() error = %v, want context.Canceled", err)
+	}
+
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+	_, err = NewReader(expired, pulsarCfg, &config.ConsumerConfig{}, "test", "test")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("NewReader() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestStartMessageID(t *testing.T) {
+	tests := []struct {
+		name  string
+		start config.ReaderStart
+		want  pulsar.MessageID
+	}{
+		{"earliest", config.ReaderStart{Position: config.ReaderStartEarliest}, pulsar.EarliestMessageID()},
+		{"latest", config.ReaderStart{Position: config.ReaderStartLatest}, pulsar.LatestMessageID()},
+		{"time", config.ReaderStart{Position: config.ReaderStartTime, Time: time.Now()}, pulsar.EarliestMessageID()},
+		{"message", config.ReaderStart{Position: config.ReaderStartMessage, LedgerID: 12, EntryID: 34, Partition: 2},
+			pulsar.NewMessageID(12, 34, -1, 2)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := StartMessageID(tt.start)
+			if got.LedgerID() != tt.want.LedgerID() || got.EntryID() != tt.want.EntryID() || got.PartitionIdx() != tt.want.PartitionIdx() {
+				t.Errorf("StartMessageID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReaderClient_ReaderOptions(t *testing.T) {
+	rc := &ReaderClient{
+		consumerCfg: &config.ConsumerConfig{ReceiverQueueSize: 500},
+		topic:       "test-topic",
+		readerID:    "reader-0",
+	}
+
+	options := rc.readerOptions(config.ReaderStart{Position: config.ReaderStartMessage, LedgerID: 1, EntryID: 2, Partition: -1})
+	if options.Topic != "test-topic" || options.Name != "reader-0" || options.ReceiverQueueSize != 500 {
+		t.Errorf("unexpected options %+v", options)
+	}
+	if !options.StartMessageIDInclusive {
+		t.Error("readers starting at a message ID should include that message")
+	}
+	if options = rc.readerOptions(config.ReaderStart{Position: config.ReaderStartEarliest}); options.StartMessageIDInclusive {
+		t.Error("readers starting at the earliest message should not be inclusive")
+	}
+}
+
+func TestReaderClient_Next(t *testing.T) {
+	t.Run("Next success", func(t *testing.T) {
+		mock := &mockReader{messages: []pulsar.Message{&mockMessage{payload: []byte("hello")}}}
+		rc := &ReaderClient{reader: mock, connected: true}
+
+		if !rc.HasNext() {
+			t.Fatal("HasNext() = false, want true")
+		}
+		msg, err := rc.Next(context.Background())
+		if err != nil || msg == nil {
+			t.Fatalf("Next() = %v, %v, want message", msg, err)
+		}
+		if rc.HasNext() {
+			t.Error("HasNext() = true after reading the last message")
+		}
+
+		stats := rc.Stats()
+		if stats.MessagesRead != 1 || stats.BytesRead != 5 {
+			t.Errorf("Stats = %+v, want 1 message of 5 bytes", stats)
+		}
+	})
+
+	t.Run("Next error", func(t *testing.T) {
+		rc := &ReaderClient{reader: &mockReader{nextErr: errors.New("read failed")}, connected: true}
+
+		if _, err := rc.Next(context.Background()); err == nil || !contains(err.Error(), "failed to read message") {
+			t.Errorf("Next() error = %v, want read failure", err)
+		}
+		if stats := rc.Stats(); stats.ReadErrors != 1 {
+			t.Errorf("Stats.ReadErrors = %d, want 1", stats.ReadErrors)
+		}
+	})
+
+	t.Run("Next when not connected", func(t *testing.T) {
+		rc := &ReaderClient{}
+		if _, err := rc.Next(context.Background()); err == nil {
+			t.Error("Next() error = nil, want error when not connected")
+		}
+		if rc.HasNext() {
+			t.Error("HasNext() = true when not connected")
+		}
+	})
+}
+
+func TestReaderClient_Close(t *testing.T) {
+	mock := &mockReader{}
+	rc := &ReaderClient{reader: mock, connected: true}
+
+	if err := rc.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if !mock.closed || rc.IsConnected() {
+		t.Error("Close() should close the reader and disconnect")
+	}
+	if err := rc.Close(); err != nil {
+		t.Errorf("second Close() error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,222 @@
+package pulsar
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pulsar-local-lab/perf-test/internal/config"
+	"github.com/pulsar-local-lab/perf-test/internal/metrics"
+	"github.com/streamnative/pulsar-admin-go/pkg/utils"
+)
+
+// FetchBrokerStats fetches topic and subscription stats from the broker.
+// Partitioned topics are reported as the aggregate over all partitions.
+func (ac *AdminClient) FetchBrokerStats(topicName *utils.TopicName) (metrics.BrokerStats, error) {
+	metadata, err := ac.admin.Topics().GetMetadata(*topicName)
+	if err != nil {
+		return metrics.BrokerStats{}, fmt.Errorf("failed to get topic metadata: %w", err)
+	}
+
+	if metadata.Partitions > 0 {
+		stats, err := ac.admin.Topics().GetPartitionedStats(*topicName, true)
+		if err != nil {
+			return metrics.BrokerStats{}, fmt.Errorf("failed to get partitioned topic stats: %w", err)
+		}
+
+		// The aggregate has no backlog size, so sum it over the partitions
+		var backlogSize int64
+		for _, partition := range stats.Partitions {
+			backlogSize += partition.BacklogSize
+		}
+
+		return metrics.BrokerStats{
+			Topic:            topicName.String(),
+			Topics:           1,
+			Partitions:       metadata.Partitions,
+			MsgRateIn:        stats.MsgRateIn,
+			MsgRateOut:       stats.MsgRateOut,
+			MsgThroughputIn:  stats.MsgThroughputIn,
+			MsgThroughputOut: stats.MsgThroughputOut,
+			StorageSize:      stats.StorageSize,
+			BacklogSize:      backlogSize,
+			Publishers:       len(stats.Publishers),
+			Subscriptions:    convertSubscriptionStats(stats.Subscriptions),
+		}, nil
+	}
+
+	stats, err := ac.admin.Topics().GetStats(*topicName)
+	if err != nil {
+		return metrics.BrokerStats{}, fmt.Errorf("failed to get topic stats: %w", err)
+	}
+
+	return metrics.BrokerStats{
+		Topic:            topicName.String(),
+		Topics:           1,
+		MsgRateIn:        stats.MsgRateIn,
+		MsgRateOut:       stats.MsgRateOut,
+		MsgThroughputIn:  stats.MsgThroughputIn,
+		MsgThroughputOut: stats.MsgThroughputOut,
+		StorageSize:      stats.StorageSize,
+		BacklogSize:      stats.BacklogSize,
+		Publishers:       len(stats.Publishers),
+		Subscriptions:    convertSubscriptionStats(stats.Subscriptions),
+	}, nil
+}
+
+// convertSubscriptionStats converts admin API subscription stats into a slice sorted by name
+func convertSubscriptionStats(subs map[string]utils.SubscriptionStats) []metrics.SubscriptionStats {
+	result := make([]metrics.SubscriptionStats, 0, len(subs))
+	for name, sub := range subs {
+		result = append(result, metrics.SubscriptionStats{
+			Name:             name,
+			Type:             sub.SubType,
+			MsgBacklog:       sub.MsgBacklog,
+			UnackedMessages:  sub.UnAckedMessages,
+			MsgRateOut:       sub.MsgRateOut,
+			MsgRateRedeliver: sub.MsgRateRedeliver,
+			Consumers:        len(sub.Consumers),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// StatsPoller periodically fetches broker-side stats for the test topics and
+// stores them in a metrics collector so they appear in every snapshot.
+// When a subscription is set, its backlog is also recorded for lag tracking.
+type StatsPoller struct {
+	admin        *AdminClient
+	topics       []*utils.TopicName
+	subscription string
+	interval     time.Duration
+	collector    *metrics.Collector
+
+	mu     sync.Mutex
+	last   metrics.BrokerStats
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewStatsPoller creates a poller for the configured topics using the configured interval.
+// The stats of multi-topic workloads are summed over their topics.
+// subscription may be empty when no subscription backlog should be tracked.
+func NewStatsPoller(cfg *config.Config, subscription string, collector *metrics.Collector) (*StatsPoller, error) {
+	var topicNames []*utils.TopicName
+	for _, topic := range cfg.Pulsar.TopicNames() {
+		topicName, err := utils.GetTopicName(topic)
+		if err != nil {
+			return nil, fmt.Errorf("invalid topic name %s: %w", topic, err)
+		}
+		topicNames = append(topicNames, topicName)
+	}
+	if cfg.Metrics.BrokerStatsInterval <= 0 {
+		return nil, fmt.Errorf("broker stats interval must be positive, got %v", cfg.Metrics.BrokerStatsInterval)
+	}
+
+	admin, err := NewAdminClient(cfg.Pulsar.AdminURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StatsPoller{
+		admin:        admin,
+		topics:       topicNames,
+		subscription: subscription,
+		interval:     time.Duration(cfg.Metrics.BrokerStatsInterval),
+		collector:    collector,
+	}, nil
+}
+
+// Start begins polling in the background until ctx is cancelled or Stop is called.
+// Calling Start on a running poller has no effect.
+func (sp *StatsPoller) Start(ctx context.Context) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	if sp.cancel != nil {
+		return
+	}
+
+	pollCtx, cancel := context.WithCancel(ctx)
+	sp.cancel = cancel
+	sp.done = make(chan struct{})
+
+	go func(done chan struct{}) {
+		defer close(done)
+
+		ticker := time.NewTicker(sp.interval)
+		defer ticker.Stop()
+
+		sp.Poll()
+		for {
+			select {
+			case <-pollCtx.Done():
+				return
+			case <-ticker.C:
+				sp.Poll()
+			}
+		}
+	}(sp.done)
+}
+
+// Stop stops background polling and waits for an in-flight poll to finish
+func (sp *StatsPoller) Stop() {
+	sp.mu.Lock()
+	cancel, done := sp.cancel, sp.done
+	sp.cancel, sp.done = nil, nil
+	sp.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+// Poll fetches stats once and publishes them to the collector.
+// On failure the previous values are kept and the error is recorded in LastError.
+func (sp *StatsPoller) Poll() {
+	// Backlog samples are dated when the poll starts, so a sample never predates the
+	// state it reports
+	polledAt := time.Now()
+	stats, err := sp.fetch()
+
+	sp.mu.Lock()
+	if err != nil {
+		sp.last.LastError = err.Error()
+	} else {
+		stats.UpdatedAt = time.Now()
+		sp.last = stats
+	}
+	latest := sp.last
+	sp.mu.Unlock()
+
+	sp.collector.SetBrokerStats(latest)
+
+	// The subscription only shows up in the stats once a consumer has subscribed
+	if err == nil && sp.subscription != "" {
+		if sub := stats.Subscription(sp.subscription); sub != nil {
+			sp.collector.RecordBacklog(sub.MsgBacklog, polledAt)
+		}
+	}
+}
+
+// fetch fetches the stats of every topic, summed when there are several
+func (sp *StatsPoller) fetch() (metrics.BrokerStats, error) {
+	if len(sp.topics) == 1 {
+		return sp.admin.FetchBrokerStats(sp.topics[0])
+	}
+
+	all := make([]metrics.BrokerStats, 0, len(sp.topics))
+	for _, topic := range sp.topics {
+		stats, err := sp.admin.FetchBrokerStats(topic)
+		if err != nil {
+			return metrics.BrokerStats{}, fmt.Errorf("%s: %w", topic, err)
+		}
+		all = append(all, stats)
+	}
+	return metrics.SumBrokerStats(all), nil
+}
@@ -0,0 +1,238 @@
+package pulsar
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pulsar-local-lab/perf-test/internal/metrics"
+	"github.com/streamnative/pulsar-admin-go/pkg/utils"
+)
+
+func TestAdminClient_FetchBrokerStats(t *testing.T) {
+	client, fake := newTestAdminClient(t)
+	fake.topics["persistent/public/default/plain"] = 0
+	fake.stats["persistent/public/default/plain"] = map[string]interface{}{
+		"msgRateIn":       120.5,
+		"msgRateOut":      80.0,
+		"msgThroughputIn": 1024.0,
+		"storageSize":     4096,
+		"backlogSize":     2048,
+		"publishers":      []map[string]interface{}{{}, {}},
+		"subscriptions": map[string]interface{}{
+			"sub-b": map[string]interface{}{"msgBacklog": 3, "type": "Shared"},
+			"sub-a": map[string]interface{}{
+				"msgBacklog":      40,
+				"unackedMessages": 7,
+				"msgRateOut":      80.0,
+				"type":            "Exclusive",
+				"consumers":       []map[string]interface{}{{}},
+			},
+		},
+	}
+	fake.topics["persistent/public/default/partitioned"] = 2
+	fake.stats["persistent/public/default/partitioned"] = map[string]interface{}{
+		"msgRateIn":   200.0,
+		"storageSize": 8192,
+		"subscriptions": map[string]interface{}{
+			"sub": map[string]interface{}{"msgBacklog": 10},
+		},
+		"partitions": map[string]interface{}{
+			"persistent://public/default/partitioned-partition-0": map[string]interface{}{"backlogSize": 100},
+			"persistent://public/default/partitioned-partition-1": map[string]interface{}{"backlogSize": 300},
+		},
+	}
+
+	t.Run("non-partitioned", func(t *testing.T) {
+		stats, err := client.FetchBrokerStats(mustTopicName(t, "persistent://public/default/plain"))
+		if err != nil {
+			t.Fatalf("FetchBrokerStats() error = %v", err)
+		}
+		if stats.Partitions != 0 || stats.MsgRateIn != 120.5 || stats.MsgRateOut != 80 {
+			t.Errorf("unexpected topic stats: %+v", stats)
+		}
+		if stats.StorageSize != 4096 || stats.BacklogSize != 2048 || stats.Publishers != 2 {
+			t.Errorf("unexpected size stats: %+v", stats)
+		}
+		if len(stats.Subscriptions) != 2 || stats.Subscriptions[0].Name != "sub-a" {
+			t.Fatalf("Subscriptions = %+v, want sub-a and sub-b sorted by name", stats.Subscriptions)
+		}
+		want := metrics.SubscriptionStats{
+			Name: "sub-a", Type: "Exclusive", MsgBacklog: 40, UnackedMessages: 7, MsgRateOut: 80, Consumers: 1,
+		}
+		if stats.Subscriptions[0] != want {
+			t.Errorf("Subscriptions[0] = %+v, want %+v", stats.Subscriptions[0], want)
+		}
+	})
+
+	t.Run("partitioned", func(t *testing.T) {
+		stats, err := client.FetchBrokerStats(mustTopicName(t, "persistent://public/default/partitioned"))
+		if err != nil {
+			t.Fatalf("FetchBrokerStats() error = %v", err)
+		}
+		if stats.Partitions != 2 || stats.MsgRateIn != 200 || stats.StorageSize != 8192 {
+			t.Errorf("unexpected topic stats: %+v", stats)
+		}
+		if stats.BacklogSize != 400 {
+			t.Errorf("BacklogSize = %d, want sum over partitions 400", stats.BacklogSize)
+		}
+		if stats.TotalBacklog() != 10 {
+			t.Errorf("TotalBacklog() = %d, want 10", stats.TotalBacklog())
+		}
+	})
+
+	t.Run("missing topic", func(t *testing.T) {
+		_, err := client.FetchBrokerStats(mustTopicName(t, "persistent://public/default/missing"))
+		if !IsNotFound(err) {
+			t.Errorf("FetchBrokerStats() error = %v, want not found", err)
+		}
+	})
+}
+
+func TestStatsPoller(t *testing.T) {
+	client, fake := newTestAdminClient(t)
+	fake.topics["persistent/public/default/perf"] = 0
+	fake.stats["persistent/public/default/perf"] = map[string]interface{}{"msgRateIn": 50.0}
+
+	collector := metrics.NewCollector([]float64{1, 10, 100})
+	poller := &StatsPoller{
+		admin:     client,
+		topics:    []*utils.TopicName{mustTopicName(t, "persistent://public/default/perf")},
+		interval:  10 * time.Millisecond,
+		collector: collector,
+	}
+
+	poller.Poll()
+	broker := collector.GetSnapshot().Broker
+	if broker == nil || broker.MsgRateIn != 50 || broker.LastError != "" || broker.UpdatedAt.IsZero() {
+		t.Fatalf("Broker after successful poll = %+v", broker)
+	}
+
+	// A failed poll keeps the last values and reports the error
+	fake.mu.Lock()
+	fake.failWith = http.StatusInternalServerError
+	fake.mu.Unlock()
+	poller.Poll()
+	broker = collector.GetSnapshot().Broker
+	if broker.MsgRateIn != 50 || broker.LastError == "" {
+		t.Errorf("Broker after failed poll = %+v, want previous rate and an error", broker)
+	}
+
+	// Background polling picks up recovery and stops cleanly
+	fake.mu.Lock()
+	fake.failWith = 0
+	fake.stats["persistent/public/default/perf"] = map[string]interface{}{"msgRateIn": 75.0}
+	fake.mu.Unlock()
+
+	poller.Start(context.Background())
+	poller.Start(context.Background()) // no-op while running
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if b := collector.GetSnapshot().Broker; b.MsgRateIn == 75 && b.LastError == "" {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	poller.Stop()
+	poller.Stop() // stopping twice is safe
+
+	if broker := collector.GetSnapshot().Broker; broker.MsgRateIn != 75 || broker.LastError != "" {
+		t.Errorf("Broker after background polling = %+v, want recovered stats", broker)
+	}
+}
+
+func TestStatsPollerRecordsSubscriptionBacklog(t *testing.T) {
+	client, fake := newTestAdminClient(t)
+	fake.topics["persistent/public/default/perf"] = 0
+
+	collector := metrics.NewCollector([]float64{1, 10, 100})
+	poller := &StatsPoller{
+		admin:        client,
+		topics:       []*utils.TopicName{mustTopicName(t, "persistent://public/default/perf")},
+		subscription: "perf-sub",
+		interval:     time.Second,
+		collector:    collector,
+	}
+
+	// No consumer has subscribed yet
+	poller.Poll()
+	if lag := collector.GetSnapshot().Lag; lag.HasBacklog {
+		t.Fatalf("Lag = %+v before the subscription exists, want no backlog", lag)
+	}
+
+	fake.stats["persistent/public/default/perf"] = map[string]interface{}{
+		"subscriptions": map[string]interface{}{
+			"other":    map[string]interface{}{"msgBacklog": 999},
+			"perf-sub": map[string]interface{}{"msgBacklog": 250},
+		},
+	}
+	poller.Poll()
+
+	lag := collector.GetSnapshot().Lag
+	if !lag.HasBacklog || lag.Backlog != 250 || lag.MaxBacklog != 250 {
+		t.Errorf("Lag = %+v, want backlog 250 of perf-sub", lag)
+	}
+}
+
+func TestStatsPollerSumsTopics(t *testing.T) {
+	client, fake := newTestAdminClient(t)
+	fake.topics["persistent/public/default/perf-0"] = 0
+	fake.stats["persistent/public/default/perf-0"] = map[string]interface{}{
+		"msgRateIn":  50.0,
+		"publishers": []map[string]interface{}{{}},
+		"subscriptions": map[string]interface{}{
+			"perf-sub": map[string]interface{}{"msgBacklog": 10, "type": "Shared", "consumers": []map[string]interface{}{{}}},
+		},
+	}
+	fake.topics["persistent/public/default/perf-1"] = 2
+	fake.stats["persistent/public/default/perf-1"] = map[string]interface{}{
+		"msgRateIn":  25.0,
+		"publishers": []map[string]interface{}{{}},
+		"subscriptions": map[string]interface{}{
+			"perf-sub": map[string]interface{}{"msgBacklog": 5, "type": "Shared", "consumers": []map[string]interface{}{{}}},
+			"other":    map[string]interface{}{"msgBacklog": 1},
+		},
+	}
+
+	collector := metrics.NewCollector([]float64{1, 10, 100})
+	poller := &StatsPoller{
+		admin: client,
+		topics: []*utils.TopicName{
+			mustTopicName(t, "persistent://public/default/perf-0"),
+			mustTopicName(t, "persistent://public/default/perf-1"),
+		},
+		subscription: "perf-sub",
+		interval:     time.Second,
+		collector:    collector,
+	}
+
+	poller.Poll()
+	snapshot := collector.GetSnapshot()
+	broker := snapshot.Broker
+	if broker == nil || broker.LastError != "" {
+		t.Fatalf("Broker after successful poll = %+v", broker)
+	}
+	if broker.Topics != 2 || broker.Partitions != 2 || broker.MsgRateIn != 75 || broker.Publishers != 2 {
+		t.Errorf("unexpected summed stats: %+v", broker)
+	}
+	want := metrics.SubscriptionStats{Name: "perf-sub", Type: "Shared", MsgBacklog: 15, Consumers: 2}
+	if sub := broker.Subscription("perf-sub"); sub == nil || *sub != want {
+		t.Errorf("Subscription(perf-sub) = %+v, want %+v", sub, want)
+	}
+	if len(broker.Subscriptions) != 2 || broker.Subscriptions[0].Name != "other" {
+		t.Errorf("Subscriptions = %+v, want other and perf-sub sorted by name", broker.Subscriptions)
+	}
+	if lag := snapshot.Lag; lag.Backlog != 15 {
+		t.Errorf("Lag backlog = %d, want 15 summed over topics", lag.Backlog)
+	}
+
+	// One failing topic fails the whole poll rather than reporting a partial sum
+	fake.mu.Lock()
+	delete(fake.topics, "persistent/public/default/perf-1")
+	fake.mu.Unlock()
+	poller.Poll()
+	if broker := collector.GetSnapshot().Broker; broker.MsgRateIn != 75 || broker.LastError == "" {
+		t.Errorf("Broker after failed poll = %+v, want previous sum and an error", broker)
+	}
+}
@@ -3,26 +3,28 @@ package ui
 import (
 	"fmt"
 	"math"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/pulsar-local-lab/perf-test/internal/config"
+	"github.com/pulsar-local-lab/perf-test/internal/generator"
 	"github.com/pulsar-local-lab/perf-test/internal/metrics"
 	"github.com/rivo/tview"
 )
 
 // Color scheme constants for consistent theming
 var (
-	ColorHeader    = tcell.NewRGBColor(0, 255, 255)    // Cyan
-	ColorLabel     = tcell.NewRGBColor(255, 255, 255)  // White
-	ColorGood      = tcell.NewRGBColor(0, 255, 0)      // Green
-	ColorWarning   = tcell.NewRGBColor(255, 255, 0)    // Yellow
-	ColorError     = tcell.NewRGBColor(255, 0, 0)      // Red
-	ColorGraph     = tcell.NewRGBColor(0, 128, 255)    // Blue
-	ColorBorder    = tcell.NewRGBColor(0, 128, 128)    // Dark Cyan
-	ColorHighlight = tcell.NewRGBColor(0, 255, 255)    // Cyan
+	ColorHeader    = tcell.NewRGBColor(0, 255, 255)   // Cyan
+	ColorLabel     = tcell.NewRGBColor(255, 255, 255) // White
+	ColorGood      = tcell.NewRGBColor(0, 255, 0)     // Green
+	ColorWarning   = tcell.NewRGBColor(255, 255, 0)   // Yellow
+	ColorError     = tcell.NewRGBColor(255, 0, 0)     // Red
+	ColorGraph     = tcell.NewRGBColor(0, 128, 255)   // Blue
+	ColorBorder    = tcell.NewRGBColor(0, 128, 128)   // Dark Cyan
+	ColorHighlight = tcell.NewRGBColor(0, 255, 255)   // Cyan
 )
 
 // MetricsPanel displays key performance metrics in a formatted table
@@ -30,6 +32,7 @@ type MetricsPanel struct {
 	*tview.TextView
 	lastSnapshot metrics.Snapshot
 	targetRate   float64
+	readers      bool // workers are readers, which do not acknowledge messages
 }
 
 // NewMetricsPanel creates a new metrics panel
@@ -49,6 +52,12 @@ func NewMetricsPanel(title string, targetRate float64) *MetricsPanel {
 	}
 }
 
+// SetReaders hides acknowledgment metrics for reader workers
+func (m *MetricsPanel) SetReaders(readers bool) *MetricsPanel {
+	m.readers = readers
+	return m
+}
+
 // UpdateProducerMetrics updates the panel with producer metrics
 func (m *MetricsPanel) UpdateProducerMetrics(snapshot metrics.Snapshot) {
 	m.lastSnapshot = snapshot
@@ -67,6 +76,10 @@ func (m *MetricsPanel) UpdateProducerMetrics(snapshot metrics.Snapshot) {
 	fmt.Fprintf(m, " [%s]Target:  [-]%s\n", colorName(ColorLabel), formatRate(m.targetRate))
 	fmt.Fprintf(m, " [%s]Bytes:   [-][%s]%s[-]\n", colorName(ColorLabel), colorName(ColorGood), formatBytes(snapshot.BytesSent))
 	fmt.Fprintf(m, " [%s]Bandwidth:[-][%s]%s[-]\n", colorName(ColorLabel), colorName(ColorGood), formatBandwidth(bandwidth))
+	if sizes := snapshot.MessageSizes; sizes.Varies() {
+		fmt.Fprintf(m, " [%s]Size P50:[-]%s\n", colorName(ColorLabel), formatBytes(uint64(sizes.P50)))
+		fmt.Fprintf(m, " [%s]Size P99:[-]%s (max %s)\n", colorName(ColorLabel), formatBytes(uint64(sizes.P99)), formatBytes(uint64(sizes.Max)))
+	}
 
 	// Latency section
 	fmt.Fprintf(m, "\n[%s]┌─ LATENCY ──────────────────────────┐[-]\n", colorName(ColorHeader))
@@ -76,6 +89,13 @@ func (m *MetricsPanel) UpdateProducerMetrics(snapshot metrics.Snapshot) {
 	fmt.Fprintf(m, " [%s]P999:    [-]%s\n", colorName(ColorLabel), m.formatLatency(snapshot.LatencyStats.P999))
 	fmt.Fprintf(m, " [%s]Min/Max: [-]%.2f / %.2f ms\n", colorName(ColorLabel), snapshot.LatencyStats.Min, snapshot.LatencyStats.Max)
 	fmt.Fprintf(m, " [%s]Mean:    [-]%.2f ms\n", colorName(ColorLabel), snapshot.LatencyStats.Mean)
+
+	// Chunking section (only shown once large payloads are being chunked)
+	if snapshot.Chunked.MessagesSent > 0 {
+		fmt.Fprintf(m, "\n[%s]┌─ CHUNKING ─────────────────────────┐[-]\n", colorName(ColorHeader))
+		fmt.Fprintf(m, " [%s]Chunked: [-][%s]%s[-] msgs\n", colorName(ColorLabel), colorName(ColorGood), formatNumber(snapshot.Chunked.MessagesSent))
+		fmt.Fprintf(m, " [%s]Bytes:   [-][%s]%s[-]\n", colorName(ColorLabel), colorName(ColorGood), formatBytes(snapshot.Chunked.BytesSent))
+	}
 }
 
 // UpdateConsumerMetrics updates the panel with consumer metrics
@@ -90,32 +110,140 @@ func (m *MetricsPanel) UpdateConsumerMetrics(snapshot metrics.Snapshot) {
 
 	// Messages section
 	fmt.Fprintf(m, "[%s]┌─ MESSAGES ─────────────────────────┐[-]\n", colorName(ColorHeader))
+
 	fmt.Fprintf(m, " [%s]Received:[-][%s]%s[-] msgs\n", colorName(ColorLabel), colorName(ColorGood), formatNumber(snapshot.MessagesReceived))
-	fmt.Fprintf(m, " [%s]Acked:   [-][%s]%s[-] msgs\n", colorName(ColorLabel), colorName(ColorGood), formatNumber(snapshot.MessagesAcked))
+	if !m.readers {
+		fmt.Fprintf(m, " [%s]Acked:   [-][%s]%s[-] msgs\n", colorName(ColorLabel), colorName(ColorGood), formatNumber(snapshot.MessagesAcked))
+	}
 	fmt.Fprintf(m, " [%s]Failed:  [-][%s]%s[-] msgs\n", colorName(ColorLabel), m.getFailureColor(snapshot.MessagesFailed), formatNumber(snapshot.MessagesFailed))
 	fmt.Fprintf(m, " [%s]Rate:    [-][%s]%s[-]\n", colorName(ColorLabel), rateColor, formatRate(currentRate))
 	fmt.Fprintf(m, " [%s]Bytes:   [-][%s]%s[-]\n", colorName(ColorLabel), colorName(ColorGood), formatBytes(snapshot.BytesReceived))
 	fmt.Fprintf(m, " [%s]Bandwidth:[-][%s]%s[-]\n", colorName(ColorLabel), colorName(ColorGood), formatBandwidth(bandwidth))
 
 	// Acknowledgment rate
-	ackRate := float64(0)
-	if snapshot.MessagesReceived > 0 {
-		ackRate = float64(snapshot.MessagesAcked) / float64(snapshot.MessagesReceived) * 100
+	if !m.readers {
+		ackRate := float64(0)
+		if snapshot.MessagesReceived > 0 {
+			ackRate = float64(snapshot.MessagesAcked) / float64(snapshot.MessagesReceived) * 100
+		}
+		ackColor := ColorGood
+		if ackRate < 99.0 {
+			ackColor = ColorWarning
+		}
+		if ackRate < 95.0 {
+			ackColor = ColorError
+		}
+		fmt.Fprintf(m, " [%s]Ack Rate:[-][%s]%.2f%%[-]\n", colorName(ColorLabel), colorName(ackColor), ackRate)
 	}
-	ackColor := ColorGood
-	if ackRate < 99.0 {
-		ackColor = ColorWarning
+
+	m.writeCatchUp(snapshot.CatchUp)
+
+	// End-to-end latency section
+	fmt.Fprintf(m, "\n[%s]┌─ E2E LATENCY ──────────────────────┐[-]\n", colorName(ColorHeader))
+	fmt.Fprintf(m, " [%s]P50:     [-]%s\n", colorName(ColorLabel), m.formatLatency(snapshot.E2ELatency.P50))
+	fmt.Fprintf(m, " [%s]P95:     [-]%s\n", colorName(ColorLabel), m.formatLatency(snapshot.E2ELatency.P95))
+	fmt.Fprintf(m, " [%s]P99:     [-]%s\n", colorName(ColorLabel), m.formatLatency(snapshot.E2ELatency.P99))
+
+	// Lag section
+	lag := snapshot.Lag
+	fmt.Fprintf(m, "\n[%s]┌─ LAG ──────────────────────────────┐[-]\n", colorName(ColorHeader))
+	if lag.HasBacklog {
+		fmt.Fprintf(m, " [%s]Backlog: [-][%s]%s[-] msgs\n", colorName(ColorLabel), m.getBacklogColor(lag), formatNumber(uint64(max64(lag.Backlog, 0))))
+		fmt.Fprintf(m, " [%s]Max:     [-]%s msgs\n", colorName(ColorLabel), formatNumber(uint64(max64(lag.MaxBacklog, 0))))
+		fmt.Fprintf(m, " [%s]Drain:   [-]%s\n", colorName(ColorLabel), m.formatDrainTime(lag))
+	} else {
+		fmt.Fprintf(m, " [%s]Backlog: [-]n/a\n", colorName(ColorLabel))
 	}
-	if ackRate < 95.0 {
-		ackColor = ColorError
+	fmt.Fprintf(m, " [%s]Lag:     [-]%s\n", colorName(ColorLabel), formatLag(lag.Lag))
+
+	// Chunking section (only shown once chunked messages arrive)
+	if snapshot.Chunked.MessagesReceived > 0 {
+		fmt.Fprintf(m, "\n[%s]┌─ CHUNKING ─────────────────────────┐[-]\n", colorName(ColorHeader))
+		fmt.Fprintf(m, " [%s]Chunked: [-][%s]%s[-] msgs\n", colorName(ColorLabel), colorName(ColorGood), formatNumber(snapshot.Chunked.MessagesReceived))
+		fmt.Fprintf(m, " [%s]E2E P50: [-]%s\n", colorName(ColorLabel), m.formatLatency(snapshot.Chunked.E2ELatency.P50))
+		fmt.Fprintf(m, " [%s]E2E P99: [-]%s\n", colorName(ColorLabel), m.formatLatency(snapshot.Chunked.E2ELatency.P99))
 	}
-	fmt.Fprintf(m, " [%s]Ack Rate:[-][%s]%.2f%%[-]\n", colorName(ColorLabel), colorName(ackColor), ackRate)
+
+	m.writeChecksums(snapshot.Checksums)
+}
+
+// UpdateE2EMetrics updates the panel with combined producer and consumer metrics
+func (m *MetricsPanel) UpdateE2EMetrics(snapshot metrics.Snapshot) {
+	m.lastSnapshot = snapshot
+	m.Clear()
+
+	sendRate := snapshot.Throughput.SendRate
+	receiveRate := snapshot.Throughput.ReceiveRate
+
+	// Send section
+	fmt.Fprintf(m, "[%s]┌─ SEND ─────────────────────────────┐[-]\n", colorName(ColorHeader))
+	fmt.Fprintf(m, " [%s]Sent:    [-][%s]%s[-] msgs\n", colorName(ColorLabel), colorName(ColorGood), formatNumber(snapshot.MessagesSent))
+	fmt.Fprintf(m, " [%s]Failed:  [-][%s]%s[-] msgs\n", colorName(ColorLabel), m.getFailureColor(snapshot.MessagesFailed), formatNumber(snapshot.MessagesFailed))
+	fmt.Fprintf(m, " [%s]Rate:    [-][%s]%s[-]\n", colorName(ColorLabel), m.getRateColor(sendRate, m.targetRate), formatRate(sendRate))
+	fmt.Fprintf(m, " [%s]Send P99:[-]%s\n", colorName(ColorLabel), m.formatLatency(snapshot.LatencyStats.P99))
+
+	// Receive section, colored by how well consumers keep up with producers
+	fmt.Fprintf(m, "\n[%s]┌─ RECEIVE ──────────────────────────┐[-]\n", colorName(ColorHeader))
+	fmt.Fprintf(m, " [%s]Received:[-][%s]%s[-] msgs\n", colorName(ColorLabel), colorName(ColorGood), formatNumber(snapshot.MessagesReceived))
+	fmt.Fprintf(m, " [%s]Acked:   [-][%s]%s[-] msgs\n", colorName(ColorLabel), colorName(ColorGood), formatNumber(snapshot.MessagesAcked))
+	fmt.Fprintf(m, " [%s]Rate:    [-][%s]%s[-]\n", colorName(ColorLabel), m.getRateColor(receiveRate, sendRate), formatRate(receiveRate))
+	inFlight := int64(snapshot.MessagesSent) - int64(snapshot.MessagesReceived)
+	fmt.Fprintf(m, " [%s]In Flight:[-]%s msgs\n", colorName(ColorLabel), formatNumber(uint64(max64(inFlight, 0))))
 
 	// End-to-end latency section
 	fmt.Fprintf(m, "\n[%s]┌─ E2E LATENCY ──────────────────────┐[-]\n", colorName(ColorHeader))
-	fmt.Fprintf(m, " [%s]P50:     [-]%s\n", colorName(ColorLabel), m.formatLatency(snapshot.LatencyStats.P50))
-	fmt.Fprintf(m, " [%s]P95:     [-]%s\n", colorName(ColorLabel), m.formatLatency(snapshot.LatencyStats.P95))
-	fmt.Fprintf(m, " [%s]P99:     [-]%s\n", colorName(ColorLabel), m.formatLatency(snapshot.LatencyStats.P99))
+	fmt.Fprintf(m, " [%s]P50:     [-]%s\n", colorName(ColorLabel), m.formatLatency(snapshot.E2ELatency.P50))
+	fmt.Fprintf(m, " [%s]P95:     [-]%s\n", colorName(ColorLabel), m.formatLatency(snapshot.E2ELatency.P95))
+	fmt.Fprintf(m, " [%s]P99:     [-]%s\n", colorName(ColorLabel), m.formatLatency(snapshot.E2ELatency.P99))
+	fmt.Fprintf(m, " [%s]Max:     [-]%s\n", colorName(ColorLabel), m.formatLatency(snapshot.E2ELatency.Max))
+
+	// Backlog section
+	lag := snapshot.Lag
+	fmt.Fprintf(m, "\n[%s]┌─ BACKLOG ──────────────────────────┐[-]\n", colorName(ColorHeader))
+	if lag.HasBacklog {
+		fmt.Fprintf(m, " [%s]Backlog: [-][%s]%s[-] msgs\n", colorName(ColorLabel), m.getBacklogColor(lag), formatNumber(uint64(max64(lag.Backlog, 0))))
+		fmt.Fprintf(m, " [%s]Max:     [-]%s msgs\n", colorName(ColorLabel), formatNumber(uint64(max64(lag.MaxBacklog, 0))))
+		fmt.Fprintf(m, " [%s]Drain:   [-]%s\n", colorName(ColorLabel), m.formatDrainTime(lag))
+	} else {
+		fmt.Fprintf(m, " [%s]Backlog: [-]n/a\n", colorName(ColorLabel))
+	}
+
+	m.writeCatchUp(snapshot.CatchUp)
+	m.writeChecksums(snapshot.Checksums)
+}
+
+// writeCatchUp writes the catch-up section of readers, or of consumers draining a backlog
+// after a seek (only shown once one has started)
+func (m *MetricsPanel) writeCatchUp(catchUp metrics.CatchUpStats) {
+	if catchUp.Readers == 0 {
+		return
+	}
+	status := fmt.Sprintf("[%s]%d/%d workers[-]", colorName(ColorWarning), catchUp.CaughtUp, catchUp.Readers)
+	if catchUp.Done() {
+		status = fmt.Sprintf("[%s]done[-]", colorName(ColorGood))
+	}
+	fmt.Fprintf(m, "\n[%s]┌─ CATCH-UP ─────────────────────────┐[-]\n", colorName(ColorHeader))
+	fmt.Fprintf(m, " [%s]Status:  [-]%s\n", colorName(ColorLabel), status)
+	fmt.Fprintf(m, " [%s]Read:    [-]%s msgs (%s)\n", colorName(ColorLabel), formatNumber(catchUp.Messages), formatBytes(catchUp.Bytes))
+	fmt.Fprintf(m, " [%s]Rate:    [-]%s, %s\n", colorName(ColorLabel), formatRate(catchUp.Rate), formatBandwidth(catchUp.Bandwidth))
+	fmt.Fprintf(m, " [%s]Time:    [-]%s\n", colorName(ColorLabel), formatDuration(catchUp.Duration))
+	fmt.Fprintf(m, " [%s]Wait P99:[-]%s\n", colorName(ColorLabel), m.formatLatency(catchUp.ReadWait.P99))
+}
+
+// writeChecksums writes the payload checksum section (only shown once checksummed
+// messages arrive)
+func (m *MetricsPanel) writeChecksums(checksums metrics.ChecksumStats) {
+	if checksums.Verified == 0 {
+		return
+	}
+	corruptColor := ColorGood
+	if checksums.Corrupt > 0 {
+		corruptColor = ColorError
+	}
+	fmt.Fprintf(m, "\n[%s]┌─ CHECKSUMS ────────────────────────┐[-]\n", colorName(ColorHeader))
+	fmt.Fprintf(m, " [%s]Verified:[-]%s msgs\n", colorName(ColorLabel), formatNumber(checksums.Verified))
+	fmt.Fprintf(m, " [%s]Corrupt: [-][%s]%s[-] msgs\n", colorName(ColorLabel), colorName(corruptColor), formatNumber(checksums.Corrupt))
+	fmt.Fprintf(m, " [%s]Verify:  [-]%.1fµs avg, %.1fµs p99\n", colorName(ColorLabel), checksums.VerifyTime.Mean, checksums.VerifyTime.P99)
 }
 
 // getRateColor returns the appropriate color based on current rate vs target
@@ -143,6 +271,29 @@ func (m *MetricsPanel) getFailureColor(failures uint64) string {
 	return colorName(ColorError)
 }
 
+// getBacklogColor returns color based on whether the backlog is draining
+func (m *MetricsPanel) getBacklogColor(lag metrics.LagStats) string {
+	if lag.Backlog == 0 {
+		return colorName(ColorGood)
+	}
+	if _, draining := lag.DrainTime(); draining {
+		return colorName(ColorWarning)
+	}
+	return colorName(ColorError)
+}
+
+// formatDrainTime formats the estimated time to consume the backlog
+func (m *MetricsPanel) formatDrainTime(lag metrics.LagStats) string {
+	if lag.Backlog <= 0 {
+		return fmt.Sprintf("[%s]caught up[-]", colorName(ColorGood))
+	}
+	drain, ok := lag.DrainTime()
+	if !ok {
+		return fmt.Sprintf("[%s]not draining[-]", colorName(ColorError))
+	}
+	return fmt.Sprintf("[%s]~%s[-]", colorName(ColorWarning), formatDuration(drain))
+}
+
 // formatLatency formats latency with color coding
 func (m *MetricsPanel) formatLatency(latency float64) string {
 	color := ColorGood
@@ -157,9 +308,10 @@ func (m *MetricsPanel) formatLatency(latency float64) string {
 // GraphWidget displays an ASCII art time-series graph
 type GraphWidget struct {
 	*tview.TextView
-	dataPoints []float64
-	maxPoints  int
-	targetRate float64
+	dataPoints  []float64
+	maxPoints   int
+	targetRate  float64
+	formatValue func(float64) string
 }
 
 // NewGraphWidget creates a new graph widget
@@ -174,13 +326,20 @@ func NewGraphWidget(title string, maxPoints int, targetRate float64) *GraphWidge
 		SetTitleColor(ColorHeader)
 
 	return &GraphWidget{
-		TextView:   tv,
-		dataPoints: make([]float64, 0, maxPoints),
-		maxPoints:  maxPoints,
-		targetRate: targetRate,
+		TextView:    tv,
+		dataPoints:  make([]float64, 0, maxPoints),
+		maxPoints:   maxPoints,
+		targetRate:  targetRate,
+		formatValue: formatRate,
 	}
 }
 
+// SetValueFormatter sets how the scale label is formatted (message rate by default)
+func (g *GraphWidget) SetValueFormatter(format func(float64) string) *GraphWidget {
+	g.formatValue = format
+	return g
+}
+
 // AddDataPoint adds a new data point to the graph
 func (g *GraphWidget) AddDataPoint(value float64) {
 	g.dataPoints = append(g.dataPoints, value)
@@ -275,12 +434,329 @@ func (g *GraphWidget) Render() {
 	fmt.Fprintf(g, "[-]")
 
 	// Add scale labels
-	fmt.Fprintf(g, "\n  [%s]Max: %s[-]", colorName(ColorLabel), formatRate(maxValue))
+	fmt.Fprintf(g, "\n  [%s]Max: %s[-]", colorName(ColorLabel), g.formatValue(maxValue))
 	if g.targetRate > 0 {
 		fmt.Fprintf(g, " [%s]Target: ─[-]", colorName(ColorWarning))
 	}
 }
 
+// PartitionPanel displays how messages are spread across topic partitions
+type PartitionPanel struct {
+	*tview.TextView
+}
+
+// NewPartitionPanel creates a new partition panel
+func NewPartitionPanel(title string) *PartitionPanel {
+	tv := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true)
+
+	tv.SetBorder(true).
+		SetTitle(fmt.Sprintf(" %s ", title)).
+		SetBorderColor(ColorBorder).
+		SetTitleColor(ColorHeader)
+
+	return &PartitionPanel{TextView: tv}
+}
+
+// UpdateProducerPartitions renders per-partition send counts, rates and send latency
+func (p *PartitionPanel) UpdateProducerPartitions(snapshot metrics.Snapshot) {
+	p.render(snapshot.Partitions, snapshot.SendSkew(),
+		func(ps metrics.PartitionStats) uint64 { return ps.MessagesSent },
+		func(ps metrics.PartitionStats) float64 { return ps.SendRate },
+		func(ps metrics.PartitionStats) metrics.LatencyStats { return ps.SendLatency })
+}
+
+// UpdateConsumerPartitions renders per-partition receive counts, rates and end-to-end latency
+func (p *PartitionPanel) UpdateConsumerPartitions(snapshot metrics.Snapshot) {
+	p.render(snapshot.Partitions, snapshot.ReceiveSkew(),
+		func(ps metrics.PartitionStats) uint64 { return ps.MessagesReceived },
+		func(ps metrics.PartitionStats) float64 { return ps.ReceiveRate },
+		func(ps metrics.PartitionStats) metrics.LatencyStats { return ps.ReceiveLatency })
+}
+
+// render draws the partition table with a heat bar per partition.
+// Bars are colored by how far the partition's count is above the mean.
+func (p *PartitionPanel) render(partitions []metrics.PartitionStats, skew float64,
+	count func(metrics.PartitionStats) uint64, rate func(metrics.PartitionStats) float64,
+	latency func(metrics.PartitionStats) metrics.LatencyStats) {
+	p.Clear()
+
+	if len(partitions) == 0 {
+		fmt.Fprintf(p, "\n  [%s]Waiting for messages...[-]", colorName(ColorLabel))
+		return
+	}
+
+	var total, maxCount uint64
+	for _, ps := range partitions {
+		c := count(ps)
+		total += c
+		if c > maxCount {
+			maxCount = c
+		}
+	}
+	mean := float64(total) / float64(len(partitions))
+
+	fmt.Fprintf(p, " [%s]Partitions:[-] %d  [%s]Skew (max/mean):[-] [%s]%.2fx[-]\n",
+		colorName(ColorLabel), len(partitions),
+		colorName(ColorLabel), colorName(skewColor(skew)), skew)
+	fmt.Fprintf(p, " [%s]%-5s %12s %12s %10s %10s  %s[-]\n",
+		colorName(ColorHeader), "PART", "MSGS", "RATE", "P50", "P99", "SHARE")
+
+	_, _, width, _ := p.GetInnerRect()
+	barWidth := max(10, min(30, width-66))
+
+	for _, ps := range partitions {
+		c := count(ps)
+		share := float64(0)
+		if total > 0 {
+			share = float64(c) / float64(total) * 100
+		}
+		filled := 0
+		if maxCount > 0 {
+			filled = int(float64(c) / float64(maxCount) * float64(barWidth))
+		}
+		ratio := float64(0)
+		if mean > 0 {
+			ratio = float64(c) / mean
+		}
+		fmt.Fprintf(p, " [%s]P%-4d[-] %12s %12s %10s %10s  [%s]%s[-]%s %5.1f%%\n",
+			colorName(ColorLabel), ps.Partition,
+			formatNumber(c),
+			formatRate(rate(ps)),
+			fmt.Sprintf("%.2f ms", latency(ps).P50),
+			fmt.Sprintf("%.2f ms", latency(ps).P99),
+			colorName(skewColor(ratio)), strings.Repeat("█", filled),
+			strings.Repeat("░", barWidth-filled),
+			share)
+	}
+}
+
+// GroupPanel displays receive metrics per value of the grouping message property
+type GroupPanel struct {
+	*tview.TextView
+}
+
+// NewGroupPanel creates a new group panel for the given property
+func NewGroupPanel(property string) *GroupPanel {
+	tv := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true)
+
+	tv.SetBorder(true).
+		SetTitle(fmt.Sprintf(" BY %s ", strings.ToUpper(property))).
+		SetBorderColor(ColorBorder).
+		SetTitleColor(ColorHeader)
+
+	return &GroupPanel{TextView: tv}
+}
+
+// Update renders per-value receive counts, rates and end-to-end latency, busiest first
+func (g *GroupPanel) Update(snapshot metrics.Snapshot) {
+	g.Clear()
+
+	if len(snapshot.Groups) == 0 {
+		fmt.Fprintf(g, "\n  [%s]Waiting for messages...[-]", colorName(ColorLabel))
+		return
+	}
+
+	var total uint64
+	for _, gs := range snapshot.Groups {
+		total += gs.MessagesReceived
+	}
+
+	fmt.Fprintf(g, " [%s]Values:[-] %d\n", colorName(ColorLabel), len(snapshot.Groups))
+	fmt.Fprintf(g, " [%s]%-16s %12s %12s %10s %10s %7s[-]\n",
+		colorName(ColorHeader), "VALUE", "MSGS", "RATE", "P50", "P99", "SHARE")
+	for _, gs := range snapshot.Groups {
+		share := float64(0)
+		if total > 0 {
+			share = float64(gs.MessagesReceived) / float64(total) * 100
+		}
+		fmt.Fprintf(g, " [%s]%-16s[-] %12s %12s %10s %10s %6.1f%%\n",
+			colorName(ColorLabel), truncateString(gs.Value, 16),
+			formatNumber(gs.MessagesReceived),
+			formatRate(gs.ReceiveRate),
+			fmt.Sprintf("%.2f ms", gs.Latency.P50),
+			fmt.Sprintf("%.2f ms", gs.Latency.P99),
+			share)
+	}
+}
+
+// TopicPanel displays how messages are spread across the topics of a multi-topic workload
+type TopicPanel struct {
+	*tview.TextView
+}
+
+// NewTopicPanel creates a new topic panel
+func NewTopicPanel() *TopicPanel {
+	tv := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true)
+
+	tv.SetBorder(true).
+		SetTitle(" TOPICS ").
+		SetBorderColor(ColorBorder).
+		SetTitleColor(ColorHeader)
+
+	return &TopicPanel{TextView: tv}
+}
+
+// UpdateProducerTopics renders per-topic send counts, rates and send latency
+func (t *TopicPanel) UpdateProducerTopics(snapshot metrics.Snapshot) {
+	t.render(snapshot.Topics,
+		func(ts metrics.TopicStats) uint64 { return ts.MessagesSent },
+		func(ts metrics.TopicStats) float64 { return ts.SendRate },
+		func(ts metrics.TopicStats) metrics.LatencyStats { return ts.SendLatency })
+}
+
+// UpdateConsumerTopics renders per-topic receive counts, rates and end-to-end latency
+func (t *TopicPanel) UpdateConsumerTopics(snapshot metrics.Snapshot) {
+	t.render(snapshot.Topics,
+		func(ts metrics.TopicStats) uint64 { return ts.MessagesReceived },
+		func(ts metrics.TopicStats) float64 { return ts.ReceiveRate },
+		func(ts metrics.TopicStats) metrics.LatencyStats { return ts.ReceiveLatency })
+}
+
+// render draws the topic table, busiest first
+func (t *TopicPanel) render(topics []metrics.TopicStats,
+	count func(metrics.TopicStats) uint64, rate func(metrics.TopicStats) float64,
+	latency func(metrics.TopicStats) metrics.LatencyStats) {
+	t.Clear()
+
+	if len(topics) == 0 {
+		fmt.Fprintf(t, "\n  [%s]Waiting for messages...[-]", colorName(ColorLabel))
+		return
+	}
+
+	var total uint64
+	for _, ts := range topics {
+		total += count(ts)
+	}
+
+	fmt.Fprintf(t, " [%s]Topics:[-] %d\n", colorName(ColorLabel), len(topics))
+	fmt.Fprintf(t, " [%s]%-24s %12s %12s %10s %10s %7s[-]\n",
+		colorName(ColorHeader), "TOPIC", "MSGS", "RATE", "P50", "P99", "SHARE")
+	for _, ts := range topics {
+		c := count(ts)
+		share := float64(0)
+		if total > 0 {
+			share = float64(c) / float64(total) * 100
+		}
+		fmt.Fprintf(t, " [%s]%-24s[-] %12s %12s %10s %10s %6.1f%%\n",
+			colorName(ColorLabel), truncateString(shortTopicName(ts.Topic), 24),
+			formatNumber(c),
+			formatRate(rate(ts)),
+			fmt.Sprintf("%.2f ms", latency(ts).P50),
+			fmt.Sprintf("%.2f ms", latency(ts).P99),
+			share)
+	}
+}
+
+// shortTopicName strips the domain, tenant and namespace from a fully qualified topic name
+func shortTopicName(topic string) string {
+	if i := strings.LastIndex(topic, "/"); i >= 0 {
+		return topic[i+1:]
+	}
+	return topic
+}
+
+// skewColor returns the color for a partition load relative to the mean (1.0 = even)
+func skewColor(ratio float64) tcell.Color {
+	switch {
+	case ratio >= 1.5:
+		return ColorError
+	case ratio >= 1.2:
+		return ColorWarning
+	default:
+		return ColorGood
+	}
+}
+
+// BrokerPanel displays topic and subscription stats reported by the broker,
+// next to the client-side numbers of the metrics panel
+type BrokerPanel struct {
+	*tview.TextView
+}
+
+// NewBrokerPanel creates a new broker stats panel
+func NewBrokerPanel(title string) *BrokerPanel {
+	tv := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true)
+
+	tv.SetBorder(true).
+		SetTitle(fmt.Sprintf(" %s ", title)).
+		SetBorderColor(ColorBorder).
+		SetTitleColor(ColorHeader)
+
+	return &BrokerPanel{TextView: tv}
+}
+
+// Update renders the latest broker stats. The subscription used by this tool,
+// if any, is highlighted in the subscription table.
+func (b *BrokerPanel) Update(snapshot metrics.Snapshot, subscription string) {
+	b.Clear()
+
+	stats := snapshot.Broker
+	if stats == nil {
+		fmt.Fprintf(b, "\n  [%s]Waiting for broker stats...[-]", colorName(ColorLabel))
+		return
+	}
+
+	if stats.LastError != "" {
+		fmt.Fprintf(b, " [%s]Poll failed: %s[-]\n", colorName(ColorError), truncateString(stats.LastError, 80))
+	}
+	if stats.UpdatedAt.IsZero() {
+		return
+	}
+
+	if stats.Topics > 1 {
+		fmt.Fprintf(b, " [%s]Summed over %d topics[-]\n", colorName(ColorLabel), stats.Topics)
+	}
+	fmt.Fprintf(b, " [%s]Rate In:  [-]%-14s [%s]Throughput In:  [-]%s\n",
+		colorName(ColorLabel), formatRate(stats.MsgRateIn),
+		colorName(ColorLabel), formatBandwidth(stats.MsgThroughputIn))
+	fmt.Fprintf(b, " [%s]Rate Out: [-]%-14s [%s]Throughput Out: [-]%s\n",
+		colorName(ColorLabel), formatRate(stats.MsgRateOut),
+		colorName(ColorLabel), formatBandwidth(stats.MsgThroughputOut))
+	fmt.Fprintf(b, " [%s]Storage:  [-]%-14s [%s]Backlog Size:   [-]%s\n",
+		colorName(ColorLabel), formatBytes(uint64(max64(stats.StorageSize, 0))),
+		colorName(ColorLabel), formatBytes(uint64(max64(stats.BacklogSize, 0))))
+	fmt.Fprintf(b, " [%s]Producers:[-]%-14d [%s]Updated:        [-]%s ago\n",
+		colorName(ColorLabel), stats.Publishers,
+		colorName(ColorLabel), formatDuration(time.Since(stats.UpdatedAt)))
+	if ratio := snapshot.WireCompressionRatio(); ratio > 0 {
+		fmt.Fprintf(b, " [%s]Wire Ratio:[-]%.3f [%s](broker bytes per message / payload bytes)[-]\n",
+			colorName(ColorLabel), ratio, colorName(ColorLabel))
+	}
+
+	if len(stats.Subscriptions) == 0 {
+		fmt.Fprintf(b, "\n [%s]No subscriptions[-]", colorName(ColorLabel))
+		return
+	}
+
+	fmt.Fprintf(b, "\n [%s]%-24s %-10s %12s %10s %12s %5s[-]\n",
+		colorName(ColorHeader), "SUBSCRIPTION", "TYPE", "BACKLOG", "UNACKED", "RATE OUT", "CONS")
+	for _, sub := range stats.Subscriptions {
+		nameColor := ColorLabel
+		if sub.Name == subscription {
+			nameColor = ColorGood
+		}
+		backlogColor := ColorGood
+		if sub.MsgBacklog > 0 {
+			backlogColor = ColorWarning
+		}
+		fmt.Fprintf(b, " [%s]%-24s[-] %-10s [%s]%12s[-] %10s %12s %5d\n",
+			colorName(nameColor), truncateString(sub.Name, 24),
+			sub.Type,
+			colorName(backlogColor), formatNumber(uint64(max64(sub.MsgBacklog, 0))),
+			formatNumber(uint64(max64(sub.UnackedMessages, 0))),
+			formatRate(sub.MsgRateOut),
+			sub.Consumers)
+	}
+}
+
 // ConfigPanel displays current configuration
 type ConfigPanel struct {
 	*tview.TextView
@@ -313,23 +789,52 @@ func (c *ConfigPanel) Render() {
 
 	fmt.Fprintf(c, "[%s]┌─ CONNECTION ───────────────────────┐[-]\n", colorName(ColorHeader))
 	fmt.Fprintf(c, " [%s]URL:     [-]%s\n", colorName(ColorLabel), truncateString(c.config.Pulsar.ServiceURL, 30))
-	fmt.Fprintf(c, " [%s]Topic:   [-]%s\n", colorName(ColorLabel), truncateString(c.config.Pulsar.Topic, 30))
+	if topics := c.config.Pulsar.TopicNames(); len(topics) > 1 {
+		fmt.Fprintf(c, " [%s]Topics:  [-]%d (%s, ...)\n", colorName(ColorLabel), len(topics), truncateString(topics[0], 20))
+	} else {
+		fmt.Fprintf(c, " [%s]Topic:   [-]%s\n", colorName(ColorLabel), truncateString(topics[0], 30))
+	}
 
 	if c.config.Producer.NumProducers > 0 {
 		fmt.Fprintf(c, "\n[%s]┌─ PRODUCER ─────────────────────────┐[-]\n", colorName(ColorHeader))
 		fmt.Fprintf(c, " [%s]Workers: [-]%d\n", colorName(ColorLabel), c.config.Producer.NumProducers)
 		fmt.Fprintf(c, " [%s]Batch:   [-]%d\n", colorName(ColorLabel), c.config.Producer.BatchingMaxSize)
-		fmt.Fprintf(c, " [%s]MsgSize: [-]%s\n", colorName(ColorLabel), formatBytes(uint64(c.config.Producer.MessageSize)))
-		fmt.Fprintf(c, " [%s]Compress:[-]%s\n", colorName(ColorLabel), c.config.Producer.CompressionType)
+		fmt.Fprintf(c, " [%s]MsgSize: [-]%s\n", colorName(ColorLabel), formatSizeDistribution(&c.config.Producer))
+		if ratio := c.config.Producer.PayloadCompressionRatio; ratio > 0 {
+			fmt.Fprintf(c, " [%s]Compress:[-]%s (payload %.2f)\n", colorName(ColorLabel), c.config.Producer.CompressionType, ratio)
+		} else {
+			fmt.Fprintf(c, " [%s]Compress:[-]%s\n", colorName(ColorLabel), c.config.Producer.CompressionType)
+		}
 		fmt.Fprintf(c, " [%s]Target:  [-]%s\n", colorName(ColorLabel), formatRate(float64(c.config.Performance.TargetThroughput)))
+		if checksum := c.config.Producer.PayloadChecksum; checksum != "" && checksum != generator.ChecksumNone {
+			fmt.Fprintf(c, " [%s]Checksum:[-]%s\n", colorName(ColorLabel), checksum)
+		}
 	}
 
 	if c.config.Consumer.NumConsumers > 0 {
 		fmt.Fprintf(c, "\n[%s]┌─ CONSUMER ─────────────────────────┐[-]\n", colorName(ColorHeader))
 		fmt.Fprintf(c, " [%s]Workers: [-]%d\n", colorName(ColorLabel), c.config.Consumer.NumConsumers)
-		fmt.Fprintf(c, " [%s]Sub:     [-]%s\n", colorName(ColorLabel), truncateString(c.config.Consumer.SubscriptionName, 20))
-		fmt.Fprintf(c, " [%s]Type:    [-]%s\n", colorName(ColorLabel), c.config.Consumer.SubscriptionType)
+		if c.config.Consumer.ReaderMode() {
+			fmt.Fprintf(c, " [%s]Mode:    [-]reader\n", colorName(ColorLabel))
+			fmt.Fprintf(c, " [%s]Start:   [-]%s\n", colorName(ColorLabel), truncateString(c.config.Consumer.ReaderStart, 25))
+		} else {
+			fmt.Fprintf(c, " [%s]Sub:     [-]%s\n", colorName(ColorLabel), truncateString(c.config.Consumer.SubscriptionName, 20))
+			if c.config.Consumer.TopicsPattern != "" {
+				fmt.Fprintf(c, " [%s]Pattern: [-]%s\n", colorName(ColorLabel), truncateString(c.config.Consumer.TopicsPattern, 30))
+			}
+			fmt.Fprintf(c, " [%s]Type:    [-]%s\n", colorName(ColorLabel), c.config.Consumer.SubscriptionType)
+			if c.config.Consumer.BacklogMode() {
+				fmt.Fprintf(c, " [%s]Mode:    [-]backlog\n", colorName(ColorLabel))
+				fmt.Fprintf(c, " [%s]Seek:    [-]%s\n", colorName(ColorLabel), truncateString(c.config.Consumer.SeekTo, 25))
+			}
+		}
 		fmt.Fprintf(c, " [%s]Queue:   [-]%d\n", colorName(ColorLabel), c.config.Consumer.ReceiverQueueSize)
+		if c.config.Consumer.GroupByProperty != "" {
+			fmt.Fprintf(c, " [%s]Group:   [-]%s\n", colorName(ColorLabel), truncateString(c.config.Consumer.GroupByProperty, 20))
+		}
+		if c.config.Consumer.CaptureFile != "" {
+			fmt.Fprintf(c, " [%s]Capture: [-]%s\n", colorName(ColorLabel), truncateString(filepath.Base(c.config.Consumer.CaptureFile), 20))
+		}
 	}
 }
 
@@ -424,6 +929,26 @@ func formatBytes(bytes uint64) string {
 	return fmt.Sprintf("%.2f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
 
+// formatSizeDistribution formats the configured payload size or size distribution for display
+func formatSizeDistribution(p *config.ProducerConfig) string {
+	if p.ReplayFile != "" {
+		return "replay"
+	}
+	if p.HasPayloadTemplate() {
+		return "template"
+	}
+	switch p.SizeDistribution {
+	case config.SizeUniform:
+		return fmt.Sprintf("%s-%s", formatBytes(uint64(p.SizeMin)), formatBytes(uint64(p.SizeMax)))
+	case config.SizeNormal, config.SizeLogNormal:
+		return fmt.Sprintf("%s ~%s", p.SizeDistribution, formatBytes(uint64(p.MessageSize)))
+	case config.SizeWeighted, config.SizeHistogram:
+		return p.SizeDistribution
+	default:
+		return formatBytes(uint64(p.MessageSize))
+	}
+}
+
 // formatRate formats a rate for display
 func formatRate(rate float64) string {
 	if rate < 1000 {
@@ -450,6 +975,17 @@ func formatBandwidth(bytesPerSecond float64) string {
 	return fmt.Sprintf("%.2f GB/s", bytesPerSecond/(unit*unit*unit))
 }
 
+// formatLag formats a consumer lag duration for display
+func formatLag(d time.Duration) string {
+	if d < time.Second {
+		return fmt.Sprintf("%d ms", d.Milliseconds())
+	}
+	if d < time.Minute {
+		return fmt.Sprintf("%.2f s", d.Seconds())
+	}
+	return formatDuration(d)
+}
+
 // formatNumber formats large numbers with commas
 func formatNumber(n uint64) string {
 	str := fmt.Sprintf("%d", n)
@@ -535,6 +1071,14 @@ func max(a, b int) int {
 	return b
 }
 
+// max64 returns the maximum of two int64s
+func max64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
 // minFloat64 returns the minimum of two float64s
 func minFloat64(a, b float64) float64 {
 	return math.Min(a, b)
@@ -663,13 +1207,13 @@ func (lw *LogWindow) GetTextView() *tview.TextView {
 
 // ControlMenuItem represents a controllable parameter
 type ControlMenuItem struct {
-	Label       string
-	Value       string
-	Adjustable  bool
-	Action      func(delta int) // Called when left/right arrow pressed
-	ToggleFunc  func()           // Called when Enter/Space pressed (for buttons)
-	MinValue    int
-	MaxValue    int
+	Label        string
+	Value        string
+	Adjustable   bool
+	Action       func(delta int) // Called when left/right arrow pressed
+	ToggleFunc   func()          // Called when Enter/Space pressed (for buttons)
+	MinValue     int
+	MaxValue     int
 	CurrentValue int
 }
 
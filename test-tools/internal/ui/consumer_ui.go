@@ -3,6 +3,7 @@ package ui
 import (
 	"context"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
@@ -13,21 +14,40 @@ import (
 
 // ConsumerUI manages the consumer terminal UI
 type ConsumerUI struct {
-	app          *tview.Application
-	pool         *worker.Pool
-	ctx          context.Context
-	cancelFunc   context.CancelFunc
-	metricsPanel *MetricsPanel
-	graphWidget  *GraphWidget
-	controlMenu  *ControlMenu
-	statusBar    *StatusBar
-	helpModal    *HelpModal
-	logWindow    *LogWindow
-	logBuffer    *LogBuffer
-	mainLayout   *tview.Flex
-	showingHelp  bool
-	showingLogs  bool
-	config       *config.Config
+	app            *tview.Application
+	pool           *worker.Pool
+	ctx            context.Context
+	cancelFunc     context.CancelFunc
+	metricsPanel   *MetricsPanel
+	graphWidget    *GraphWidget
+	backlogGraph   *GraphWidget
+	lagGraph       *GraphWidget
+	partitionPanel *PartitionPanel
+	groupPanel     *GroupPanel
+	topicPanel     *TopicPanel
+	brokerPanel    *BrokerPanel
+	controlMenu    *ControlMenu
+	statusBar      *StatusBar
+	helpModal      *HelpModal
+	logWindow      *LogWindow
+	logBuffer      *LogBuffer
+	mainLayout     *tview.Flex
+	connInfo       *tview.TextView
+	showingHelp    bool
+	showingLogs    bool
+	config         *config.Config
+	seekBack       int // index into seekBackOptions
+}
+
+// seekBackOptions are the positions the subscription can be moved back to from the UI
+var seekBackOptions = []string{"1m", "5m", "15m", "1h", "6h", "earliest"}
+
+// subscriptionTypes are the subscription types the UI cycles through
+var subscriptionTypes = []string{
+	config.SubscriptionExclusive,
+	config.SubscriptionShared,
+	config.SubscriptionFailover,
+	config.SubscriptionKeyShared,
 }
 
 // NewConsumerUI creates a new consumer UI
@@ -44,20 +64,24 @@ func NewConsumerUI(ctx context.Context, pool *worker.Pool) *ConsumerUI {
 	// Create UI components
 	metricsPanel := NewMetricsPanel("METRICS", targetRate)
 	graphWidget := NewGraphWidget("CONSUMPTION RATE", 60, targetRate)
+	lagGraph := NewGraphWidget("LAG (PUBLISH → RECEIVE)", 60, 0).SetValueFormatter(func(ms float64) string {
+		return formatLag(time.Duration(ms * float64(time.Millisecond)))
+	})
 	statusBar := NewStatusBar()
 	controlMenu := NewControlMenu("CONTROLS")
 
 	// Create help modal
 	shortcuts := map[string]string{
-		"Q / Ctrl+C":    "Quit application",
-		"↑/↓ Arrows":    "Navigate controls",
-		"←/→ Arrows":    "Adjust values",
-		"Enter/Space":   "Activate button",
-		"P":             "Pause/Resume",
-		"R":             "Reset metrics",
-		"L":             "Show/hide logs",
-		"C":             "Clear logs (when visible)",
-		"H / ?":         "Show/hide help",
+		"Q / Ctrl+C":  "Quit application",
+		"↑/↓ Arrows":  "Navigate controls",
+		"←/→ Arrows":  "Adjust values",
+		"Enter/Space": "Activate button",
+		"P":           "Pause/Resume",
+		"R":           "Reset metrics",
+		"S":           "Seek back (see Seek Back control)",
+		"L":           "Show/hide logs",
+		"C":           "Clear logs (when visible)",
+		"H / ?":       "Show/hide help",
 	}
 	helpModal := NewHelpModal(shortcuts)
 
@@ -68,6 +92,7 @@ func NewConsumerUI(ctx context.Context, pool *worker.Pool) *ConsumerUI {
 		cancelFunc:   cancel,
 		metricsPanel: metricsPanel,
 		graphWidget:  graphWidget,
+		lagGraph:     lagGraph,
 		controlMenu:  controlMenu,
 		statusBar:    statusBar,
 		helpModal:    helpModal,
@@ -75,6 +100,25 @@ func NewConsumerUI(ctx context.Context, pool *worker.Pool) *ConsumerUI {
 		config:       cfg,
 	}
 
+	if cfg != nil {
+		metricsPanel.SetReaders(cfg.Consumer.ReaderMode())
+	}
+	if cfg != nil && cfg.TrackPartitions() {
+		ui.partitionPanel = NewPartitionPanel("PARTITIONS")
+	}
+	if cfg != nil && cfg.Consumer.GroupByProperty != "" {
+		ui.groupPanel = NewGroupPanel(cfg.Consumer.GroupByProperty)
+	}
+	if cfg != nil && cfg.MultiTopic() {
+		ui.topicPanel = NewTopicPanel()
+	}
+	if cfg != nil && cfg.Metrics.BrokerStatsInterval > 0 {
+		ui.brokerPanel = NewBrokerPanel("BROKER STATS")
+		ui.backlogGraph = NewGraphWidget("SUBSCRIPTION BACKLOG", 60, 0).SetValueFormatter(func(v float64) string {
+			return formatNumber(uint64(v)) + " msgs"
+		})
+	}
+
 	ui.setupControlMenu()
 	ui.buildLayout()
 	return ui
@@ -96,6 +140,38 @@ func (ui *ConsumerUI) setupControlMenu() {
 		},
 	})
 
+	// Receiver Queue Size control (requires restart to take effect)
+	ui.controlMenu.AddItem(&ControlMenuItem{
+		Label:      "Queue Size*",
+		Value:      fmt.Sprintf("%d", ui.config.Consumer.ReceiverQueueSize),
+		Adjustable: true,
+		Action: func(delta int) {
+			ui.adjustReceiverQueueSize(delta)
+		},
+	})
+
+	// Subscription Type control (requires restart to take effect; readers have none)
+	if !ui.config.Consumer.ReaderMode() {
+		ui.controlMenu.AddItem(&ControlMenuItem{
+			Label:      "Sub Type*",
+			Value:      ui.config.Consumer.SubscriptionType,
+			Adjustable: true,
+			Action: func(delta int) {
+				ui.adjustSubscriptionType(delta)
+			},
+		})
+	}
+
+	// Restart Workers button (applies settings marked with *)
+	ui.controlMenu.AddItem(&ControlMenuItem{
+		Label:      "Restart Workers",
+		Value:      "",
+		Adjustable: false,
+		ToggleFunc: func() {
+			ui.restartWorkers()
+		},
+	})
+
 	// Pause/Resume button
 	ui.controlMenu.AddItem(&ControlMenuItem{
 		Label:      "Pause/Resume",
@@ -115,6 +191,24 @@ func (ui *ConsumerUI) setupControlMenu() {
 			ui.resetMetrics()
 		},
 	})
+
+	// Seek control: ←/→ picks how far back, Enter moves the subscription there
+	if !ui.config.Consumer.ReaderMode() {
+		ui.seekBack = 1
+		seekItem := &ControlMenuItem{
+			Label:      "Seek Back",
+			Value:      seekBackOptions[ui.seekBack],
+			Adjustable: true,
+		}
+		seekItem.Action = func(delta int) {
+			ui.seekBack = (ui.seekBack + delta + len(seekBackOptions)) % len(seekBackOptions)
+			seekItem.Value = seekBackOptions[ui.seekBack]
+		}
+		seekItem.ToggleFunc = func() {
+			ui.seek(seekBackOptions[ui.seekBack])
+		}
+		ui.controlMenu.AddItem(seekItem)
+	}
 }
 
 // buildLayout constructs the UI layout
@@ -126,14 +220,10 @@ func (ui *ConsumerUI) buildLayout() {
 		SetText("[cyan::b]█▓▒░ PULSAR CONSUMER PERFORMANCE TEST ░▒▓█[-:-:-]")
 
 	// Connection info
-	cfg := ui.config
-	connInfo := tview.NewTextView().
+	ui.connInfo = tview.NewTextView().
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignCenter)
-	fmt.Fprintf(connInfo, "[darkcyan]Connection:[-] %s  [darkcyan]│[-]  [darkcyan]Subscription:[-] %s ([darkcyan]%s[-])",
-		truncateString(cfg.Pulsar.ServiceURL, 35),
-		truncateString(cfg.Consumer.SubscriptionName, 25),
-		cfg.Consumer.SubscriptionType)
+	ui.updateConnInfo()
 
 	// Top section: metrics and graph side by side
 	topSection := tview.NewFlex().
@@ -143,9 +233,35 @@ func (ui *ConsumerUI) buildLayout() {
 	// Right content area (metrics and graph)
 	rightContent := tview.NewFlex().SetDirection(tview.FlexRow).
 		AddItem(title, 1, 0, false).
-		AddItem(connInfo, 1, 0, false).
+		AddItem(ui.connInfo, 1, 0, false).
 		AddItem(tview.NewBox().SetBorder(false), 1, 0, false). // Spacer
-		AddItem(topSection, 0, 1, false)
+		AddItem(topSection, 0, 2, false)
+
+	// Backlog and lag over time below metrics
+	lagSection := tview.NewFlex()
+	if ui.backlogGraph != nil {
+		lagSection.AddItem(ui.backlogGraph, 0, 1, false)
+	}
+	lagSection.AddItem(ui.lagGraph, 0, 1, false)
+	rightContent.AddItem(lagSection, 0, 1, false)
+
+	// Partition and topic distribution, property groups and broker-side stats below metrics
+	if ui.partitionPanel != nil || ui.topicPanel != nil || ui.groupPanel != nil || ui.brokerPanel != nil {
+		bottomSection := tview.NewFlex()
+		if ui.partitionPanel != nil {
+			bottomSection.AddItem(ui.partitionPanel, 0, 1, false)
+		}
+		if ui.topicPanel != nil {
+			bottomSection.AddItem(ui.topicPanel, 0, 1, false)
+		}
+		if ui.groupPanel != nil {
+			bottomSection.AddItem(ui.groupPanel, 0, 1, false)
+		}
+		if ui.brokerPanel != nil {
+			bottomSection.AddItem(ui.brokerPanel, 0, 1, false)
+		}
+		rightContent.AddItem(bottomSection, 0, 1, false)
+	}
 
 	// Main content with control menu on left
 	mainContent := tview.NewFlex().
@@ -161,6 +277,16 @@ func (ui *ConsumerUI) buildLayout() {
 	ui.mainLayout.SetInputCapture(ui.handleInput)
 }
 
+// updateConnInfo shows the connection and the current subscription type
+func (ui *ConsumerUI) updateConnInfo() {
+	cfg := ui.pool.GetConfig()
+	ui.connInfo.Clear()
+	fmt.Fprintf(ui.connInfo, "[darkcyan]Connection:[-] %s  [darkcyan]│[-]  [darkcyan]Subscription:[-] %s ([darkcyan]%s[-])",
+		truncateString(cfg.Pulsar.ServiceURL, 35),
+		truncateString(cfg.Consumer.SubscriptionName, 25),
+		cfg.Consumer.SubscriptionType)
+}
+
 // handleInput handles keyboard input
 func (ui *ConsumerUI) handleInput(event *tcell.EventKey) *tcell.EventKey {
 	// If help is showing, let modal handle input
@@ -210,6 +336,11 @@ func (ui *ConsumerUI) handleInput(event *tcell.EventKey) *tcell.EventKey {
 	case 'r', 'R':
 		ui.resetMetrics()
 		return nil
+	case 's', 'S':
+		if !ui.config.Consumer.ReaderMode() {
+			ui.seek(seekBackOptions[ui.seekBack])
+		}
+		return nil
 	case 'l', 'L':
 		ui.toggleLogs()
 		return nil
@@ -245,10 +376,27 @@ func (ui *ConsumerUI) resetMetrics() {
 	ui.graphWidget.dataPoints = ui.graphWidget.dataPoints[:0]
 }
 
+// seek moves the subscription back to a position given like --consumer.seek-to, in the
+// background since seeking waits for the broker
+func (ui *ConsumerUI) seek(position string) {
+	start, err := config.ParseSeekPosition(position, time.Now())
+	if err != nil {
+		log.Printf("Invalid seek position %q: %v", position, err)
+		return
+	}
+	go func() {
+		if err := ui.pool.Seek(start); err != nil {
+			log.Printf("Seek to %s failed: %v", position, err)
+			return
+		}
+		log.Printf("Subscription moved back to %s, draining the replayed backlog", position)
+	}()
+}
+
 // addWorker adds a new worker to the pool
 func (ui *ConsumerUI) addWorker() {
 	err := ui.pool.AddWorker(ui.ctx, func(id int) (worker.Worker, error) {
-		return worker.NewConsumerWorker(id, ui.config, ui.pool.GetMetrics())
+		return worker.NewReceiveWorker(id, ui.config, ui.pool.GetMetrics())
 	})
 	if err != nil {
 		// Silently handle error - can't log during TUI
@@ -267,13 +415,74 @@ func (ui *ConsumerUI) removeWorker() {
 
 // updateControlMenu updates the control menu display
 func (ui *ConsumerUI) updateControlMenu() {
+	cfg := ui.pool.GetConfig()
+
 	// Update values in menu items
-	if len(ui.controlMenu.items) > 0 {
+	// Order: Workers, Queue Size, Sub Type (subscriptions only), Restart, Pause, Reset, Seek Back
+	if len(ui.controlMenu.items) >= 2 {
 		ui.controlMenu.items[0].Value = fmt.Sprintf("%d", ui.pool.WorkerCount())
+		ui.controlMenu.items[1].Value = fmt.Sprintf("%d", cfg.Consumer.ReceiverQueueSize)
+	}
+	if len(ui.controlMenu.items) >= 3 && !cfg.Consumer.ReaderMode() {
+		ui.controlMenu.items[2].Value = cfg.Consumer.SubscriptionType
 	}
 	ui.controlMenu.Render()
 }
 
+// adjustReceiverQueueSize adjusts the receiver queue size
+func (ui *ConsumerUI) adjustReceiverQueueSize(delta int) {
+	cfg := ui.pool.GetConfig()
+	current := cfg.Consumer.ReceiverQueueSize
+
+	// Adjust in increments based on current value
+	var increment int
+	if current < 100 {
+		increment = 10
+	} else if current < 1000 {
+		increment = 100
+	} else if current < 10000 {
+		increment = 1000
+	} else {
+		increment = 10000
+	}
+
+	newSize := current + (delta * increment)
+	if newSize < 10 {
+		newSize = 10
+	}
+	if newSize > 100000 {
+		newSize = 100000
+	}
+
+	ui.pool.UpdateReceiverQueueSize(newSize)
+}
+
+// adjustSubscriptionType cycles through subscription types
+func (ui *ConsumerUI) adjustSubscriptionType(delta int) {
+	cfg := ui.pool.GetConfig()
+
+	// Find current index
+	currentIdx := 0
+	for i, st := range subscriptionTypes {
+		if st == cfg.Consumer.SubscriptionType {
+			currentIdx = i
+			break
+		}
+	}
+
+	newIdx := (currentIdx + delta + len(subscriptionTypes)) % len(subscriptionTypes)
+	ui.pool.UpdateSubscriptionType(subscriptionTypes[newIdx])
+}
+
+// restartWorkers restarts all workers to apply the settings marked with *. Consumers
+// disconnect and resubscribe, so unacknowledged messages are redelivered.
+func (ui *ConsumerUI) restartWorkers() {
+	if err := ui.pool.RestartWorkers(ui.ctx); err != nil {
+		log.Printf("Restarting consumers failed: %v", err)
+	}
+	ui.updateConnInfo()
+}
+
 // showHelp displays the help modal
 func (ui *ConsumerUI) showHelp() {
 	ui.showingHelp = true
@@ -332,9 +541,9 @@ func (ui *ConsumerUI) hideLogs() {
 
 // shutdown stops the UI and worker pool
 func (ui *ConsumerUI) shutdown() {
-	ui.app.Stop()       // Stop TUI first to restore terminal
-	ui.cancelFunc()     // Cancel context to signal workers
-	ui.pool.Stop()      // Stop workers (may take time, but terminal is restored)
+	ui.app.Stop()   // Stop TUI first to restore terminal
+	ui.cancelFunc() // Cancel context to signal workers
+	ui.pool.Stop()  // Stop workers (may take time, but terminal is restored)
 }
 
 // updateLoop runs the UI update loop
@@ -345,6 +554,8 @@ func (ui *ConsumerUI) updateLoop() {
 	for {
 		select {
 		case <-ui.ctx.Done():
+			// Cancelled by a signal or a finished scenario: close the UI so Run returns
+			ui.app.Stop()
 			return
 		case <-ticker.C:
 			snapshot := ui.pool.GetMetrics().GetSnapshot()
@@ -359,6 +570,26 @@ func (ui *ConsumerUI) updateLoop() {
 				// Update graph with current rate
 				ui.graphWidget.AddDataPoint(snapshot.Throughput.ReceiveRate)
 
+				// Update backlog and lag graphs
+				if ui.backlogGraph != nil && snapshot.Lag.HasBacklog {
+					ui.backlogGraph.AddDataPoint(float64(snapshot.Lag.Backlog))
+				}
+				ui.lagGraph.AddDataPoint(float64(snapshot.Lag.Lag.Milliseconds()))
+
+				// Update partition distribution
+				if ui.partitionPanel != nil {
+					ui.partitionPanel.UpdateConsumerPartitions(snapshot)
+				}
+				if ui.topicPanel != nil {
+					ui.topicPanel.UpdateConsumerTopics(snapshot)
+				}
+				if ui.groupPanel != nil {
+					ui.groupPanel.Update(snapshot)
+				}
+				if ui.brokerPanel != nil {
+					ui.brokerPanel.Update(snapshot, ui.config.Consumer.SubscriptionName)
+				}
+
 				// Update status bar
 				shortcuts := "↑↓←→ Navigate  [Q]uit  [P]ause  [R]eset  [L]ogs  [H]elp"
 				ui.statusBar.Update(
@@ -0,0 +1,508 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/pulsar-local-lab/perf-test/internal/config"
+	"github.com/pulsar-local-lab/perf-test/internal/worker"
+	"github.com/rivo/tview"
+)
+
+// E2EUI represents the terminal UI for the combined end-to-end test, showing a producer
+// pool and a consumer pool that share one metrics collector
+type E2EUI struct {
+	app          *tview.Application
+	producers    *worker.Pool
+	consumers    *worker.Pool
+	ctx          context.Context
+	cancelFunc   context.CancelFunc
+	metricsPanel *MetricsPanel
+	sendGraph    *GraphWidget
+	receiveGraph *GraphWidget
+	backlogGraph *GraphWidget
+	latencyGraph *GraphWidget
+	topicPanel   *TopicPanel
+	brokerPanel  *BrokerPanel
+	controlMenu  *ControlMenu
+	statusBar    *StatusBar
+	helpModal    *HelpModal
+	logWindow    *LogWindow
+	logBuffer    *LogBuffer
+	mainLayout   *tview.Flex
+	showingHelp  bool
+	showingLogs  bool
+	config       *config.Config
+}
+
+// NewE2EUI creates a new end-to-end UI
+func NewE2EUI(ctx context.Context, producers, consumers *worker.Pool) *E2EUI {
+	cfg := producers.GetConfig()
+	targetRate := float64(cfg.Performance.TargetThroughput)
+
+	app := tview.NewApplication()
+	ctxWithCancel, cancel := context.WithCancel(ctx)
+
+	// Create UI components
+	metricsPanel := NewMetricsPanel("METRICS", targetRate)
+	sendGraph := NewGraphWidget("SEND RATE", 60, targetRate)
+	receiveGraph := NewGraphWidget("RECEIVE RATE", 60, targetRate)
+	latencyGraph := NewGraphWidget("E2E LATENCY (LATEST)", 60, 0).SetValueFormatter(func(ms float64) string {
+		return formatLag(time.Duration(ms * float64(time.Millisecond)))
+	})
+	statusBar := NewStatusBar()
+	controlMenu := NewControlMenu("CONTROLS")
+
+	// Create help modal
+	shortcuts := map[string]string{
+		"Q / Ctrl+C":  "Quit application",
+		"↑/↓ Arrows":  "Navigate controls",
+		"←/→ Arrows":  "Adjust values",
+		"Enter/Space": "Activate button",
+		"P":           "Pause/Resume producers",
+		"R":           "Reset metrics",
+		"L":           "Show/hide logs",
+		"C":           "Clear logs (when visible)",
+		"H / ?":       "Show/hide help",
+	}
+	helpModal := NewHelpModal(shortcuts)
+
+	ui := &E2EUI{
+		app:          app,
+		producers:    producers,
+		consumers:    consumers,
+		ctx:          ctxWithCancel,
+		cancelFunc:   cancel,
+		metricsPanel: metricsPanel,
+		sendGraph:    sendGraph,
+		receiveGraph: receiveGraph,
+		latencyGraph: latencyGraph,
+		controlMenu:  controlMenu,
+		statusBar:    statusBar,
+		helpModal:    helpModal,
+		showingHelp:  false,
+		config:       cfg,
+	}
+
+	if cfg.MultiTopic() {
+		ui.topicPanel = NewTopicPanel()
+	}
+	if cfg.Metrics.BrokerStatsInterval > 0 {
+		ui.brokerPanel = NewBrokerPanel("BROKER STATS")
+		ui.backlogGraph = NewGraphWidget("SUBSCRIPTION BACKLOG", 60, 0).SetValueFormatter(func(v float64) string {
+			return formatNumber(uint64(v)) + " msgs"
+		})
+	}
+
+	ui.setupControlMenu()
+	ui.buildLayout()
+	return ui
+}
+
+// setupControlMenu configures the control menu items
+func (ui *E2EUI) setupControlMenu() {
+	// Producer workers control
+	ui.controlMenu.AddItem(&ControlMenuItem{
+		Label:      "Producers",
+		Value:      fmt.Sprintf("%d", ui.producers.WorkerCount()),
+		Adjustable: true,
+		Action: func(delta int) {
+			if delta > 0 {
+				ui.addProducer()
+			} else {
+				ui.removeWorker(ui.producers)
+			}
+		},
+	})
+
+	// Consumer workers control
+	ui.controlMenu.AddItem(&ControlMenuItem{
+		Label:      "Consumers",
+		Value:      fmt.Sprintf("%d", ui.consumers.WorkerCount()),
+		Adjustable: true,
+		Action: func(delta int) {
+			if delta > 0 {
+				ui.addConsumer()
+			} else {
+				ui.removeWorker(ui.consumers)
+			}
+		},
+	})
+
+	// Target Rate control (msg/s, 0 = unlimited)
+	ui.controlMenu.AddItem(&ControlMenuItem{
+		Label:      "Target Rate",
+		Value:      formatTargetRate(ui.config.Performance.TargetThroughput),
+		Adjustable: true,
+		Action: func(delta int) {
+			ui.producers.UpdateTargetRate(nextTargetRate(ui.producers.GetConfig().Performance.TargetThroughput, delta))
+		},
+	})
+
+	// Pause/Resume producers so the backlog can be watched draining
+	ui.controlMenu.AddItem(&ControlMenuItem{
+		Label:      "Pause/Resume",
+		Value:      "",
+		Adjustable: false,
+		ToggleFunc: func() {
+			ui.togglePause()
+		},
+	})
+
+	// Reset metrics button
+	ui.controlMenu.AddItem(&ControlMenuItem{
+		Label:      "Reset Metrics",
+		Value:      "",
+		Adjustable: false,
+		ToggleFunc: func() {
+			ui.resetMetrics()
+		},
+	})
+}
+
+// buildLayout constructs the UI layout
+func (ui *E2EUI) buildLayout() {
+	// Title header
+	title := tview.NewTextView().
+		SetTextAlign(tview.AlignCenter).
+		SetDynamicColors(true).
+		SetText("[cyan::b]█▓▒░ PULSAR END-TO-END PERFORMANCE TEST ░▒▓█[-:-:-]")
+
+	// Connection info
+	cfg := ui.config
+	connInfo := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	fmt.Fprintf(connInfo, "[darkcyan]Topic:[-] %s  [darkcyan]│[-]  [darkcyan]Subscription:[-] %s ([darkcyan]%s[-])",
+		truncateString(cfg.Pulsar.Topic, 50),
+		truncateString(cfg.Consumer.SubscriptionName, 25),
+		cfg.Consumer.SubscriptionType)
+
+	// Send and receive rate graphs stacked next to the metrics
+	rateGraphs := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(ui.sendGraph, 0, 1, false).
+		AddItem(ui.receiveGraph, 0, 1, false)
+
+	topSection := tview.NewFlex().
+		AddItem(ui.metricsPanel, 0, 1, false).
+		AddItem(rateGraphs, 0, 2, false)
+
+	// Backlog and latency over time
+	lagSection := tview.NewFlex()
+	if ui.backlogGraph != nil {
+		lagSection.AddItem(ui.backlogGraph, 0, 1, false)
+	}
+	lagSection.AddItem(ui.latencyGraph, 0, 1, false)
+
+	// Right content area
+	rightContent := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(title, 1, 0, false).
+		AddItem(connInfo, 1, 0, false).
+		AddItem(tview.NewBox().SetBorder(false), 1, 0, false). // Spacer
+		AddItem(topSection, 0, 2, false).
+		AddItem(lagSection, 0, 1, false)
+
+	// Topic distribution and broker-side stats below latency
+	if ui.topicPanel != nil || ui.brokerPanel != nil {
+		bottomSection := tview.NewFlex()
+		if ui.topicPanel != nil {
+			bottomSection.AddItem(ui.topicPanel, 0, 1, false)
+		}
+		if ui.brokerPanel != nil {
+			bottomSection.AddItem(ui.brokerPanel, 0, 1, false)
+		}
+		rightContent.AddItem(bottomSection, 0, 1, false)
+	}
+
+	// Main content with control menu on left
+	mainContent := tview.NewFlex().
+		AddItem(ui.controlMenu, 40, 0, false).
+		AddItem(rightContent, 0, 1, false)
+
+	// Main layout with status bar at bottom
+	ui.mainLayout = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(mainContent, 0, 1, false).
+		AddItem(ui.statusBar, 1, 0, false)
+
+	// Set up input handling
+	ui.mainLayout.SetInputCapture(ui.handleInput)
+}
+
+// handleInput handles keyboard input
+func (ui *E2EUI) handleInput(event *tcell.EventKey) *tcell.EventKey {
+	// If help is showing, let modal handle input
+	if ui.showingHelp {
+		if event.Key() == tcell.KeyEscape || event.Rune() == 'h' || event.Rune() == 'H' || event.Rune() == '?' {
+			ui.hideHelp()
+			return nil
+		}
+		return event
+	}
+
+	// Handle key events
+	switch event.Key() {
+	case tcell.KeyCtrlC, tcell.KeyEscape:
+		ui.shutdown()
+		return nil
+	case tcell.KeyUp:
+		ui.controlMenu.MoveSelection(-1)
+		ui.updateControlMenu()
+		return nil
+	case tcell.KeyDown:
+		ui.controlMenu.MoveSelection(1)
+		ui.updateControlMenu()
+		return nil
+	case tcell.KeyLeft:
+		ui.controlMenu.AdjustValue(-1)
+		ui.updateControlMenu()
+		return nil
+	case tcell.KeyRight:
+		ui.controlMenu.AdjustValue(1)
+		ui.updateControlMenu()
+		return nil
+	case tcell.KeyEnter:
+		ui.controlMenu.ActivateSelected()
+		ui.updateControlMenu()
+		return nil
+	}
+
+	// Handle rune events
+	switch event.Rune() {
+	case 'q', 'Q':
+		ui.shutdown()
+		return nil
+	case 'p', 'P':
+		ui.togglePause()
+		return nil
+	case 'r', 'R':
+		ui.resetMetrics()
+		return nil
+	case 'l', 'L':
+		ui.toggleLogs()
+		return nil
+	case 'c', 'C':
+		if ui.showingLogs {
+			ui.logBuffer.Clear()
+		}
+		return nil
+	case ' ': // Space bar
+		ui.controlMenu.ActivateSelected()
+		ui.updateControlMenu()
+		return nil
+	case 'h', 'H', '?':
+		ui.showHelp()
+		return nil
+	}
+
+	return event
+}
+
+// togglePause pauses or resumes the producers; consumers keep draining the backlog
+func (ui *E2EUI) togglePause() {
+	if ui.producers.IsPaused() {
+		ui.producers.Resume()
+	} else {
+		ui.producers.Pause()
+	}
+}
+
+// resetMetrics resets the shared metrics and all graphs
+func (ui *E2EUI) resetMetrics() {
+	ui.producers.GetMetrics().Reset()
+	for _, graph := range []*GraphWidget{ui.sendGraph, ui.receiveGraph, ui.backlogGraph, ui.latencyGraph} {
+		if graph != nil {
+			graph.dataPoints = graph.dataPoints[:0]
+		}
+	}
+}
+
+// addProducer adds a new producer worker
+func (ui *E2EUI) addProducer() {
+	err := ui.producers.AddWorker(ui.ctx, func(id int) (worker.Worker, error) {
+		return worker.NewProducerWorker(id, ui.config, ui.producers.GetMetrics())
+	})
+	if err != nil {
+		// Silently handle error - can't log during TUI
+		_ = err
+	}
+}
+
+// addConsumer adds a new consumer worker
+func (ui *E2EUI) addConsumer() {
+	err := ui.consumers.AddWorker(ui.ctx, func(id int) (worker.Worker, error) {
+		return worker.NewReceiveWorker(id, ui.config, ui.consumers.GetMetrics())
+	})
+	if err != nil {
+		// Silently handle error - can't log during TUI
+		_ = err
+	}
+}
+
+// removeWorker removes a worker from the given pool
+func (ui *E2EUI) removeWorker(pool *worker.Pool) {
+	if err := pool.RemoveWorker(); err != nil {
+		// Silently handle error - can't log during TUI
+		_ = err
+	}
+}
+
+// updateControlMenu updates the control menu display
+func (ui *E2EUI) updateControlMenu() {
+	// Order: Producers, Consumers, Target Rate, Pause, Reset
+	if len(ui.controlMenu.items) >= 3 {
+		ui.controlMenu.items[0].Value = fmt.Sprintf("%d", ui.producers.WorkerCount())
+		ui.controlMenu.items[1].Value = fmt.Sprintf("%d", ui.consumers.WorkerCount())
+		ui.controlMenu.items[2].Value = formatTargetRate(ui.producers.GetConfig().Performance.TargetThroughput)
+	}
+	ui.controlMenu.Render()
+}
+
+// showHelp displays the help modal
+func (ui *E2EUI) showHelp() {
+	ui.showingHelp = true
+	ui.helpModal.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+		ui.hideHelp()
+	})
+	ui.app.SetRoot(ui.helpModal, true)
+}
+
+// hideHelp hides the help modal
+func (ui *E2EUI) hideHelp() {
+	ui.showingHelp = false
+	ui.app.SetRoot(ui.mainLayout, true)
+}
+
+// toggleLogs shows/hides the log window
+func (ui *E2EUI) toggleLogs() {
+	if ui.showingLogs {
+		ui.hideLogs()
+	} else {
+		ui.showLogs()
+	}
+}
+
+// showLogs displays the log window
+func (ui *E2EUI) showLogs() {
+	ui.showingLogs = true
+	ui.logWindow.Update()
+
+	// Set input capture for log window
+	ui.logWindow.GetTextView().SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Rune() == 'l' || event.Rune() == 'L' || event.Key() == tcell.KeyEscape {
+			ui.hideLogs()
+			return nil
+		}
+		if event.Rune() == 'c' || event.Rune() == 'C' {
+			ui.logBuffer.Clear()
+			ui.logWindow.Update()
+			return nil
+		}
+		if event.Rune() == 'q' || event.Rune() == 'Q' || event.Key() == tcell.KeyCtrlC {
+			ui.shutdown()
+			return nil
+		}
+		return event
+	})
+
+	ui.app.SetRoot(ui.logWindow.GetTextView(), true)
+}
+
+// hideLogs hides the log window
+func (ui *E2EUI) hideLogs() {
+	ui.showingLogs = false
+	ui.app.SetRoot(ui.mainLayout, true)
+}
+
+// shutdown stops the UI and both worker pools
+func (ui *E2EUI) shutdown() {
+	ui.app.Stop()       // Stop TUI first to restore terminal
+	ui.cancelFunc()     // Cancel context to signal workers
+	ui.producers.Stop() // Stop producers first so consumers see every sent message
+	ui.consumers.Stop()
+}
+
+// updateLoop runs the UI update loop
+func (ui *E2EUI) updateLoop() {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ui.ctx.Done():
+			// Cancelled by a signal or a finished scenario: close the UI so Run returns
+			ui.app.Stop()
+			return
+		case <-ticker.C:
+			snapshot := ui.producers.GetMetrics().GetSnapshot()
+
+			ui.app.QueueUpdateDraw(func() {
+				// Update control menu
+				ui.updateControlMenu()
+
+				// Update metrics panel
+				ui.metricsPanel.UpdateE2EMetrics(snapshot)
+
+				// Update graphs
+				ui.sendGraph.AddDataPoint(snapshot.Throughput.SendRate)
+				ui.receiveGraph.AddDataPoint(snapshot.Throughput.ReceiveRate)
+				if ui.backlogGraph != nil && snapshot.Lag.HasBacklog {
+					ui.backlogGraph.AddDataPoint(float64(snapshot.Lag.Backlog))
+				}
+				ui.latencyGraph.AddDataPoint(float64(snapshot.Lag.Lag) / float64(time.Millisecond))
+
+				if ui.topicPanel != nil {
+					ui.topicPanel.UpdateConsumerTopics(snapshot)
+				}
+				if ui.brokerPanel != nil {
+					ui.brokerPanel.Update(snapshot, ui.config.Consumer.SubscriptionName)
+				}
+
+				// Update status bar
+				shortcuts := "↑↓←→ Navigate  [Q]uit  [P]ause producers  [R]eset  [L]ogs  [H]elp"
+				ui.statusBar.Update(
+					ui.producers.IsRunning(),
+					ui.producers.IsPaused(),
+					ui.producers.WorkerCount()+ui.consumers.WorkerCount(),
+					snapshot.Elapsed,
+					shortcuts,
+				)
+
+				// Update log window if visible
+				if ui.showingLogs && ui.logWindow != nil {
+					ui.logWindow.Update()
+				}
+			})
+		}
+	}
+}
+
+// Run starts the end-to-end UI
+func (ui *E2EUI) Run() error {
+	// Start consumers before producers so no message is sent before a consumer is receiving
+	if err := ui.consumers.Start(ui.ctx); err != nil {
+		return fmt.Errorf("failed to start consumer pool: %w", err)
+	}
+	if err := ui.producers.Start(ui.ctx); err != nil {
+		return fmt.Errorf("failed to start producer pool: %w", err)
+	}
+
+	// Start update loop in background
+	go ui.updateLoop()
+
+	// Run the application
+	if err := ui.app.SetRoot(ui.mainLayout, true).EnableMouse(true).Run(); err != nil {
+		return fmt.Errorf("failed to run UI: %w", err)
+	}
+
+	return nil
+}
+
+// RunE2EUI is the main entry point for the end-to-end UI
+func RunE2EUI(ctx context.Context, producers, consumers *worker.Pool, logBuffer *LogBuffer) error {
+	ui := NewE2EUI(ctx, producers, consumers)
+	ui.logBuffer = logBuffer
+	ui.logWindow = NewLogWindow(logBuffer)
+	return ui.Run()
+}
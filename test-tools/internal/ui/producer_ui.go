@@ -13,21 +13,24 @@ import (
 
 // ProducerUI manages the producer terminal UI
 type ProducerUI struct {
-	app          *tview.Application
-	pool         *worker.Pool
-	ctx          context.Context
-	cancelFunc   context.CancelFunc
-	metricsPanel *MetricsPanel
-	graphWidget  *GraphWidget
-	controlMenu  *ControlMenu
-	statusBar    *StatusBar
-	helpModal    *HelpModal
-	logWindow    *LogWindow
-	logBuffer    *LogBuffer
-	mainLayout   *tview.Flex
-	showingHelp  bool
-	showingLogs  bool
-	config       *config.Config
+	app            *tview.Application
+	pool           *worker.Pool
+	ctx            context.Context
+	cancelFunc     context.CancelFunc
+	metricsPanel   *MetricsPanel
+	graphWidget    *GraphWidget
+	partitionPanel *PartitionPanel
+	topicPanel     *TopicPanel
+	brokerPanel    *BrokerPanel
+	controlMenu    *ControlMenu
+	statusBar      *StatusBar
+	helpModal      *HelpModal
+	logWindow      *LogWindow
+	logBuffer      *LogBuffer
+	mainLayout     *tview.Flex
+	showingHelp    bool
+	showingLogs    bool
+	config         *config.Config
 }
 
 // NewProducerUI creates a new producer UI
@@ -49,16 +52,16 @@ func NewProducerUI(ctx context.Context, pool *worker.Pool) *ProducerUI {
 
 	// Create help modal
 	shortcuts := map[string]string{
-		"Q / Ctrl+C":    "Quit application",
-		"↑/↓ Arrows":    "Navigate controls",
-		"←/→ Arrows":    "Adjust values",
-		"Enter/Space":   "Activate button",
-		"P":             "Pause/Resume",
-		"R":             "Reset metrics",
-		"L":             "Show/hide logs",
-		"C":             "Clear logs (when visible)",
-		"H / ?":         "Show/hide help",
-		"* (asterisk)":  "Use 'Restart Workers' to apply",
+		"Q / Ctrl+C":   "Quit application",
+		"↑/↓ Arrows":   "Navigate controls",
+		"←/→ Arrows":   "Adjust values",
+		"Enter/Space":  "Activate button",
+		"P":            "Pause/Resume",
+		"R":            "Reset metrics",
+		"L":            "Show/hide logs",
+		"C":            "Clear logs (when visible)",
+		"H / ?":        "Show/hide help",
+		"* (asterisk)": "Use 'Restart Workers' to apply",
 	}
 	helpModal := NewHelpModal(shortcuts)
 
@@ -76,6 +79,16 @@ func NewProducerUI(ctx context.Context, pool *worker.Pool) *ProducerUI {
 		config:       cfg,
 	}
 
+	if cfg != nil && cfg.TrackPartitions() {
+		ui.partitionPanel = NewPartitionPanel("PARTITIONS")
+	}
+	if cfg != nil && cfg.MultiTopic() {
+		ui.topicPanel = NewTopicPanel()
+	}
+	if cfg != nil && cfg.Metrics.BrokerStatsInterval > 0 {
+		ui.brokerPanel = NewBrokerPanel("BROKER STATS")
+	}
+
 	ui.setupControlMenu()
 	ui.buildLayout()
 	return ui
@@ -197,7 +210,22 @@ func (ui *ProducerUI) buildLayout() {
 		AddItem(title, 1, 0, false).
 		AddItem(connInfo, 1, 0, false).
 		AddItem(tview.NewBox().SetBorder(false), 1, 0, false). // Spacer
-		AddItem(topSection, 0, 1, false)
+		AddItem(topSection, 0, 2, false)
+
+	// Partition and topic distribution and broker-side stats below metrics
+	if ui.partitionPanel != nil || ui.topicPanel != nil || ui.brokerPanel != nil {
+		bottomSection := tview.NewFlex()
+		if ui.partitionPanel != nil {
+			bottomSection.AddItem(ui.partitionPanel, 0, 1, false)
+		}
+		if ui.topicPanel != nil {
+			bottomSection.AddItem(ui.topicPanel, 0, 1, false)
+		}
+		if ui.brokerPanel != nil {
+			bottomSection.AddItem(ui.brokerPanel, 0, 1, false)
+		}
+		rightContent.AddItem(bottomSection, 0, 1, false)
+	}
 
 	// Main content with control menu on left
 	mainContent := tview.NewFlex().
@@ -336,8 +364,11 @@ func (ui *ProducerUI) updateControlMenu() {
 // adjustTargetRate adjusts the target message rate
 func (ui *ProducerUI) adjustTargetRate(delta int) {
 	cfg := ui.pool.GetConfig()
-	current := cfg.Performance.TargetThroughput
+	ui.pool.UpdateTargetRate(nextTargetRate(cfg.Performance.TargetThroughput, delta))
+}
 
+// nextTargetRate steps a target rate up or down in increments based on its current value
+func nextTargetRate(current, delta int) int {
 	// Adjust in increments based on current value
 	var increment int
 	if current == 0 {
@@ -354,8 +385,7 @@ func (ui *ProducerUI) adjustTargetRate(delta int) {
 	if newRate < 0 {
 		newRate = 0 // 0 = unlimited
 	}
-
-	ui.pool.UpdateTargetRate(newRate)
+	return newRate
 }
 
 // adjustBatchSize adjusts the batching max size
@@ -419,16 +449,24 @@ func (ui *ProducerUI) adjustMessageSize(delta int) {
 		increment = 1024 // 1 KB
 	} else if current < 102400 {
 		increment = 10240 // 10 KB
-	} else {
+	} else if current < 1048576 {
 		increment = 102400 // 100 KB
+	} else {
+		increment = 1048576 // 1 MB
+	}
+
+	// Payloads above 1 MB only make sense when the producer can chunk them
+	maxSize := 1048576 // 1 MB
+	if cfg.Producer.EnableChunking {
+		maxSize = 64 * 1048576 // 64 MB
 	}
 
 	newSize := current + (delta * increment)
 	if newSize < 256 {
 		newSize = 256 // Minimum 256 bytes
 	}
-	if newSize > 1048576 {
-		newSize = 1048576 // Maximum 1 MB
+	if newSize > maxSize {
+		newSize = maxSize
 	}
 
 	ui.pool.UpdateMessageSize(newSize)
@@ -536,6 +574,8 @@ func (ui *ProducerUI) updateLoop() {
 	for {
 		select {
 		case <-ui.ctx.Done():
+			// Cancelled by a signal or a finished scenario: close the UI so Run returns
+			ui.app.Stop()
 			return
 		case <-ticker.C:
 			snapshot := ui.pool.GetMetrics().GetSnapshot()
@@ -550,6 +590,17 @@ func (ui *ProducerUI) updateLoop() {
 				// Update graph with current rate
 				ui.graphWidget.AddDataPoint(snapshot.Throughput.SendRate)
 
+				// Update partition distribution
+				if ui.partitionPanel != nil {
+					ui.partitionPanel.UpdateProducerPartitions(snapshot)
+				}
+				if ui.topicPanel != nil {
+					ui.topicPanel.UpdateProducerTopics(snapshot)
+				}
+				if ui.brokerPanel != nil {
+					ui.brokerPanel.Update(snapshot, "")
+				}
+
 				// Update status bar
 				shortcuts := "↑↓←→ Navigate  [Q]uit  [P]ause  [R]eset  [L]ogs  [H]elp"
 				ui.statusBar.Update(
@@ -0,0 +1,101 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pulsar-local-lab/perf-test/internal/config"
+	"github.com/pulsar-local-lab/perf-test/internal/metrics"
+)
+
+// prefillCheckInterval is how often pre-filling checks whether it has sent enough messages
+const prefillCheckInterval = 10 * time.Millisecond
+
+// PrefillResult summarizes the backlog produced before consumers start
+type PrefillResult struct {
+	Messages uint64
+	Bytes    uint64
+	Duration time.Duration
+}
+
+// Rate returns the messages per second the backlog was produced at
+func (r PrefillResult) Rate() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+	return float64(r.Messages) / r.Duration.Seconds()
+}
+
+// Report converts the result to its section of the exported metrics report
+func (r PrefillResult) Report() *metrics.PrefillReport {
+	return &metrics.PrefillReport{
+		Messages: r.Messages,
+		Bytes:    r.Bytes,
+		Duration: r.Duration.String(),
+		Rate:     r.Rate(),
+	}
+}
+
+// Prefill produces a backlog on the topic with the configured producers until
+// Consumer.BacklogMessages messages are sent or Consumer.BacklogDuration has passed,
+// whichever comes first, or until the producers finish on their own (e.g. at the end of a
+// replay file). Messages still in flight when the limit is reached are sent too, so the
+// backlog can be slightly larger than requested. progress is called about once a second with
+// the number of messages sent so far.
+func Prefill(ctx context.Context, cfg *config.Config, progress func(sent uint64)) (PrefillResult, error) {
+	// Pre-filling starts right away and ends at the backlog limits
+	prefillCfg := *cfg
+	prefillCfg.Performance.Warmup = 0
+	prefillCfg.Performance.Duration = 0
+
+	collector := metrics.NewCollector(cfg.Metrics.HistogramBuckets)
+	pool, err := newProducerPool(ctx, &prefillCfg, collector)
+	if err != nil {
+		return PrefillResult{}, fmt.Errorf("failed to create producers: %w", err)
+	}
+
+	startTime := time.Now()
+	if err := pool.Start(ctx); err != nil {
+		pool.closeWorkers()
+		return PrefillResult{}, err
+	}
+
+	finished := make(chan struct{})
+	go func() {
+		pool.wg.Wait()
+		close(finished)
+	}()
+
+	ticker := time.NewTicker(prefillCheckInterval)
+	defer ticker.Stop()
+	lastProgress := startTime
+	for done := false; !done; {
+		select {
+		case <-ctx.Done():
+			_ = pool.Stop()
+			return PrefillResult{}, ctx.Err()
+		case <-finished:
+			done = true
+		case now := <-ticker.C:
+			sent := collector.MessagesSent()
+			done = (cfg.Consumer.BacklogMessages > 0 && sent >= uint64(cfg.Consumer.BacklogMessages)) ||
+				(cfg.Consumer.BacklogDuration > 0 && now.Sub(startTime) >= time.Duration(cfg.Consumer.BacklogDuration))
+			if progress != nil && now.Sub(lastProgress) >= time.Second {
+				lastProgress = now
+				progress(sent)
+			}
+		}
+	}
+
+	// Stopping flushes the messages still in flight
+	if err := pool.Stop(); err != nil {
+		return PrefillResult{}, fmt.Errorf("failed to stop producers: %w", err)
+	}
+	snapshot := collector.GetSnapshot()
+	return PrefillResult{
+		Messages: snapshot.MessagesSent,
+		Bytes:    snapshot.BytesSent,
+		Duration: time.Since(startTime),
+	}, nil
+}
@@ -3,19 +3,59 @@ package worker
 import (
 	"context"
 	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	pulsarclient "github.com/apache/pulsar-client-go/pulsar"
 	"github.com/pulsar-local-lab/perf-test/internal/config"
+	"github.com/pulsar-local-lab/perf-test/internal/generator"
 	"github.com/pulsar-local-lab/perf-test/internal/metrics"
 	"github.com/pulsar-local-lab/perf-test/internal/pulsar"
 )
 
 // ConsumerWorker represents a consumer worker
 type ConsumerWorker struct {
-	id        int
-	client    *pulsar.ConsumerClient
-	collector *metrics.Collector
-	config    *config.Config
+	receiver
+	id     int
+	client consumerClient
+
+	// drainFrom is the time in Unix nanoseconds of a seek the worker has yet to start
+	// draining the replayed backlog of (0 = none)
+	drainFrom atomic.Int64
+}
+
+// consumerClient is the part of pulsar.ConsumerClient consumer workers use
+type consumerClient interface {
+	Receive(ctx context.Context) (pulsarclient.Message, error)
+	Ack(msg pulsarclient.Message) error
+	SeekTo(start config.ReaderStart) error
+	Close() error
+}
+
+// receiver records the messages consumer and reader workers receive and holds what they
+// share with producer workers: the pool for pause/resume and a context of their own
+type receiver struct {
+	collector    *metrics.Collector
+	config       *config.Config
+	capture      *pulsar.Capture // shared by the pool's workers, nil unless capturing
+	perTopic     bool            // track metrics per topic (multi-topic workloads)
+	perPartition bool            // track metrics per partition (single partitioned topic)
+	workerPool   *Pool
+	workerCtx    context.Context
+	cancelFunc   context.CancelFunc
+	wg           sync.WaitGroup
+}
+
+// newReceiver creates the receiver of a consumer or reader worker
+func newReceiver(cfg *config.Config, collector *metrics.Collector) receiver {
+	return receiver{
+		collector:    collector,
+		config:       cfg,
+		perTopic:     cfg.MultiTopic(),
+		perPartition: cfg.TrackPartitions(),
+	}
 }
 
 // NewConsumerWorker creates a new consumer worker
@@ -27,22 +67,31 @@ func NewConsumerWorker(id int, cfg *config.Config, collector *metrics.Collector)
 	}
 
 	return &ConsumerWorker{
-		id:        id,
-		client:    client,
-		collector: collector,
-		config:    cfg,
+		receiver: newReceiver(cfg, collector),
+		id:       id,
+		client:   client,
 	}, nil
 }
 
-// Start starts the consumer worker
+// Start starts the consumer worker.
+// After the subscription seeks back, the worker drains the replayed backlog until a broker
+// stats poll started after the seek finds the subscription backlog empty, or it reaches
+// messages published after the seek. Receive timeouts do not end the drain, since cold
+// reads can be slow. Drained messages are measured separately and, like reader catch-up,
+// do not record end-to-end latency.
 func (cw *ConsumerWorker) Start(ctx context.Context) error {
+	ctx = cw.workContext(ctx)
+	defer cw.wg.Done()
+
 	// Warmup period
 	if cw.config.Performance.Warmup > 0 {
-		time.Sleep(cw.config.Performance.Warmup)
+		time.Sleep(time.Duration(cw.config.Performance.Warmup))
 	}
 
 	// Main consumption loop
 	startTime := time.Now()
+	draining := false
+	var seekTime, lastRead time.Time
 	for {
 		select {
 		case <-ctx.Done():
@@ -52,11 +101,33 @@ func (cw *ConsumerWorker) Start(ctx context.Context) error {
 
 		// Check duration limit
 		if cw.config.Performance.Duration > 0 &&
-			time.Since(startTime) >= cw.config.Performance.Duration {
+			time.Since(startTime) >= time.Duration(cw.config.Performance.Duration) {
 			return nil
 		}
 
+		// Check if paused - stop receiving so the receiver queue fills up and the broker
+		// stops dispatching, then sleep briefly and check again
+		if cw.paused() {
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+
+		// Start draining after a seek
+		if from := cw.drainFrom.Swap(0); from != 0 {
+			draining = true
+			seekTime = time.Unix(0, from)
+			lastRead = seekTime
+		}
+
+		// The replayed backlog is drained once the subscription has no unacknowledged
+		// messages left
+		if draining && cw.collector.BacklogDrainedSince(seekTime) {
+			draining = false
+			cw.collector.RecordCaughtUp(lastRead)
+		}
+
 		// Receive message with shorter timeout for faster shutdown response
+		receiveStart := time.Now()
 		receiveCtx, cancel := context.WithTimeout(ctx, 1*time.Second)
 		msg, err := cw.client.Receive(receiveCtx)
 		cancel()
@@ -68,9 +139,28 @@ func (cw *ConsumerWorker) Start(ctx context.Context) error {
 			}
 			continue
 		}
+		receivedAt := time.Now()
 
-		// Record metrics
+		// The replayed backlog ends where messages published after the seek begin
+		if draining && !msg.PublishTime().Before(seekTime) {
+			draining = false
+			cw.collector.RecordCaughtUp(receivedAt)
+		}
+
+		// Record metrics, preferring the exact send time when the producer attached one
+		lag := receivedAt.Sub(msg.PublishTime())
+		if sentAt, ok := pulsar.ParseSendTime(msg.Properties()); ok {
+			lag = receivedAt.Sub(sentAt)
+		}
 		cw.collector.RecordReceive(len(msg.Payload()))
+		if draining {
+			lastRead = receivedAt
+			cw.collector.RecordCatchUpRead(len(msg.Payload()), receivedAt.Sub(receiveStart))
+		} else {
+			cw.collector.RecordE2ELatency(lag)
+		}
+		cw.collector.RecordLag(lag)
+		cw.recordMessage(msg, receivedAt, lag)
 
 		// Acknowledge message
 		if err := cw.client.Ack(msg); err != nil {
@@ -82,11 +172,102 @@ func (cw *ConsumerWorker) Start(ctx context.Context) error {
 	}
 }
 
+// recordMessage records the partition, topic, group, chunking and checksum metrics of a
+// received message and writes it to the capture file
+func (r *receiver) recordMessage(msg pulsarclient.Message, receivedAt time.Time, lag time.Duration) {
+	if r.perPartition {
+		r.collector.RecordPartitionReceive(pulsar.TopicPartition(msg.Topic()), len(msg.Payload()), lag)
+	}
+	if r.perTopic {
+		r.collector.RecordTopicReceive(pulsar.TopicBase(msg.Topic()), len(msg.Payload()), lag)
+	}
+	if name := r.config.Consumer.GroupByProperty; name != "" {
+		value, ok := msg.Properties()[name]
+		if !ok {
+			value = metrics.GroupNone
+		}
+		r.collector.RecordGroupReceive(value, len(msg.Payload()), lag)
+	}
+	if pulsar.IsChunkedMessageID(msg.ID()) {
+		r.collector.RecordChunkedReceive(len(msg.Payload()), lag)
+	}
+
+	// Verify the payload checksum attached by the producer, timed on its own
+	if r.config.Consumer.VerifyChecksums {
+		if checksum, ok := msg.Properties()[pulsar.ChecksumProperty]; ok {
+			verifyStart := time.Now()
+			err := generator.VerifyChecksum(checksum, msg.Payload())
+			r.collector.RecordChecksumVerify(time.Since(verifyStart))
+			if err != nil {
+				r.collector.RecordCorrupt(msg.ID().String())
+				log.Printf("Corrupt message %s on %s: %v", msg.ID(), msg.Topic(), err)
+			}
+		}
+	}
+
+	// Write the message to the capture file
+	if r.capture != nil {
+		if err := r.capture.Write(msg, receivedAt); err != nil {
+			log.Printf("Capture stopped: %v", err)
+		}
+	}
+}
+
+// startDrain makes the worker measure how fast it drains the backlog replayed by a seek at
+// the given time
+func (cw *ConsumerWorker) startDrain(seekTime time.Time) {
+	cw.drainFrom.Store(seekTime.UnixNano())
+}
+
 // Stop stops the consumer worker
 func (cw *ConsumerWorker) Stop() error {
 	return cw.client.Close()
 }
 
+// SetContext sets the worker's context and cancel function.
+// The worker uses this context instead of the one passed to Start.
+func (r *receiver) SetContext(ctx context.Context, cancel context.CancelFunc) {
+	r.workerCtx = ctx
+	r.cancelFunc = cancel
+}
+
+// SetPool sets the worker pool reference for pause/resume functionality and the pool's
+// capture, if any
+func (r *receiver) SetPool(pool *Pool) {
+	r.workerPool = pool
+	r.capture = pool.capture
+}
+
+// CancelContext cancels the worker's context, signaling it to stop
+func (r *receiver) CancelContext() {
+	if r.cancelFunc != nil {
+		r.cancelFunc()
+	}
+}
+
+// MarkStarted registers a run of the worker before the pool launches its goroutine
+func (r *receiver) MarkStarted() {
+	r.wg.Add(1)
+}
+
+// WaitForCompletion waits for the worker's goroutine to finish
+func (r *receiver) WaitForCompletion() {
+	r.wg.Wait()
+}
+
+// workContext returns the worker's own context if set, otherwise the provided context
+func (r *receiver) workContext(ctx context.Context) context.Context {
+	if r.workerCtx != nil {
+		return r.workerCtx
+	}
+	return ctx
+}
+
+// paused reports whether the worker's pool is paused
+func (r *receiver) paused() bool {
+	return r.workerPool != nil && r.workerPool.IsPaused()
+}
+
 // ID returns the worker ID
 func (cw *ConsumerWorker) ID() int {
 	return cw.id
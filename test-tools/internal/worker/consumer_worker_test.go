@@ -0,0 +1,186 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	pulsarclient "github.com/apache/pulsar-client-go/pulsar"
+	"github.com/pulsar-local-lab/perf-test/internal/config"
+	"github.com/pulsar-local-lab/perf-test/internal/metrics"
+)
+
+// fakeConsumer is a consumer client whose receives always time out quickly
+type fakeConsumer struct {
+	mu             sync.Mutex
+	receives       int
+	closed         bool
+	receivedClosed bool
+}
+
+func (f *fakeConsumer) Receive(ctx context.Context) (pulsarclient.Message, error) {
+	f.mu.Lock()
+	f.receives++
+	if f.closed {
+		f.receivedClosed = true
+	}
+	f.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(5 * time.Millisecond):
+		return nil, context.DeadlineExceeded
+	}
+}
+
+func (f *fakeConsumer) Ack(pulsarclient.Message) error { return nil }
+
+func (f *fakeConsumer) SeekTo(config.ReaderStart) error { return nil }
+
+func (f *fakeConsumer) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	return nil
+}
+
+func (f *fakeConsumer) state() (receives int, closed, receivedClosed bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.receives, f.closed, f.receivedClosed
+}
+
+// newFakeConsumerPool creates a pool of consumer workers with fake clients
+func newFakeConsumerPool(ctx context.Context, n int) (*Pool, []*fakeConsumer) {
+	cfg := config.DefaultConfig("")
+	cfg.Performance.Warmup = 0
+	cfg.Performance.Duration = 0
+	collector := metrics.NewCollector(cfg.Metrics.HistogramBuckets)
+
+	pool := &Pool{collector: collector, config: cfg}
+	var clients []*fakeConsumer
+	for i := 0; i < n; i++ {
+		worker, client := newFakeConsumerWorker(i, cfg, collector)
+		pool.initWorker(ctx, worker)
+		pool.workers = append(pool.workers, worker)
+		clients = append(clients, client)
+	}
+	return pool, clients
+}
+
+func newFakeConsumerWorker(id int, cfg *config.Config, collector *metrics.Collector) (*ConsumerWorker, *fakeConsumer) {
+	client := &fakeConsumer{}
+	return &ConsumerWorker{
+		receiver: newReceiver(cfg, collector),
+		id:       id,
+		client:   client,
+	}, client
+}
+
+// waitFor polls cond until it holds or the timeout expires
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before timeout")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestConsumerWorkerPause(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pool, clients := newFakeConsumerPool(ctx, 2)
+	if err := pool.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	receives := func() int {
+		total := 0
+		for _, c := range clients {
+			n, _, _ := c.state()
+			total += n
+		}
+		return total
+	}
+	waitFor(t, time.Second, func() bool { return receives() > 0 })
+
+	// Paused workers stop receiving once their in-flight receive returns
+	pool.Pause()
+	time.Sleep(200 * time.Millisecond)
+	paused := receives()
+	time.Sleep(300 * time.Millisecond)
+	if got := receives(); got != paused {
+		t.Errorf("received %d times while paused, want 0", got-paused)
+	}
+
+	pool.Resume()
+	waitFor(t, time.Second, func() bool { return receives() > paused })
+
+	cancel()
+	if err := pool.Stop(); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+}
+
+func TestConsumerWorkerRemove(t *testing.T) {
+	tests := []struct {
+		name   string
+		paused bool
+	}{
+		{"running", false},
+		{"paused", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			pool, _ := newFakeConsumerPool(ctx, 1)
+			if err := pool.Start(ctx); err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+			if tt.paused {
+				pool.Pause()
+			}
+
+			// Remove a worker right after adding it, before its goroutine may have run
+			var added *fakeConsumer
+			err := pool.AddWorker(ctx, func(id int) (Worker, error) {
+				worker, client := newFakeConsumerWorker(id, pool.config, pool.collector)
+				added = client
+				return worker, nil
+			})
+			if err != nil {
+				t.Fatalf("AddWorker() error = %v", err)
+			}
+			if err := pool.RemoveWorker(); err != nil {
+				t.Fatalf("RemoveWorker() error = %v", err)
+			}
+
+			// The worker has stopped before its client was closed
+			time.Sleep(100 * time.Millisecond)
+			_, closed, receivedClosed := added.state()
+			if !closed {
+				t.Error("removed worker's client was not closed")
+			}
+			if receivedClosed {
+				t.Error("removed worker received after its client was closed")
+			}
+			if got := pool.WorkerCount(); got != 1 {
+				t.Errorf("WorkerCount() = %d, want 1", got)
+			}
+
+			cancel()
+			if err := pool.Stop(); err != nil {
+				t.Errorf("Stop() error = %v", err)
+			}
+		})
+	}
+}
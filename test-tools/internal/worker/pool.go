@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/pulsar-local-lab/perf-test/internal/config"
+	"github.com/pulsar-local-lab/perf-test/internal/generator"
 	"github.com/pulsar-local-lab/perf-test/internal/metrics"
 	"github.com/pulsar-local-lab/perf-test/internal/pulsar"
 )
@@ -20,6 +21,15 @@ type Pool struct {
 	mu        sync.RWMutex
 	running   bool
 	paused    bool
+
+	// statsPoller polls broker-side topic stats into the collector (nil when disabled)
+	statsPoller *pulsar.StatsPoller
+
+	// replay is the traffic file producer workers replay (nil unless replaying)
+	replay *generator.Replayer
+
+	// capture writes the messages consumer workers receive to a file (nil unless capturing)
+	capture *pulsar.Capture
 }
 
 // Worker interface for producer and consumer workers
@@ -29,6 +39,17 @@ type Worker interface {
 	ID() int
 }
 
+// managedWorker is a worker with its own context, so it can be stopped gracefully on its
+// own, and a reference to its pool for pause/resume
+type managedWorker interface {
+	Worker
+	SetContext(ctx context.Context, cancel context.CancelFunc)
+	SetPool(pool *Pool)
+	CancelContext()
+	MarkStarted()
+	WaitForCompletion()
+}
+
 // NewProducerPool creates a new producer worker pool
 func NewProducerPool(ctx context.Context, cfg *config.Config) (*Pool, error) {
 	// Ensure topic exists with correct partition configuration
@@ -36,65 +57,189 @@ func NewProducerPool(ctx context.Context, cfg *config.Config) (*Pool, error) {
 		return nil, fmt.Errorf("failed to ensure topic exists: %w", err)
 	}
 
+	pool, err := newProducerPool(ctx, cfg, metrics.NewCollector(cfg.Metrics.HistogramBuckets))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pool.initStatsPoller(""); err != nil {
+		return nil, err
+	}
+
+	return pool, nil
+}
+
+// NewConsumerPool creates a new consumer worker pool
+func NewConsumerPool(ctx context.Context, cfg *config.Config) (*Pool, error) {
+	// Ensure topic exists with correct partition configuration
+	if err := pulsar.EnsureTopic(cfg); err != nil {
+		return nil, fmt.Errorf("failed to ensure topic exists: %w", err)
+	}
+
+	pool, err := newConsumerPool(ctx, cfg, metrics.NewCollector(cfg.Metrics.HistogramBuckets))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pool.initStatsPoller(statsSubscription(cfg)); err != nil {
+		return nil, err
+	}
+
+	return pool, nil
+}
+
+// NewE2EPools creates a producer pool and a consumer pool on the same topic that report to
+// one shared collector, so send and receive metrics come from the same clock.
+// Consumers are created first so their subscription exists before the first message is sent.
+// Only the consumer pool polls broker stats, which also tracks its subscription backlog.
+func NewE2EPools(ctx context.Context, cfg *config.Config) (*Pool, *Pool, error) {
+	// Ensure topic exists with correct partition configuration
+	if err := pulsar.EnsureTopic(cfg); err != nil {
+		return nil, nil, fmt.Errorf("failed to ensure topic exists: %w", err)
+	}
+
 	collector := metrics.NewCollector(cfg.Metrics.HistogramBuckets)
 
+	consumers, err := newConsumerPool(ctx, cfg, collector)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := consumers.initStatsPoller(statsSubscription(cfg)); err != nil {
+		consumers.closeWorkers()
+		return nil, nil, err
+	}
+
+	producers, err := newProducerPool(ctx, cfg, collector)
+	if err != nil {
+		consumers.closeWorkers()
+		return nil, nil, err
+	}
+
+	return producers, consumers, nil
+}
+
+// newProducerPool creates producer workers for an existing topic reporting to the given collector
+func newProducerPool(ctx context.Context, cfg *config.Config, collector *metrics.Collector) (*Pool, error) {
 	pool := &Pool{
 		workers:   make([]Worker, 0, cfg.Producer.NumProducers),
 		collector: collector,
 		config:    cfg,
 	}
 
+	// Open the replay file before connecting so a missing or empty file fails fast
+	replay, err := cfg.Producer.NewReplayer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open replay file: %w", err)
+	}
+	pool.replay = replay
+
 	// Create producer workers
 	for i := 0; i < cfg.Producer.NumProducers; i++ {
 		worker, err := NewProducerWorker(i, cfg, collector)
 		if err != nil {
 			// Clean up any created workers
-			pool.Stop()
+			pool.closeWorkers()
 			return nil, fmt.Errorf("failed to create producer worker %d: %w", i, err)
 		}
-
-		// Set per-worker context
-		workerCtx, cancelFunc := context.WithCancel(ctx)
-		worker.SetContext(workerCtx, cancelFunc)
-
-		// Set pool reference for pause/resume
-		worker.SetPool(pool)
-
+		pool.initWorker(ctx, worker)
 		pool.workers = append(pool.workers, worker)
 	}
 
 	return pool, nil
 }
 
-// NewConsumerPool creates a new consumer worker pool
-func NewConsumerPool(ctx context.Context, cfg *config.Config) (*Pool, error) {
-	// Ensure topic exists with correct partition configuration
-	if err := pulsar.EnsureTopic(cfg); err != nil {
-		return nil, fmt.Errorf("failed to ensure topic exists: %w", err)
-	}
-
-	collector := metrics.NewCollector(cfg.Metrics.HistogramBuckets)
-
+// newConsumerPool creates consumer workers for an existing topic reporting to the given collector
+func newConsumerPool(ctx context.Context, cfg *config.Config, collector *metrics.Collector) (*Pool, error) {
 	pool := &Pool{
 		workers:   make([]Worker, 0, cfg.Consumer.NumConsumers),
 		collector: collector,
 		config:    cfg,
 	}
 
+	capture, err := pulsar.NewCapture(&cfg.Consumer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create capture file: %w", err)
+	}
+	pool.capture = capture
+
 	// Create consumer workers
 	for i := 0; i < cfg.Consumer.NumConsumers; i++ {
-		worker, err := NewConsumerWorker(i, cfg, collector)
+		worker, err := NewReceiveWorker(i, cfg, collector)
 		if err != nil {
 			// Clean up any created workers
-			pool.Stop()
+			pool.closeWorkers()
 			return nil, fmt.Errorf("failed to create consumer worker %d: %w", i, err)
 		}
+		pool.initWorker(ctx, worker)
 		pool.workers = append(pool.workers, worker)
 	}
 
 	return pool, nil
 }
 
+// NewReceiveWorker creates a consumer worker, or a reader worker in reader mode
+func NewReceiveWorker(id int, cfg *config.Config, collector *metrics.Collector) (Worker, error) {
+	if cfg.Consumer.ReaderMode() {
+		return NewReaderWorker(id, cfg, collector)
+	}
+	return NewConsumerWorker(id, cfg, collector)
+}
+
+// initWorker gives a worker its own context derived from ctx and sets its pool
+func (p *Pool) initWorker(ctx context.Context, worker Worker) {
+	if mw, ok := worker.(managedWorker); ok {
+		workerCtx, cancelFunc := context.WithCancel(ctx)
+		mw.SetContext(workerCtx, cancelFunc)
+		mw.SetPool(p)
+	}
+}
+
+// newWorkerLike creates a worker of the same kind as the given one with the pool's
+// current configuration
+func (p *Pool) newWorkerLike(id int, like Worker) (Worker, error) {
+	if _, ok := like.(*ProducerWorker); ok {
+		return NewProducerWorker(id, p.config, p.collector)
+	}
+	return NewReceiveWorker(id, p.config, p.collector)
+}
+
+// statsSubscription returns the subscription whose backlog the broker stats poller tracks.
+// Readers have no subscription.
+func statsSubscription(cfg *config.Config) string {
+	if cfg.Consumer.ReaderMode() {
+		return ""
+	}
+	return cfg.Consumer.SubscriptionName
+}
+
+// closeWorkers closes the clients of workers that were created but never started
+func (p *Pool) closeWorkers() {
+	for _, worker := range p.workers {
+		_ = worker.Stop()
+	}
+	p.workers = nil
+	if p.replay != nil {
+		_ = p.replay.Close()
+	}
+	if p.capture != nil {
+		_ = p.capture.Close()
+	}
+}
+
+// initStatsPoller creates the broker stats poller when broker stats polling is enabled.
+// Consumer pools pass their subscription so its backlog is tracked.
+func (p *Pool) initStatsPoller(subscription string) error {
+	if p.config.Metrics.BrokerStatsInterval <= 0 {
+		return nil
+	}
+	poller, err := pulsar.NewStatsPoller(p.config, subscription, p.collector)
+	if err != nil {
+		return fmt.Errorf("failed to create broker stats poller: %w", err)
+	}
+	p.statsPoller = poller
+	return nil
+}
+
 // Start starts all workers in the pool
 func (p *Pool) Start(ctx context.Context) error {
 	p.mu.Lock()
@@ -105,22 +250,37 @@ func (p *Pool) Start(ctx context.Context) error {
 	p.running = true
 	p.mu.Unlock()
 
+	if p.statsPoller != nil {
+		p.statsPoller.Start(ctx)
+	}
+
 	// Start all workers
 	for _, worker := range p.workers {
-		p.wg.Add(1)
-		go func(w Worker) {
-			defer p.wg.Done()
-			if err := w.Start(ctx); err != nil {
-				// Silently handle error - logging to stdout breaks the TUI
-				// In production, would log to file or structured logger
-				_ = err
-			}
-		}(worker)
+		p.startWorker(ctx, worker)
 	}
 
 	return nil
 }
 
+// startWorker runs a worker in its own goroutine. Managed workers are marked as started
+// before the goroutine is launched, so waiting for one that is removed right away does not
+// return before it has even started.
+func (p *Pool) startWorker(ctx context.Context, worker Worker) {
+	if mw, ok := worker.(managedWorker); ok {
+		mw.MarkStarted()
+	}
+
+	p.wg.Add(1)
+	go func(w Worker) {
+		defer p.wg.Done()
+		if err := w.Start(ctx); err != nil {
+			// Silently handle error - logging to stdout breaks the TUI
+			// In production, would log to file or structured logger
+			_ = err
+		}
+	}(worker)
+}
+
 // Stop stops all workers in the pool
 func (p *Pool) Stop() error {
 	p.mu.Lock()
@@ -131,6 +291,21 @@ func (p *Pool) Stop() error {
 	p.running = false
 	p.mu.Unlock()
 
+	if p.statsPoller != nil {
+		p.statsPoller.Stop()
+	}
+
+	// Close the replay and capture files even when workers time out, so the capture is
+	// flushed; both are safe to close while a stuck worker still uses them
+	defer func() {
+		if p.replay != nil {
+			_ = p.replay.Close()
+		}
+		if p.capture != nil {
+			_ = p.capture.Close()
+		}
+	}()
+
 	// Stop all workers
 	var errs []error
 	for _, worker := range p.workers {
@@ -161,6 +336,11 @@ func (p *Pool) Stop() error {
 	return nil
 }
 
+// Capture returns the pool's message capture, or nil when messages are not captured
+func (p *Pool) Capture() *pulsar.Capture {
+	return p.capture
+}
+
 // GetMetrics returns the metrics collector
 func (p *Pool) GetMetrics() *metrics.Collector {
 	return p.collector
@@ -194,6 +374,43 @@ func (p *Pool) Resume() {
 	p.paused = false
 }
 
+// Seek moves the subscription of the pool's consumers to a start position and measures how
+// fast they drain the backlog it replays. Consumers share the subscription cursor, so one
+// consumer seeks on behalf of all of them. Readers have no subscription to seek.
+func (p *Pool) Seek(start config.ReaderStart) error {
+	p.mu.RLock()
+	var consumers []*ConsumerWorker
+	for _, worker := range p.workers {
+		if cw, ok := worker.(*ConsumerWorker); ok {
+			consumers = append(consumers, cw)
+		}
+	}
+	p.mu.RUnlock()
+
+	if len(consumers) == 0 {
+		return fmt.Errorf("no consumers to seek")
+	}
+
+	// The end of the drain is detected from the subscription backlog
+	if p.statsPoller == nil {
+		return fmt.Errorf("seeking needs broker stats polling (metrics.broker_stats_interval) to detect the end of the drain")
+	}
+
+	seekTime := time.Now()
+	if err := consumers[0].client.SeekTo(start); err != nil {
+		return err
+	}
+
+	// Backlog polls started before the seek completed may still report the old backlog
+	seekedAt := time.Now()
+	p.collector.RestartCatchUp(seekTime)
+	for _, cw := range consumers {
+		p.collector.RecordCatchUpStart()
+		cw.startDrain(seekedAt)
+	}
+	return nil
+}
+
 // WorkerCount returns the number of workers
 func (p *Pool) WorkerCount() int {
 	p.mu.RLock()
@@ -212,26 +429,14 @@ func (p *Pool) AddWorker(ctx context.Context, workerFactory func(int) (Worker, e
 		return fmt.Errorf("failed to create worker %d: %w", workerID, err)
 	}
 
-	// Set per-worker context for ProducerWorker
-	if pw, ok := worker.(*ProducerWorker); ok {
-		workerCtx, cancelFunc := context.WithCancel(ctx)
-		pw.SetContext(workerCtx, cancelFunc)
-		// Set pool reference for pause/resume
-		pw.SetPool(p)
-	}
+	// Set per-worker context and pool reference for pause/resume
+	p.initWorker(ctx, worker)
 
 	p.workers = append(p.workers, worker)
 
 	// Start the worker if pool is running
 	if p.running {
-		p.wg.Add(1)
-		go func(w Worker) {
-			defer p.wg.Done()
-			if err := w.Start(ctx); err != nil {
-				// Silently handle error - logging to stdout breaks the TUI
-				_ = err
-			}
-		}(worker)
+		p.startWorker(ctx, worker)
 	}
 
 	return nil
@@ -263,13 +468,13 @@ func (p *Pool) RemoveWorker() error {
 	p.mu.Unlock()
 
 	// Step 1: Cancel the worker's context to signal it to stop
-	if pw, ok := lastWorker.(*ProducerWorker); ok {
-		pw.CancelContext()
+	if mw, ok := lastWorker.(managedWorker); ok {
+		mw.CancelContext()
 
 		// Step 2: Wait for the goroutine to finish (with timeout)
 		done := make(chan struct{})
 		go func() {
-			pw.WaitForCompletion()
+			mw.WaitForCompletion()
 			close(done)
 		}()
 
@@ -282,7 +487,7 @@ func (p *Pool) RemoveWorker() error {
 		}
 	}
 
-	// Step 3: Now it's safe to stop (flush and close client)
+	// Step 3: Now it's safe to stop (flush or finish acknowledging and close client)
 	if err := lastWorker.Stop(); err != nil {
 		// Don't return error - worker is already removed from pool
 		// Log would go here if we had proper logging
@@ -364,6 +569,20 @@ func (p *Pool) UpdateCompression(compressionType string) {
 	p.config.Producer.CompressionType = compressionType
 }
 
+// UpdateReceiverQueueSize updates the consumer receiver queue size
+func (p *Pool) UpdateReceiverQueueSize(size int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.config.Consumer.ReceiverQueueSize = size
+}
+
+// UpdateSubscriptionType updates the consumer subscription type
+func (p *Pool) UpdateSubscriptionType(subscriptionType string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.config.Consumer.SubscriptionType = subscriptionType
+}
+
 // UpdateMessageSize updates the message size
 func (p *Pool) UpdateMessageSize(size int) {
 	p.mu.Lock()
@@ -372,14 +591,14 @@ func (p *Pool) UpdateMessageSize(size int) {
 }
 
 // RestartWorkers restarts all workers to apply immutable configuration changes
-// This is needed for settings like batch size, compression, and message size
+// This is needed for settings like batch size, compression, and message size, and for
+// the receiver queue size and subscription type of consumers
 func (p *Pool) RestartWorkers(ctx context.Context) error {
 	p.mu.Lock()
 
 	// Store current state
 	wasRunning := p.running
 	currentWorkerCount := len(p.workers)
-	currentConfig := p.config
 
 	// Stop all workers
 	oldWorkers := p.workers
@@ -388,8 +607,8 @@ func (p *Pool) RestartWorkers(ctx context.Context) error {
 
 	// Cancel all worker contexts and wait for them to stop
 	for _, worker := range oldWorkers {
-		if pw, ok := worker.(*ProducerWorker); ok {
-			pw.CancelContext()
+		if mw, ok := worker.(managedWorker); ok {
+			mw.CancelContext()
 		}
 	}
 
@@ -397,8 +616,8 @@ func (p *Pool) RestartWorkers(ctx context.Context) error {
 	done := make(chan struct{})
 	go func() {
 		for _, worker := range oldWorkers {
-			if pw, ok := worker.(*ProducerWorker); ok {
-				pw.WaitForCompletion()
+			if mw, ok := worker.(managedWorker); ok {
+				mw.WaitForCompletion()
 			}
 		}
 		close(done)
@@ -420,19 +639,20 @@ func (p *Pool) RestartWorkers(ctx context.Context) error {
 	p.running = false
 	p.mu.Unlock()
 
-	// Create new workers with updated configuration
+	// Create new workers of the same kind with updated configuration. Consumers reconnect
+	// only after all old ones disconnected, so the subscription type can change.
+	var createErr error
 	for i := 0; i < currentWorkerCount; i++ {
-		worker, err := NewProducerWorker(i, currentConfig, p.collector)
+		worker, err := p.newWorkerLike(i, oldWorkers[0])
 		if err != nil {
-			return fmt.Errorf("failed to create worker %d during restart: %w", i, err)
+			// Keep the workers created so far running, e.g. the one consumer an
+			// Exclusive subscription admits
+			createErr = fmt.Errorf("failed to create worker %d during restart: %w", i, err)
+			break
 		}
 
-		// Set per-worker context
-		workerCtx, cancelFunc := context.WithCancel(ctx)
-		worker.SetContext(workerCtx, cancelFunc)
-
-		// Set pool reference for pause/resume
-		worker.SetPool(p)
+		// Set per-worker context and pool reference for pause/resume
+		p.initWorker(ctx, worker)
 
 		p.mu.Lock()
 		p.workers = append(p.workers, worker)
@@ -440,9 +660,11 @@ func (p *Pool) RestartWorkers(ctx context.Context) error {
 	}
 
 	// Start workers if pool was running before
-	if wasRunning {
-		return p.Start(ctx)
+	if wasRunning && p.WorkerCount() > 0 {
+		if err := p.Start(ctx); err != nil {
+			return err
+		}
 	}
 
-	return nil
+	return createErr
 }
\ No newline at end of file
@@ -3,9 +3,12 @@ package worker
 import (
 	"context"
 	"fmt"
+	"io"
+	"math/rand/v2"
 	"sync"
 	"time"
 
+	pulsarclient "github.com/apache/pulsar-client-go/pulsar"
 	"github.com/pulsar-local-lab/perf-test/internal/config"
 	"github.com/pulsar-local-lab/perf-test/internal/generator"
 	"github.com/pulsar-local-lab/perf-test/internal/metrics"
@@ -15,20 +18,69 @@ import (
 
 // ProducerWorker represents a producer worker
 type ProducerWorker struct {
-	id         int
-	client     *pulsar.ProducerClient
+	id          int
+	client      *pulsar.ProducerClient
 	payloadPool *generator.PayloadPool
-	workerPool *Pool
-	collector  *metrics.Collector
-	limiter    *ratelimit.Limiter
-	config     *config.Config
-	workerCtx  context.Context
-	cancelFunc context.CancelFunc
-	wg         sync.WaitGroup
+	filler      generator.Filler
+	template    *generator.TemplateRenderer // nil unless payloads are templated
+	replay      *generator.Replayer         // shared by the pool's workers, nil unless replaying
+	properties  *generator.PropertyRenderer // nil unless message properties are configured
+	checksummer *generator.Checksummer      // nil unless payload checksums are enabled
+	topicChoice *generator.WeightedChoice   // nil unless sending to several topics
+	topics      []string                    // fully qualified topic names, indexed like topicChoice
+	sizes       generator.SizeDistribution
+	rng         *rand.Rand
+	workerPool  *Pool
+	collector   *metrics.Collector
+	limiter     *ratelimit.Limiter
+	config      *config.Config
+	chunkSize   int      // payloads larger than this are chunked (0 = chunking disabled)
+	keys        []string // message keys rotated through when KeyCount > 0
+	sent        uint64
+	workerCtx   context.Context
+	cancelFunc  context.CancelFunc
+	wg          sync.WaitGroup
 }
 
 // NewProducerWorker creates a new producer worker
 func NewProducerWorker(id int, cfg *config.Config, collector *metrics.Collector) (*ProducerWorker, error) {
+	// Create the payload size distribution before connecting so a bad histogram file fails fast
+	sizes, err := cfg.Producer.NewSizeDistribution()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create size distribution: %w", err)
+	}
+	filler, err := cfg.Producer.NewPayloadFiller(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create payload filler: %w", err)
+	}
+	tmpl, err := cfg.Producer.NewPayloadTemplate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load payload template: %w", err)
+	}
+	var renderer *generator.TemplateRenderer
+	if tmpl != nil {
+		renderer = generator.NewTemplateRenderer(tmpl, cfg.Producer.PayloadSeedFor(id))
+		renderer.SetWorker(id)
+	}
+	properties, err := cfg.Producer.NewPropertyRenderer(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create message properties: %w", err)
+	}
+	checksummer, err := cfg.Producer.NewChecksummer()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create payload checksummer: %w", err)
+	}
+	var topicChoice *generator.WeightedChoice
+	var topics []string
+	if names := cfg.Pulsar.TopicNames(); len(names) > 1 {
+		if topicChoice, err = cfg.Pulsar.NewTopicChoice(); err != nil {
+			return nil, fmt.Errorf("failed to create topic choice: %w", err)
+		}
+		for _, name := range names {
+			topics = append(topics, pulsar.FullTopicName(name))
+		}
+	}
+
 	// Create Pulsar producer client
 	client, err := pulsar.NewProducerClient(cfg)
 	if err != nil {
@@ -50,12 +102,34 @@ func NewProducerWorker(id int, cfg *config.Config, collector *metrics.Collector)
 		id:          id,
 		client:      client,
 		payloadPool: pool,
+		filler:      filler,
+		template:    renderer,
+		properties:  properties,
+		checksummer: checksummer,
+		topicChoice: topicChoice,
+		topics:      topics,
+		sizes:       sizes,
+		rng:         rand.New(rand.NewPCG(uint64(id), cfg.Producer.PayloadSeedFor(id))),
 		collector:   collector,
 		limiter:     limiter,
 		config:      cfg,
+		chunkSize:   cfg.Producer.ChunkThreshold(),
+		keys:        messageKeys(cfg.Producer.KeyCount),
 	}, nil
 }
 
+// messageKeys pre-builds the message keys so the send loop does not allocate them
+func messageKeys(count int) []string {
+	if count <= 0 {
+		return nil
+	}
+	keys := make([]string, count)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+	}
+	return keys
+}
+
 // Start starts the producer worker
 // The context passed here is ignored - worker uses its own workerCtx set during initialization
 func (pw *ProducerWorker) Start(ctx context.Context) error {
@@ -65,13 +139,11 @@ func (pw *ProducerWorker) Start(ctx context.Context) error {
 		workCtx = ctx
 	}
 
-	// Mark work group as started
-	pw.wg.Add(1)
 	defer pw.wg.Done()
 
 	// Warmup period
 	if pw.config.Performance.Warmup > 0 {
-		time.Sleep(pw.config.Performance.Warmup)
+		time.Sleep(time.Duration(pw.config.Performance.Warmup))
 	}
 
 	// Main production loop
@@ -85,7 +157,7 @@ func (pw *ProducerWorker) Start(ctx context.Context) error {
 
 		// Check duration limit
 		if pw.config.Performance.Duration > 0 &&
-			time.Since(startTime) >= pw.config.Performance.Duration {
+			time.Since(startTime) >= time.Duration(pw.config.Performance.Duration) {
 			return nil
 		}
 
@@ -103,17 +175,76 @@ func (pw *ProducerWorker) Start(ctx context.Context) error {
 			continue
 		}
 
-		// Get payload buffer from pool and generate random data
-		payload := pw.payloadPool.Get()
-		generator.GenerateRandomPayloadTo(payload)
+		// Take the next replayed record once it is due, render the next templated payload,
+		// or get a payload buffer of the sampled size from the pool and fill it
+		var payload []byte
+		var record generator.Record
+		switch {
+		case pw.replay != nil:
+			var err error
+			if record, err = pw.replay.Wait(workCtx); err != nil {
+				if err == io.EOF || workCtx.Err() != nil {
+					// Replay finished or worker stopped
+					return nil
+				}
+				return err
+			}
+			payload = record.Payload
+		case pw.template != nil:
+			payload = pw.template.Render()
+		default:
+			payload = pw.payloadPool.GetSize(pw.sizes.Next(pw.rng))
+			pw.filler.Fill(payload)
+		}
+
+		// Checksum the payload before the send is timed
+		var checksum string
+		if pw.checksummer != nil {
+			checksum = pw.checksummer.Sum(payload)
+		}
+
+		// Pick the topic of the message
+		topic := 0
+		if pw.topicChoice != nil {
+			topic = pw.topicChoice.Next(pw.rng)
+		}
+
+		// Build the message before the send is timed, so rendering properties does not count
+		// as send latency
+		var msg *pulsarclient.ProducerMessage
+		if pw.replay != nil || pw.properties != nil || len(pw.keys) > 0 || pw.config.Producer.SendTimestamps || checksum != "" || pw.topicChoice != nil {
+			msg = &pulsarclient.ProducerMessage{Payload: payload}
+			switch {
+			case pw.replay != nil:
+				msg.Key = record.Key
+				msg.Properties = record.Properties
+			case len(pw.keys) > 0:
+				msg.Key = pw.keys[pw.sent%uint64(len(pw.keys))]
+			}
+			if pw.properties != nil || pw.config.Producer.SendTimestamps || checksum != "" {
+				msg.Properties = pw.messageProperties(msg.Properties, checksum)
+			}
+		}
 
 		// Send message and measure latency
 		sendStart := time.Now()
-		_, err := pw.client.Send(workCtx, payload)
+		var msgID pulsarclient.MessageID
+		var err error
+		if msg != nil {
+			if pw.config.Producer.SendTimestamps {
+				msg.Properties[pulsar.SendTimeProperty] = pulsar.FormatSendTime(sendStart)
+			}
+			msgID, err = pw.client.SendMessageTo(workCtx, topic, msg)
+		} else {
+			msgID, err = pw.client.Send(workCtx, payload)
+		}
 		sendLatency := time.Since(sendStart)
 
-		// Return buffer to pool
-		pw.payloadPool.Put(payload)
+		// Return buffer to pool; replayed records and the template renderer's buffer are
+		// not pooled
+		if pw.replay == nil && pw.template == nil {
+			pw.payloadPool.Put(payload)
+		}
 
 		if err != nil {
 			// Check if context was cancelled (not a real failure)
@@ -126,9 +257,41 @@ func (pw *ProducerWorker) Start(ctx context.Context) error {
 
 		// Record metrics
 		pw.collector.RecordSend(len(payload), sendLatency)
+		if pw.chunkSize > 0 && len(payload) > pw.chunkSize {
+			pw.collector.RecordChunkedSend(len(payload))
+		}
+		if pw.config.TrackPartitions() {
+			pw.collector.RecordPartitionSend(msgID.PartitionIdx(), len(payload), sendLatency)
+		}
+		if pw.topicChoice != nil {
+			pw.collector.RecordTopicSend(pw.topics[topic], len(payload), sendLatency)
+		}
+		pw.sent++
 	}
 }
 
+// messageProperties merges the configured properties and the payload checksum (if not
+// empty) into the properties of a message (replayed properties, or nil). Configured
+// properties take precedence; the result is only valid until the next call. The send
+// time is stamped by the caller right before the send.
+func (pw *ProducerWorker) messageProperties(base map[string]string, checksum string) map[string]string {
+	var properties map[string]string
+	if pw.properties != nil {
+		properties = pw.properties.Render()
+	} else {
+		properties = make(map[string]string, len(base)+1)
+	}
+	for name, value := range base {
+		if _, ok := properties[name]; !ok {
+			properties[name] = value
+		}
+	}
+	if checksum != "" {
+		properties[pulsar.ChecksumProperty] = checksum
+	}
+	return properties
+}
+
 // Stop stops the producer worker
 func (pw *ProducerWorker) Stop() error {
 	// Flush any pending messages
@@ -175,9 +338,11 @@ func (pw *ProducerWorker) SetContext(ctx context.Context, cancel context.CancelF
 	pw.cancelFunc = cancel
 }
 
-// SetPool sets the worker pool reference for pause/resume functionality
+// SetPool sets the worker pool reference for pause/resume functionality and the
+// pool's replayer
 func (pw *ProducerWorker) SetPool(pool *Pool) {
 	pw.workerPool = pool
+	pw.replay = pool.replay
 }
 
 // CancelContext cancels the worker's context, signaling it to stop
@@ -187,6 +352,11 @@ func (pw *ProducerWorker) CancelContext() {
 	}
 }
 
+// MarkStarted registers a run of the worker before the pool launches its goroutine
+func (pw *ProducerWorker) MarkStarted() {
+	pw.wg.Add(1)
+}
+
 // WaitForCompletion waits for the worker's goroutine to finish
 func (pw *ProducerWorker) WaitForCompletion() {
 	pw.wg.Wait()
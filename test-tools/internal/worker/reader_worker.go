@@ -0,0 +1,132 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pulsar-local-lab/perf-test/internal/config"
+	"github.com/pulsar-local-lab/perf-test/internal/metrics"
+	"github.com/pulsar-local-lab/perf-test/internal/pulsar"
+)
+
+// catchUpCheckInterval is how often a reader that is still catching up asks whether it
+// has reached the end of the topic
+const catchUpCheckInterval = 10 * time.Millisecond
+
+// ReaderWorker reads a topic with a reader instead of a subscription. It first catches up
+// on the messages that exist when it starts, measured separately, then keeps tail reading.
+type ReaderWorker struct {
+	receiver
+	id     int
+	client *pulsar.ReaderClient
+}
+
+// NewReaderWorker creates a new reader worker
+func NewReaderWorker(id int, cfg *config.Config, collector *metrics.Collector) (*ReaderWorker, error) {
+	// Create Pulsar reader client
+	client, err := pulsar.NewReaderClient(cfg, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reader client: %w", err)
+	}
+
+	return &ReaderWorker{
+		receiver: newReceiver(cfg, collector),
+		id:       id,
+		client:   client,
+	}, nil
+}
+
+// Start starts the reader worker.
+// End-to-end latency is only recorded for tail reads: while catching up it would be the
+// age of the backlog rather than a latency.
+func (rw *ReaderWorker) Start(ctx context.Context) error {
+	ctx = rw.workContext(ctx)
+	defer rw.wg.Done()
+
+	// Warmup period
+	if rw.config.Performance.Warmup > 0 {
+		time.Sleep(time.Duration(rw.config.Performance.Warmup))
+	}
+
+	// Main read loop
+	startTime := time.Now()
+	rw.collector.RecordCatchUpStart()
+	caughtUp := !rw.client.HasNext()
+	if caughtUp {
+		rw.collector.RecordCaughtUp(startTime)
+	}
+	lastRead, lastCheck := startTime, startTime
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		// Check duration limit
+		if rw.config.Performance.Duration > 0 &&
+			time.Since(startTime) >= time.Duration(rw.config.Performance.Duration) {
+			return nil
+		}
+
+		// Check if paused - stop reading and sleep briefly
+		if rw.paused() {
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+
+		// Read message with shorter timeout for faster shutdown response
+		readStart := time.Now()
+		readCtx, cancel := context.WithTimeout(ctx, 1*time.Second)
+		msg, err := rw.client.Next(readCtx)
+		cancel()
+
+		if err != nil {
+			// Timeout is expected at the end of the topic
+			if ctx.Err() != nil {
+				return nil
+			}
+			if !caughtUp && !rw.client.HasNext() {
+				caughtUp = true
+				rw.collector.RecordCaughtUp(lastRead)
+			}
+			continue
+		}
+		receivedAt := time.Now()
+		lastRead = receivedAt
+
+		// Record metrics, preferring the exact send time when the producer attached one
+		lag := receivedAt.Sub(msg.PublishTime())
+		if sentAt, ok := pulsar.ParseSendTime(msg.Properties()); ok {
+			lag = receivedAt.Sub(sentAt)
+		}
+		rw.collector.RecordReceive(len(msg.Payload()))
+		if caughtUp {
+			rw.collector.RecordE2ELatency(lag)
+		} else {
+			rw.collector.RecordCatchUpRead(len(msg.Payload()), receivedAt.Sub(readStart))
+		}
+		rw.collector.RecordLag(lag)
+		rw.recordMessage(msg, receivedAt, lag)
+
+		// Check whether this was the last message that existed, at most once per interval
+		if !caughtUp && receivedAt.Sub(lastCheck) >= catchUpCheckInterval {
+			lastCheck = receivedAt
+			if !rw.client.HasNext() {
+				caughtUp = true
+				rw.collector.RecordCaughtUp(receivedAt)
+			}
+		}
+	}
+}
+
+// Stop stops the reader worker
+func (rw *ReaderWorker) Stop() error {
+	return rw.client.Close()
+}
+
+// ID returns the worker ID
+func (rw *ReaderWorker) ID() int {
+	return rw.id
+}
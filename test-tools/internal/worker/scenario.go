@@ -0,0 +1,340 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pulsar-local-lab/perf-test/internal/config"
+	"github.com/pulsar-local-lab/perf-test/internal/metrics"
+)
+
+// scenarioTickInterval is how often ramping phases update the target rate
+const scenarioTickInterval = time.Second
+
+// PhaseResult holds the settings and metrics of one executed scenario phase
+type PhaseResult struct {
+	Name        string
+	StartedAt   time.Time
+	Completed   bool // false when the scenario was cancelled during the phase
+	Producers   int
+	Consumers   int
+	TargetRate  int // rate at the end of the phase (0 = unlimited)
+	MessageSize int
+	Compression string
+	Snapshot    metrics.Snapshot
+}
+
+// ScenarioRunner executes the phases of a scenario back to back, adjusting the
+// pools through their dynamic controls. Metrics are reset at the start of every
+// phase so each phase reports its own throughput and latency.
+type ScenarioRunner struct {
+	scenario  *config.Scenario
+	producers *Pool // nil for consumer-only scenarios
+	consumers *Pool // nil for producer-only scenarios
+	collector *metrics.Collector
+
+	mu      sync.RWMutex
+	results []PhaseResult
+}
+
+// NewScenarioRunner creates a runner for the given pools. Either pool may be nil:
+// without producers no phase may change producer settings, without consumers the
+// consumer counts are ignored so one scenario file also drives a producer-only run.
+func NewScenarioRunner(scenario *config.Scenario, producers, consumers *Pool) (*ScenarioRunner, error) {
+	if producers == nil && consumers == nil {
+		return nil, fmt.Errorf("scenario requires a producer or consumer pool")
+	}
+
+	for i, phase := range scenario.Phases {
+		if producers == nil && (phase.Producers > 0 || phase.Rate != nil || phase.MessageSize > 0 || phase.Compression != "") {
+			return nil, fmt.Errorf("phase %d (%s): producer settings require a producer pool", i+1, phase.Name)
+		}
+	}
+
+	collector := consumers
+	if producers != nil {
+		collector = producers
+	}
+
+	return &ScenarioRunner{
+		scenario:  scenario,
+		producers: producers,
+		consumers: consumers,
+		collector: collector.GetMetrics(),
+	}, nil
+}
+
+// Run executes all phases and returns the per-phase results. It waits for the pools to be
+// started (usually by the UI) before changing them. When ctx is cancelled the results up to
+// and including the interrupted phase are returned with ctx's error.
+func (r *ScenarioRunner) Run(ctx context.Context) ([]PhaseResult, error) {
+	if err := r.waitRunning(ctx); err != nil {
+		return nil, err
+	}
+
+	for i := range r.scenario.Phases {
+		phase := &r.scenario.Phases[i]
+		log.Printf("Scenario %s: phase %d/%d %s (%v)", r.scenario.Name, i+1, len(r.scenario.Phases), phase.Name, phase.Duration)
+
+		if err := r.applyPhase(ctx, phase); err != nil {
+			return r.Results(), fmt.Errorf("failed to apply phase %d (%s): %w", i+1, phase.Name, err)
+		}
+
+		r.collector.Reset()
+		started := time.Now()
+		err := r.runPhase(ctx, phase, started)
+		r.recordResult(phase, started, err == nil)
+		if err != nil {
+			return r.Results(), err
+		}
+	}
+
+	log.Printf("Scenario %s completed", r.scenario.Name)
+	return r.Results(), nil
+}
+
+// waitRunning blocks until all pools of the scenario are running
+func (r *ScenarioRunner) waitRunning(ctx context.Context) error {
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	for (r.producers != nil && !r.producers.IsRunning()) || (r.consumers != nil && !r.consumers.IsRunning()) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// applyPhase resizes the pools and applies the phase's producer settings
+func (r *ScenarioRunner) applyPhase(ctx context.Context, phase *config.ScenarioPhase) error {
+	if r.producers != nil {
+		cfg := r.producers.GetConfig()
+
+		// Message size and compression are fixed per producer, so changing them restarts the workers
+		restart := false
+		if phase.MessageSize > 0 && phase.MessageSize != cfg.Producer.MessageSize {
+			r.producers.UpdateMessageSize(phase.MessageSize)
+			restart = true
+		}
+		if phase.Compression != "" && phase.Compression != cfg.Producer.CompressionType {
+			r.producers.UpdateCompression(phase.Compression)
+			restart = true
+		}
+		if restart {
+			if err := r.producers.RestartWorkers(ctx); err != nil {
+				return fmt.Errorf("failed to restart producers: %w", err)
+			}
+		}
+
+		if phase.Producers > 0 {
+			err := scalePool(ctx, r.producers, phase.Producers, func(id int) (Worker, error) {
+				return NewProducerWorker(id, cfg, r.collector)
+			})
+			if err != nil {
+				return fmt.Errorf("failed to scale producers: %w", err)
+			}
+		}
+
+		// Redistribute the rate over the current workers even when it is unchanged
+		rate, ok := phase.RateAt(0)
+		if !ok {
+			rate = cfg.Performance.TargetThroughput
+		}
+		r.producers.UpdateTargetRate(rate)
+	}
+
+	if r.consumers != nil && phase.Consumers > 0 {
+		cfg := r.consumers.GetConfig()
+		err := scalePool(ctx, r.consumers, phase.Consumers, func(id int) (Worker, error) {
+			return NewReceiveWorker(id, cfg, r.collector)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scale consumers: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// runPhase waits for the phase to end, updating the target rate of ramping phases
+func (r *ScenarioRunner) runPhase(ctx context.Context, phase *config.ScenarioPhase, started time.Time) error {
+	timer := time.NewTimer(phase.Duration)
+	defer timer.Stop()
+
+	ticker := time.NewTicker(scenarioTickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			if rate, ok := phase.RateAt(phase.Duration); ok && phase.Shape == config.ShapeRamp {
+				r.producers.UpdateTargetRate(rate)
+			}
+			return nil
+		case <-ticker.C:
+			if rate, ok := phase.RateAt(time.Since(started)); ok && phase.Shape == config.ShapeRamp {
+				r.producers.UpdateTargetRate(rate)
+			}
+		}
+	}
+}
+
+// recordResult stores the metrics of a finished or interrupted phase
+func (r *ScenarioRunner) recordResult(phase *config.ScenarioPhase, started time.Time, completed bool) {
+	result := PhaseResult{
+		Name:      phase.Name,
+		StartedAt: started,
+		Completed: completed,
+		Snapshot:  r.collector.GetSnapshot(),
+	}
+	if r.producers != nil {
+		cfg := r.producers.GetConfig()
+		result.Producers = r.producers.WorkerCount()
+		result.TargetRate = cfg.Performance.TargetThroughput
+		result.MessageSize = cfg.Producer.MessageSize
+		result.Compression = cfg.Producer.CompressionType
+	}
+	if r.consumers != nil {
+		result.Consumers = r.consumers.WorkerCount()
+	}
+
+	r.mu.Lock()
+	r.results = append(r.results, result)
+	r.mu.Unlock()
+}
+
+// Results returns the results of the phases executed so far
+func (r *ScenarioRunner) Results() []PhaseResult {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	results := make([]PhaseResult, len(r.results))
+	copy(results, r.results)
+	return results
+}
+
+// scalePool adds or removes workers until the pool has the target count
+func scalePool(ctx context.Context, pool *Pool, target int, factory func(int) (Worker, error)) error {
+	for pool.WorkerCount() < target {
+		if err := pool.AddWorker(ctx, factory); err != nil {
+			return err
+		}
+	}
+	for pool.WorkerCount() > target {
+		if err := pool.RemoveWorker(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// phaseReport is the exported form of a PhaseResult
+type phaseReport struct {
+	Name             string  `json:"name"`
+	StartedAt        string  `json:"started_at"`
+	Duration         string  `json:"duration"`
+	Completed        bool    `json:"completed"`
+	Producers        int     `json:"producers"`
+	Consumers        int     `json:"consumers"`
+	TargetRate       int     `json:"target_rate"`
+	MessageSize      int     `json:"message_size"`
+	Compression      string  `json:"compression,omitempty"`
+	MessagesSent     uint64  `json:"messages_sent"`
+	MessagesReceived uint64  `json:"messages_received"`
+	MessagesFailed   uint64  `json:"messages_failed"`
+	SendRate         float64 `json:"send_rate"`
+	ReceiveRate      float64 `json:"receive_rate"`
+	LatencyP50       float64 `json:"latency_p50"`
+	LatencyP99       float64 `json:"latency_p99"`
+	LatencyMax       float64 `json:"latency_max"`
+	E2ELatencyP50    float64 `json:"e2e_latency_p50"`
+	E2ELatencyP99    float64 `json:"e2e_latency_p99"`
+}
+
+// newPhaseReport converts a result into its exported form; rates are averages over the phase
+func newPhaseReport(result PhaseResult) phaseReport {
+	s := result.Snapshot
+	report := phaseReport{
+		Name:             result.Name,
+		StartedAt:        result.StartedAt.Format(time.RFC3339),
+		Duration:         s.Elapsed.Round(time.Millisecond).String(),
+		Completed:        result.Completed,
+		Producers:        result.Producers,
+		Consumers:        result.Consumers,
+		TargetRate:       result.TargetRate,
+		MessageSize:      result.MessageSize,
+		Compression:      result.Compression,
+		MessagesSent:     s.MessagesSent,
+		MessagesReceived: s.MessagesReceived,
+		MessagesFailed:   s.MessagesFailed,
+		LatencyP50:       s.LatencyStats.P50,
+		LatencyP99:       s.LatencyStats.P99,
+		LatencyMax:       s.LatencyStats.Max,
+		E2ELatencyP50:    s.E2ELatency.P50,
+		E2ELatencyP99:    s.E2ELatency.P99,
+	}
+	if seconds := s.Elapsed.Seconds(); seconds > 0 {
+		report.SendRate = float64(s.MessagesSent) / seconds
+		report.ReceiveRate = float64(s.MessagesReceived) / seconds
+	}
+	return report
+}
+
+// ExportScenarioReport writes the per-phase results as JSON to exportPath and returns the file name
+func ExportScenarioReport(exportPath string, scenario *config.Scenario, results []PhaseResult) (string, error) {
+	if err := os.MkdirAll(exportPath, 0755); err != nil {
+		return "", fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	report := struct {
+		Scenario  string        `json:"scenario"`
+		Timestamp string        `json:"timestamp"`
+		Phases    []phaseReport `json:"phases"`
+	}{
+		Scenario:  scenario.Name,
+		Timestamp: time.Now().Format(time.RFC3339),
+		Phases:    make([]phaseReport, 0, len(results)),
+	}
+	for _, result := range results {
+		report.Phases = append(report.Phases, newPhaseReport(result))
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal scenario report: %w", err)
+	}
+
+	timestamp := time.Now().Format("20060102-150405")
+	filename := filepath.Join(exportPath, fmt.Sprintf("scenario-%s.json", timestamp))
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write scenario report: %w", err)
+	}
+
+	return filename, nil
+}
+
+// WriteScenarioSummary writes one line per phase with its average rates and latency
+func WriteScenarioSummary(w io.Writer, results []PhaseResult) {
+	fmt.Fprintf(w, "=== Scenario Phases ===\n")
+	for _, result := range results {
+		report := newPhaseReport(result)
+		status := ""
+		if !result.Completed {
+			status = " (interrupted)"
+		}
+		fmt.Fprintf(w, "  %-12s %8s  sent %.0f msg/s  recv %.0f msg/s  P99 %.2f ms  E2E P99 %.2f ms%s\n",
+			report.Name, report.Duration, report.SendRate, report.ReceiveRate, report.LatencyP99, report.E2ELatencyP99, status)
+	}
+	fmt.Fprintf(w, "=======================\n")
+}